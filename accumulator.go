@@ -0,0 +1,284 @@
+package gopenrouter
+
+import (
+	"sort"
+	"strings"
+)
+
+// ChatCompletionAccumulator assembles streamed chat completion chunks into a complete
+// ChatCompletionResponse. Feed it every chunk received from a ChatCompletionStreamReader
+// and call Response once the stream has finished, or at any point to get a snapshot of
+// the data accumulated so far.
+//
+// The zero value is ready to use. An accumulator is not safe for concurrent use.
+//
+// Example usage:
+//
+//	var acc gopenrouter.ChatCompletionAccumulator
+//	for {
+//	  chunk, err := stream.Recv()
+//	  if err == io.EOF {
+//	    break
+//	  }
+//	  if err != nil {
+//	    // handle error
+//	  }
+//	  acc.AddChunk(chunk)
+//	}
+//	response := acc.Response()
+type ChatCompletionAccumulator struct {
+	id      string
+	model   string
+	object  string
+	created int64
+	choices map[int]*chatChoiceState
+	usage   *Usage
+}
+
+// chatChoiceState holds the data accumulated for a single choice index.
+type chatChoiceState struct {
+	role         string
+	content      strings.Builder
+	finishReason string
+	logProbs     *LogProbs
+	toolCalls    []ToolCall
+}
+
+// AddChunk merges a single streamed chunk into the accumulated response.
+// Content deltas are concatenated per choice index, tool call fragments are merged
+// by their index, and the most recent finish reason and usage statistics are kept.
+func (a *ChatCompletionAccumulator) AddChunk(chunk ChatCompletionStreamResponse) {
+	if a.id == "" {
+		a.id = chunk.ID
+		a.model = chunk.Model
+		a.object = chunk.Object
+		a.created = chunk.Created
+	}
+	if a.choices == nil {
+		a.choices = make(map[int]*chatChoiceState)
+	}
+
+	for _, choice := range chunk.Choices {
+		state, ok := a.choices[choice.Index]
+		if !ok {
+			state = &chatChoiceState{}
+			a.choices[choice.Index] = state
+		}
+
+		if choice.Delta.Role != nil && *choice.Delta.Role != "" {
+			state.role = *choice.Delta.Role
+		}
+		if choice.Delta.Content != nil {
+			state.content.WriteString(*choice.Delta.Content)
+		}
+		for _, call := range choice.Delta.ToolCalls {
+			state.mergeToolCall(call)
+		}
+		if choice.FinishReason != nil && *choice.FinishReason != "" {
+			state.finishReason = *choice.FinishReason
+		}
+		state.logProbs = appendLogProbs(state.logProbs, choice.LogProbs)
+	}
+
+	if chunk.Usage != nil {
+		usage := *chunk.Usage
+		a.usage = &usage
+	}
+}
+
+// appendLogProbs appends the tokens of a streamed logprobs fragment to the accumulated
+// log probabilities, allocating them on first use.
+func appendLogProbs(dst, fragment *LogProbs) *LogProbs {
+	if fragment == nil {
+		return dst
+	}
+	if dst == nil {
+		dst = &LogProbs{}
+	}
+
+	dst.Content = append(dst.Content, fragment.Content...)
+	if fragment.Refusal != nil {
+		var refusal []TokenLogProbs
+		if dst.Refusal != nil {
+			refusal = *dst.Refusal
+		}
+		refusal = append(refusal, *fragment.Refusal...)
+		dst.Refusal = &refusal
+	}
+	return dst
+}
+
+// copyLogProbs returns a copy of the accumulated log probabilities that does not share
+// its token slices with the accumulator.
+func copyLogProbs(logProbs *LogProbs) *LogProbs {
+	if logProbs == nil {
+		return nil
+	}
+
+	result := LogProbs{
+		Content: append([]TokenLogProbs(nil), logProbs.Content...),
+	}
+	if logProbs.Refusal != nil {
+		refusal := append([]TokenLogProbs(nil), *logProbs.Refusal...)
+		result.Refusal = &refusal
+	}
+	return &result
+}
+
+// mergeToolCall merges a streamed tool call fragment into the list of tool calls.
+// Fragments are matched by index; fragments without an index continue the last tool call
+// unless they carry a new ID.
+func (s *chatChoiceState) mergeToolCall(fragment ToolCall) {
+	var target *ToolCall
+	if fragment.Index != nil {
+		for i := range s.toolCalls {
+			if s.toolCalls[i].Index != nil && *s.toolCalls[i].Index == *fragment.Index {
+				target = &s.toolCalls[i]
+				break
+			}
+		}
+	} else if len(s.toolCalls) > 0 && (fragment.ID == "" || fragment.ID == s.toolCalls[len(s.toolCalls)-1].ID) {
+		target = &s.toolCalls[len(s.toolCalls)-1]
+	}
+
+	if target == nil {
+		s.toolCalls = append(s.toolCalls, fragment)
+		return
+	}
+
+	if fragment.ID != "" {
+		target.ID = fragment.ID
+	}
+	if fragment.Type != "" {
+		target.Type = fragment.Type
+	}
+	if fragment.Function.Name != "" {
+		target.Function.Name = fragment.Function.Name
+	}
+	target.Function.Arguments += fragment.Function.Arguments
+}
+
+// Response returns the chat completion response assembled from all chunks added so far.
+// Choices are ordered by their index.
+func (a *ChatCompletionAccumulator) Response() ChatCompletionResponse {
+	response := ChatCompletionResponse{
+		ID:      a.id,
+		Model:   a.model,
+		Object:  a.object,
+		Created: a.created,
+	}
+
+	indexes := make([]int, 0, len(a.choices))
+	for index := range a.choices {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	for _, index := range indexes {
+		state := a.choices[index]
+		choice := ChatChoice{
+			Message: ChatMessage{
+				Role:    state.role,
+				Content: state.content.String(),
+			},
+			Index:        index,
+			FinishReason: state.finishReason,
+		}
+		if len(state.toolCalls) > 0 {
+			choice.Message.ToolCalls = append([]ToolCall(nil), state.toolCalls...)
+		}
+		choice.LogProbs = copyLogProbs(state.logProbs)
+		response.Choices = append(response.Choices, choice)
+	}
+
+	if a.usage != nil {
+		response.Usage = *a.usage
+	}
+
+	return response
+}
+
+// CompletionAccumulator assembles streamed completion chunks into a complete
+// CompletionResponse. It works like ChatCompletionAccumulator for chunks received
+// from a CompletionStreamReader.
+//
+// The zero value is ready to use. An accumulator is not safe for concurrent use.
+type CompletionAccumulator struct {
+	response CompletionResponse
+	choices  map[int]*completionChoiceState
+}
+
+// completionChoiceState holds the data accumulated for a single completion choice index.
+type completionChoiceState struct {
+	text               strings.Builder
+	finishReason       string
+	nativeFinishReason string
+	logProbs           *LogProbs
+}
+
+// AddChunk merges a single streamed chunk into the accumulated response.
+// Text deltas are concatenated per choice index, and the most recent finish reasons
+// and usage statistics are kept.
+func (a *CompletionAccumulator) AddChunk(chunk CompletionStreamResponse) {
+	if a.response.ID == "" {
+		a.response.ID = chunk.ID
+		a.response.Provider = chunk.Provider
+		a.response.Model = chunk.Model
+		a.response.Object = chunk.Object
+		a.response.Created = chunk.Created
+	}
+	if chunk.SystemFingerprint != nil {
+		fingerprint := *chunk.SystemFingerprint
+		a.response.SystemFingerprint = &fingerprint
+	}
+	if a.choices == nil {
+		a.choices = make(map[int]*completionChoiceState)
+	}
+
+	for _, choice := range chunk.Choices {
+		state, ok := a.choices[choice.Index]
+		if !ok {
+			state = &completionChoiceState{}
+			a.choices[choice.Index] = state
+		}
+
+		state.text.WriteString(choice.Text)
+		if choice.FinishReason != nil && *choice.FinishReason != "" {
+			state.finishReason = *choice.FinishReason
+		}
+		if choice.NativeFinishReason != nil && *choice.NativeFinishReason != "" {
+			state.nativeFinishReason = *choice.NativeFinishReason
+		}
+		state.logProbs = appendLogProbs(state.logProbs, choice.LogProbs)
+	}
+
+	if chunk.Usage != nil {
+		a.response.Usage = *chunk.Usage
+	}
+}
+
+// Response returns the completion response assembled from all chunks added so far.
+// Choices are ordered by their index.
+func (a *CompletionAccumulator) Response() CompletionResponse {
+	response := a.response
+	response.Choices = nil
+
+	indexes := make([]int, 0, len(a.choices))
+	for index := range a.choices {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	for _, index := range indexes {
+		state := a.choices[index]
+		response.Choices = append(response.Choices, CompletionChoice{
+			LogProbs:           copyLogProbs(state.logProbs),
+			FinishReason:       state.finishReason,
+			NativeFinishReason: state.nativeFinishReason,
+			Text:               state.text.String(),
+			Index:              index,
+		})
+	}
+
+	return response
+}
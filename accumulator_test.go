@@ -0,0 +1,173 @@
+package gopenrouter_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func decodeChatChunks(t *testing.T, raw []string) []gopenrouter.ChatCompletionStreamResponse {
+	t.Helper()
+
+	chunks := make([]gopenrouter.ChatCompletionStreamResponse, 0, len(raw))
+	for _, data := range raw {
+		var chunk gopenrouter.ChatCompletionStreamResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatalf("Failed to decode chunk %s: %v", data, err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func TestChatCompletionAccumulator(t *testing.T) {
+	t.Run("ContentAndUsage", func(t *testing.T) {
+		chunks := decodeChatChunks(t, []string{
+			`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"openai/gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"},"finish_reason":null,"logprobs":{"content":[{"token":"Hello","bytes":[72,101,108,108,111],"logprob":-0.8,"top_logprobs":[]}]}}]}`,
+			`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":" there"},"finish_reason":null,"logprobs":{"content":[{"token":" there","bytes":[32,116,104,101,114,101],"logprob":-0.2,"top_logprobs":[]}]}}]}`,
+			`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"!"},"finish_reason":"stop"}]}`,
+			`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{},"finish_reason":null}],"usage":{"prompt_tokens":5,"completion_tokens":3,"total_tokens":8}}`,
+		})
+
+		var acc gopenrouter.ChatCompletionAccumulator
+		for _, chunk := range chunks {
+			acc.AddChunk(chunk)
+		}
+		response := acc.Response()
+
+		if response.ID != "chatcmpl-1" {
+			t.Errorf("Expected ID 'chatcmpl-1', got '%s'", response.ID)
+		}
+		if response.Model != "openai/gpt-4o" || response.Object != "chat.completion.chunk" || response.Created != 1700000000 {
+			t.Errorf("Expected the metadata of the first chunk, got model '%s', object '%s', and created %d",
+				response.Model, response.Object, response.Created)
+		}
+		if len(response.Choices) != 1 {
+			t.Fatalf("Expected 1 choice, got %d", len(response.Choices))
+		}
+		choice := response.Choices[0]
+		if choice.Message.Role != "assistant" {
+			t.Errorf("Expected role 'assistant', got '%s'", choice.Message.Role)
+		}
+		if choice.Message.Content != "Hello there!" {
+			t.Errorf("Expected content 'Hello there!', got '%s'", choice.Message.Content)
+		}
+		if choice.FinishReason != "stop" {
+			t.Errorf("Expected finish reason 'stop', got '%s'", choice.FinishReason)
+		}
+		if choice.LogProbs == nil || len(choice.LogProbs.Content) != 2 {
+			t.Errorf("Expected 2 accumulated logprob tokens, got %v", choice.LogProbs)
+		}
+		if response.Usage.TotalTokens != 8 {
+			t.Errorf("Expected total tokens 8, got %d", response.Usage.TotalTokens)
+		}
+	})
+
+	t.Run("MultipleChoices", func(t *testing.T) {
+		chunks := decodeChatChunks(t, []string{
+			`{"id":"chatcmpl-2","choices":[{"index":1,"delta":{"role":"assistant","content":"B"}},{"index":0,"delta":{"role":"assistant","content":"A"}}]}`,
+			`{"id":"chatcmpl-2","choices":[{"index":0,"delta":{"content":"1"},"finish_reason":"stop"},{"index":1,"delta":{"content":"2"},"finish_reason":"length"}]}`,
+		})
+
+		var acc gopenrouter.ChatCompletionAccumulator
+		for _, chunk := range chunks {
+			acc.AddChunk(chunk)
+		}
+		response := acc.Response()
+
+		if len(response.Choices) != 2 {
+			t.Fatalf("Expected 2 choices, got %d", len(response.Choices))
+		}
+		if response.Choices[0].Index != 0 || response.Choices[0].Message.Content != "A1" {
+			t.Errorf("Unexpected first choice: %+v", response.Choices[0])
+		}
+		if response.Choices[1].Index != 1 || response.Choices[1].Message.Content != "B2" {
+			t.Errorf("Unexpected second choice: %+v", response.Choices[1])
+		}
+		if response.Choices[1].FinishReason != "length" {
+			t.Errorf("Expected finish reason 'length', got '%s'", response.Choices[1].FinishReason)
+		}
+	})
+
+	t.Run("ToolCalls", func(t *testing.T) {
+		chunks := decodeChatChunks(t, []string{
+			`{"id":"chatcmpl-3","choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+			`{"id":"chatcmpl-3","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}`,
+			`{"id":"chatcmpl-3","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}},{"index":1,"id":"call_2","type":"function","function":{"name":"get_time","arguments":"{}"}}]}}]}`,
+			`{"id":"chatcmpl-3","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		})
+
+		var acc gopenrouter.ChatCompletionAccumulator
+		for _, chunk := range chunks {
+			acc.AddChunk(chunk)
+		}
+		response := acc.Response()
+
+		if len(response.Choices) != 1 {
+			t.Fatalf("Expected 1 choice, got %d", len(response.Choices))
+		}
+		calls := response.Choices[0].Message.ToolCalls
+		if len(calls) != 2 {
+			t.Fatalf("Expected 2 tool calls, got %d", len(calls))
+		}
+		if calls[0].ID != "call_1" || calls[0].Function.Name != "get_weather" {
+			t.Errorf("Unexpected first tool call: %+v", calls[0])
+		}
+		if calls[0].Function.Arguments != `{"city":"Paris"}` {
+			t.Errorf("Expected merged arguments, got '%s'", calls[0].Function.Arguments)
+		}
+		if calls[1].ID != "call_2" || calls[1].Function.Name != "get_time" {
+			t.Errorf("Unexpected second tool call: %+v", calls[1])
+		}
+		if response.Choices[0].FinishReason != "tool_calls" {
+			t.Errorf("Expected finish reason 'tool_calls', got '%s'", response.Choices[0].FinishReason)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		var acc gopenrouter.ChatCompletionAccumulator
+		response := acc.Response()
+
+		if response.ID != "" || len(response.Choices) != 0 {
+			t.Errorf("Expected empty response, got %+v", response)
+		}
+	})
+}
+
+func TestCompletionAccumulator(t *testing.T) {
+	raw := []string{
+		`{"id":"cmpl-1","provider":"OpenAI","model":"test-model","object":"text_completion","created":1700000000,"choices":[{"index":0,"text":"Once","finish_reason":null},{"index":1,"text":"In","finish_reason":null}]}`,
+		`{"id":"cmpl-1","choices":[{"index":1,"text":" a land","finish_reason":"length","native_finish_reason":"max_tokens"}]}`,
+		`{"id":"cmpl-1","choices":[{"index":0,"text":" upon","finish_reason":"stop","logprobs":{"content":[{"token":" upon","bytes":[32,117,112,111,110],"logprob":-0.1,"top_logprobs":[]}]}}],"usage":{"prompt_tokens":4,"completion_tokens":4,"total_tokens":8}}`,
+	}
+
+	var acc gopenrouter.CompletionAccumulator
+	for _, data := range raw {
+		var chunk gopenrouter.CompletionStreamResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatalf("Failed to decode chunk %s: %v", data, err)
+		}
+		acc.AddChunk(chunk)
+	}
+
+	response := acc.Response()
+	if response.ID != "cmpl-1" || response.Provider != "OpenAI" || response.Model != "test-model" {
+		t.Errorf("Expected metadata from the first chunk, got %+v", response)
+	}
+	if len(response.Choices) != 2 {
+		t.Fatalf("Expected 2 choices, got %d", len(response.Choices))
+	}
+	if response.Choices[0].Text != "Once upon" || response.Choices[0].FinishReason != "stop" {
+		t.Errorf("Expected 'Once upon' with finish reason 'stop', got '%s' with '%s'", response.Choices[0].Text, response.Choices[0].FinishReason)
+	}
+	if response.Choices[0].LogProbs == nil || len(response.Choices[0].LogProbs.Content) != 1 {
+		t.Errorf("Expected 1 accumulated logprob token, got %v", response.Choices[0].LogProbs)
+	}
+	if response.Choices[1].Text != "In a land" || response.Choices[1].NativeFinishReason != "max_tokens" {
+		t.Errorf("Expected 'In a land' with native finish reason 'max_tokens', got '%s' with '%s'", response.Choices[1].Text, response.Choices[1].NativeFinishReason)
+	}
+	if response.Usage.TotalTokens != 8 {
+		t.Errorf("Expected 8 total tokens, got %d", response.Usage.TotalTokens)
+	}
+}
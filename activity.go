@@ -0,0 +1,103 @@
+package gopenrouter
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// activityDateLayout is the format of the dates of activity rows.
+const activityDateLayout = "2006-01-02"
+
+// activityResponse represents the internal API response structure when retrieving activity.
+type activityResponse struct {
+	Data []ActivityData `json:"data"`
+}
+
+// ActivityData contains the usage of a model through one provider endpoint on one day.
+type ActivityData struct {
+	// Date is the UTC day of the activity in "YYYY-MM-DD" format
+	Date string `json:"date"`
+	// Model is the identifier of the model used
+	Model string `json:"model"`
+	// ModelPermaslug is the permanent identifier of the model version used
+	ModelPermaslug string `json:"model_permaslug"`
+	// EndpointID is the identifier of the provider endpoint that served the requests
+	EndpointID string `json:"endpoint_id"`
+	// ProviderName is the name of the provider that served the requests
+	ProviderName string `json:"provider_name"`
+	// Usage is the amount of credits consumed
+	Usage float64 `json:"usage"`
+	// BYOKUsageInference is the cost of "Bring Your Own Key" inference billed by the provider
+	BYOKUsageInference float64 `json:"byok_usage_inference"`
+	// Requests is the number of requests made
+	Requests int `json:"requests"`
+	// PromptTokens is the number of prompt tokens processed
+	PromptTokens int `json:"prompt_tokens"`
+	// CompletionTokens is the number of completion tokens generated
+	CompletionTokens int `json:"completion_tokens"`
+	// ReasoningTokens is the number of tokens used for internal reasoning
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+// ActivityOptions filters the activity returned by GetActivity by date.
+// OpenRouter reports the activity of the last 30 completed UTC days.
+type ActivityOptions struct {
+	// From is the first day to include; no lower bound is applied if zero
+	From time.Time
+	// To is the last day to include; no upper bound is applied if zero
+	To time.Time
+}
+
+// GetActivity retrieves the daily usage of the account, grouped by model and provider endpoint.
+//
+// The activity can be used to build usage dashboards or to attribute spending to models.
+// A range of a single day is filtered by OpenRouter; wider ranges are filtered locally.
+// Days are compared in UTC. Activity is only available when authenticated with a
+// provisioning key.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - options: The range of days to retrieve
+//
+// Returns:
+//   - []ActivityData: The activity rows within the range
+//   - error: Any error that occurred during the request
+func (c *Client) GetActivity(ctx context.Context, options ActivityOptions) (activity []ActivityData, err error) {
+	urlSuffix := "/activity"
+	var response activityResponse
+
+	from, to := activityDate(options.From), activityDate(options.To)
+
+	var setters []requestOption
+	if from != "" && from == to {
+		setters = append(setters, withQueryParam("date", from))
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), setters...)
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	if err != nil {
+		return
+	}
+
+	activity = response.Data[:0]
+	for _, row := range response.Data {
+		day := row.Date[:min(len(row.Date), len(activityDateLayout))]
+		if (from == "" || day >= from) && (to == "" || day <= to) {
+			activity = append(activity, row)
+		}
+	}
+	return
+}
+
+// activityDate formats the UTC day of t, or returns an empty string if t is zero.
+func activityDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(activityDateLayout)
+}
@@ -0,0 +1,131 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestClientGetActivity(t *testing.T) {
+	const body = `{"data": [
+		{"date": "2025-08-22", "model": "openai/gpt-4o", "provider_name": "OpenAI", "usage": 0.5, "byok_usage_inference": 0, "requests": 3, "prompt_tokens": 100, "completion_tokens": 50, "reasoning_tokens": 0},
+		{"date": "2025-08-23", "model": "openai/gpt-4o", "provider_name": "Azure", "usage": 1.5, "byok_usage_inference": 0.25, "requests": 7, "prompt_tokens": 300, "completion_tokens": 150, "reasoning_tokens": 10},
+		{"date": "2025-08-24", "model": "anthropic/claude-3.5-sonnet", "provider_name": "Anthropic", "usage": 2, "byok_usage_inference": 0, "requests": 4, "prompt_tokens": 200, "completion_tokens": 80, "reasoning_tokens": 0}
+	]}`
+
+	day := func(date string) time.Time {
+		t.Helper()
+		d, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	cases := []struct {
+		name        string
+		options     gopenrouter.ActivityOptions
+		expectQuery string
+		expectDates []string
+	}{
+		{
+			name:        "All",
+			options:     gopenrouter.ActivityOptions{},
+			expectDates: []string{"2025-08-22", "2025-08-23", "2025-08-24"},
+		},
+		{
+			name:        "SingleDay",
+			options:     gopenrouter.ActivityOptions{From: day("2025-08-23"), To: day("2025-08-23")},
+			expectQuery: "date=2025-08-23",
+			expectDates: []string{"2025-08-23"},
+		},
+		{
+			name:        "Range",
+			options:     gopenrouter.ActivityOptions{From: day("2025-08-23"), To: day("2025-08-24")},
+			expectDates: []string{"2025-08-23", "2025-08-24"},
+		},
+		{
+			name:        "OpenEnded",
+			options:     gopenrouter.ActivityOptions{To: day("2025-08-22")},
+			expectDates: []string{"2025-08-22"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var query string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/activity" {
+					t.Errorf("Expected path /activity, got %s", r.URL.Path)
+				}
+				query = r.URL.RawQuery
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprint(w, body)
+			}))
+			defer server.Close()
+
+			client := gopenrouter.New("provisioning-key", gopenrouter.WithBaseURL(server.URL))
+			activity, err := client.GetActivity(context.Background(), tc.options)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if query != tc.expectQuery {
+				t.Errorf("Expected query %q, got %q", tc.expectQuery, query)
+			}
+			if len(activity) != len(tc.expectDates) {
+				t.Fatalf("Expected %d rows, got %d", len(tc.expectDates), len(activity))
+			}
+			for i, row := range activity {
+				if row.Date != tc.expectDates[i] {
+					t.Errorf("Expected row %d on %s, got %s", i, tc.expectDates[i], row.Date)
+				}
+			}
+		})
+	}
+
+	t.Run("Fields", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, body)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("provisioning-key", gopenrouter.WithBaseURL(server.URL))
+		activity, err := client.GetActivity(context.Background(), gopenrouter.ActivityOptions{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		row := activity[1]
+		if row.Model != "openai/gpt-4o" || row.ProviderName != "Azure" {
+			t.Errorf("Expected openai/gpt-4o through Azure, got %s through %s", row.Model, row.ProviderName)
+		}
+		if row.Requests != 7 || row.Usage != 1.5 || row.BYOKUsageInference != 0.25 {
+			t.Errorf("Expected 7 requests, usage 1.5, and BYOK usage 0.25, got %d, %f, and %f", row.Requests, row.Usage, row.BYOKUsageInference)
+		}
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 403, "message": "Only provisioning keys can fetch activity"}}`)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("regular-key", gopenrouter.WithBaseURL(server.URL))
+		_, err := client.GetActivity(context.Background(), gopenrouter.ActivityOptions{})
+
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got %T: %v", err, err)
+		}
+	})
+}
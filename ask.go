@@ -0,0 +1,56 @@
+package gopenrouter
+
+import "context"
+
+// Ask sends a single user prompt to a model and returns the content of the response.
+// It covers the common case of a one-off question without building a request; use
+// ChatCompletion for full control over the request and access to the response metadata.
+//
+// Example usage:
+//
+//	answer, err := client.Ask(ctx, "openai/gpt-4o-mini", "What is the capital of France?")
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - model: The model to ask; empty to use the model set with WithDefaultModel
+//   - prompt: The user prompt
+//
+// Returns:
+//   - string: The content of the first choice of the response
+//   - error: Any error that occurred during the request, including ErrNoChoices
+func (c *Client) Ask(ctx context.Context, model, prompt string) (string, error) {
+	return c.Chat(ctx, model, "", prompt)
+}
+
+// Chat sends a system prompt and a user message to a model and returns the content of the
+// response. It is the counterpart of Ask for instructions that set the behavior of the
+// model.
+//
+// Example usage:
+//
+//	reply, err := client.Chat(ctx, "openai/gpt-4o-mini", "Answer in one word.", "What is the capital of France?")
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - model: The model to ask; empty to use the model set with WithDefaultModel
+//   - system: The system prompt; empty to send only the user message
+//   - user: The user message
+//
+// Returns:
+//   - string: The content of the first choice of the response
+//   - error: Any error that occurred during the request, including ErrNoChoices
+func (c *Client) Chat(ctx context.Context, model, system, user string) (content string, err error) {
+	messages := make([]ChatMessage, 0, 2)
+	if system != "" {
+		messages = append(messages, ChatMessage{Role: "system", Content: system})
+	}
+	messages = append(messages, ChatMessage{Role: "user", Content: user})
+
+	response, err := c.ChatCompletion(ctx, *NewChatCompletionRequestBuilder(model, messages).Build())
+	if err != nil {
+		return
+	}
+
+	content = response.Choices[0].Message.Content
+	return
+}
@@ -0,0 +1,76 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+func TestAsk(t *testing.T) {
+	var recorded gopenrouter.ChatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorded = gopenroutertest.DecodeChatCompletionRequest(t, r)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(gopenrouter.ChatCompletionResponse{
+			ID:      "gen-1",
+			Choices: []gopenrouter.ChatChoice{{Message: gopenrouter.ChatMessage{Role: "assistant", Content: "Paris"}}},
+		})
+	}))
+	defer server.Close()
+
+	client := gopenrouter.New("test-api-key",
+		gopenrouter.WithBaseURL(server.URL),
+		gopenrouter.WithDefaultModel("default/model"))
+	ctx := context.Background()
+
+	t.Run("Ask", func(t *testing.T) {
+		answer, err := client.Ask(ctx, "test/model", "What is the capital of France?")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if answer != "Paris" {
+			t.Errorf("Expected answer 'Paris', got '%s'", answer)
+		}
+		expected := []gopenrouter.ChatMessage{{Role: "user", Content: "What is the capital of France?"}}
+		if diff := gopenroutertest.Diff(expected, recorded.Messages); diff != "" {
+			t.Errorf("Unexpected messages:\n%s", diff)
+		}
+		if recorded.Model != "test/model" {
+			t.Errorf("Expected model 'test/model', got '%s'", recorded.Model)
+		}
+	})
+
+	t.Run("Chat", func(t *testing.T) {
+		if _, err := client.Chat(ctx, "", "Answer in one word.", "What is the capital of France?"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := []gopenrouter.ChatMessage{
+			{Role: "system", Content: "Answer in one word."},
+			{Role: "user", Content: "What is the capital of France?"},
+		}
+		if diff := gopenroutertest.Diff(expected, recorded.Messages); diff != "" {
+			t.Errorf("Unexpected messages:\n%s", diff)
+		}
+		if recorded.Model != "default/model" {
+			t.Errorf("Expected the default model, got '%s'", recorded.Model)
+		}
+	})
+
+	t.Run("NoChoices", func(t *testing.T) {
+		empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id":"gen-1","choices":[]}`))
+		}))
+		defer empty.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(empty.URL))
+		if _, err := client.Ask(ctx, "test/model", "Hello"); !errors.Is(err, gopenrouter.ErrNoChoices) {
+			t.Errorf("Expected ErrNoChoices, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,248 @@
+// Package auth implements the OAuth PKCE flow of OpenRouter, which lets users sign in
+// with their OpenRouter account and grant an application a user-scoped API key.
+//
+// The flow consists of three steps:
+//
+//	flow := auth.New()
+//	challenge, err := auth.NewChallenge()
+//	if err != nil {
+//	  // handle error
+//	}
+//	// 1. Store challenge.Verifier in the user's session and redirect the user to:
+//	redirectURL := flow.AuthorizationURL("https://yourapp.com/callback", challenge)
+//	// 2. OpenRouter redirects the user back to the callback URL with a "code" query parameter.
+//	// 3. Exchange the code for an API key using the stored verifier:
+//	key, err := flow.ExchangeCode(ctx, code, challenge)
+//	client := gopenrouter.New(key.Key)
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+const (
+	// openRouterAuthURL is the default URL of the OpenRouter authorization page.
+	openRouterAuthURL = "https://openrouter.ai/auth"
+	// openRouterAPIURL is the default base URL for the OpenRouter API.
+	openRouterAPIURL = "https://openrouter.ai/api/v1"
+	// maxResponseSize is the maximum size of the response body read by ExchangeCode.
+	maxResponseSize = 1 << 20
+)
+
+// ChallengeMethod is the method used to derive the code challenge from the code verifier.
+type ChallengeMethod string
+
+const (
+	// ChallengeMethodS256 sends the SHA-256 hash of the verifier as the challenge (recommended)
+	ChallengeMethodS256 ChallengeMethod = "S256"
+	// ChallengeMethodPlain sends the verifier itself as the challenge
+	ChallengeMethodPlain ChallengeMethod = "plain"
+)
+
+// ErrMissingCode is returned by ExchangeCode when no authorization code is given.
+var ErrMissingCode = errors.New("authorization code is required")
+
+// Challenge holds a PKCE code verifier and the code challenge derived from it.
+// The verifier must be kept secret and stored until the code is exchanged.
+type Challenge struct {
+	// Verifier is the random secret proving that the code is exchanged by the application
+	// that started the flow
+	Verifier string
+	// Challenge is the value sent to the authorization page
+	Challenge string
+	// Method is the method used to derive Challenge from Verifier
+	Method ChallengeMethod
+}
+
+// NewChallenge generates a random code verifier and its S256 code challenge.
+func NewChallenge() (Challenge, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return Challenge{}, fmt.Errorf("error generating code verifier: %w", err)
+	}
+	return ChallengeFromVerifier(base64.RawURLEncoding.EncodeToString(b[:])), nil
+}
+
+// ChallengeFromVerifier derives the S256 code challenge of a previously generated verifier,
+// such as one restored from the user's session.
+func ChallengeFromVerifier(verifier string) Challenge {
+	sum := sha256.Sum256([]byte(verifier))
+	return Challenge{
+		Verifier:  verifier,
+		Challenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+		Method:    ChallengeMethodS256,
+	}
+}
+
+// Key is the API key granted by the user.
+type Key struct {
+	// Key is the user-scoped API key, used to authenticate requests
+	Key string `json:"key"`
+	// UserID is the identifier of the user who granted the key, if reported
+	UserID string `json:"user_id,omitempty"`
+}
+
+// Flow builds authorization URLs and exchanges authorization codes for API keys.
+type Flow struct {
+	authURL    string
+	baseURL    string
+	httpClient gopenrouter.HTTPDoer
+}
+
+// Option defines an option function for modifying Flow properties.
+type Option func(*Flow)
+
+// WithAuthURL sets the URL of the authorization page users are redirected to.
+func WithAuthURL(authURL string) Option {
+	return func(f *Flow) {
+		f.authURL = authURL
+	}
+}
+
+// WithBaseURL sets the base URL of the OpenRouter API used to exchange codes.
+func WithBaseURL(baseURL string) Option {
+	return func(f *Flow) {
+		f.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to exchange codes.
+func WithHTTPClient(httpClient gopenrouter.HTTPDoer) Option {
+	return func(f *Flow) {
+		f.httpClient = httpClient
+	}
+}
+
+// New creates a PKCE flow with the provided options.
+// By default, it uses the standard OpenRouter URLs and the default HTTP client.
+func New(options ...Option) *Flow {
+	f := &Flow{
+		authURL:    openRouterAuthURL,
+		baseURL:    openRouterAPIURL,
+		httpClient: http.DefaultClient,
+	}
+	for _, option := range options {
+		option(f)
+	}
+	return f
+}
+
+// AuthorizationURL returns the URL of the page where the user signs in and grants the
+// application an API key. OpenRouter then redirects the user to callbackURL with the
+// authorization code in the "code" query parameter.
+func (f *Flow) AuthorizationURL(callbackURL string, challenge Challenge) string {
+	params := url.Values{}
+	params.Set("callback_url", callbackURL)
+	params.Set("code_challenge", challenge.Challenge)
+	if challenge.Method != "" {
+		params.Set("code_challenge_method", string(challenge.Method))
+	}
+
+	separator := "?"
+	if strings.Contains(f.authURL, "?") {
+		separator = "&"
+	}
+	return f.authURL + separator + params.Encode()
+}
+
+// exchangeRequest is the body of a code exchange request.
+type exchangeRequest struct {
+	Code                string          `json:"code"`
+	CodeVerifier        string          `json:"code_verifier,omitempty"`
+	CodeChallengeMethod ChallengeMethod `json:"code_challenge_method,omitempty"`
+}
+
+// ExchangeCode exchanges the authorization code received on the callback URL for a
+// user-scoped API key. The challenge must hold the verifier of the challenge used to
+// build the authorization URL.
+//
+// Errors returned by OpenRouter are reported as *gopenrouter.APIError, and unexpected
+// responses as *gopenrouter.RequestError. Responses larger than 1 MiB fail with
+// gopenrouter.ErrResponseTooLarge, and malformed successful responses are reported without
+// their body, which may carry the key.
+func (f *Flow) ExchangeCode(ctx context.Context, code string, challenge Challenge) (Key, error) {
+	if code == "" {
+		return Key{}, ErrMissingCode
+	}
+
+	body, err := json.Marshal(exchangeRequest{
+		Code:                code,
+		CodeVerifier:        challenge.Verifier,
+		CodeChallengeMethod: challenge.Method,
+	})
+	if err != nil {
+		return Key{}, err
+	}
+
+	requestURL := strings.TrimRight(f.baseURL, "/") + "/auth/keys"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return Key{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "gopenrouter/"+gopenrouter.Version)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return Key{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return Key{}, fmt.Errorf("error, reading response body: %w", err)
+	}
+	if len(data) > maxResponseSize {
+		return Key{}, fmt.Errorf("error, reading response body: %w", gopenrouter.ErrResponseTooLarge)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return Key{}, responseError(req, resp, data)
+	}
+
+	var key Key
+	if err := json.Unmarshal(data, &key); err != nil || key.Key == "" {
+		if err == nil {
+			err = errors.New("response contains no key")
+		}
+		// The body is left out, as it may carry the issued key
+		return Key{}, fmt.Errorf("error, decoding response with status %d: %w", resp.StatusCode, err)
+	}
+	return key, nil
+}
+
+// responseError converts an error response into an APIError, or a RequestError if the
+// body does not contain an OpenRouter error.
+func responseError(req *http.Request, resp *http.Response, body []byte) error {
+	var errRes gopenrouter.ErrorResponse
+	err := json.Unmarshal(body, &errRes)
+	if err == nil && errRes.Error != nil {
+		errRes.Error.HTTPStatus = resp.Status
+		errRes.Error.HTTPStatusCode = resp.StatusCode
+		errRes.Error.Header = resp.Header
+		return errRes.Error
+	}
+	return &gopenrouter.RequestError{
+		HTTPStatus:     resp.Status,
+		HTTPStatusCode: resp.StatusCode,
+		Err:            err,
+		Body:           body,
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		Header:         resp.Header,
+	}
+}
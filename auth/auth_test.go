@@ -0,0 +1,213 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/auth"
+)
+
+func TestNewChallenge(t *testing.T) {
+	challenge, err := auth.NewChallenge()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(challenge.Verifier) < 43 || len(challenge.Verifier) > 128 {
+		t.Errorf("Expected a verifier of 43 to 128 characters, got %d", len(challenge.Verifier))
+	}
+	sum := sha256.Sum256([]byte(challenge.Verifier))
+	if expected := base64.RawURLEncoding.EncodeToString(sum[:]); challenge.Challenge != expected {
+		t.Errorf("Expected challenge %q, got %q", expected, challenge.Challenge)
+	}
+	if challenge.Method != auth.ChallengeMethodS256 {
+		t.Errorf("Expected method S256, got %s", challenge.Method)
+	}
+
+	other, err := auth.NewChallenge()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if other.Verifier == challenge.Verifier {
+		t.Error("Expected random verifiers to differ")
+	}
+}
+
+func TestChallengeFromVerifier(t *testing.T) {
+	// Example from RFC 7636, Appendix B
+	challenge := auth.ChallengeFromVerifier("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if challenge.Challenge != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("Expected RFC 7636 challenge, got %q", challenge.Challenge)
+	}
+}
+
+func TestAuthorizationURL(t *testing.T) {
+	challenge := auth.ChallengeFromVerifier("verifier")
+
+	t.Run("Default", func(t *testing.T) {
+		flow := auth.New()
+		u, err := url.Parse(flow.AuthorizationURL("https://example.com/callback?state=1", challenge))
+		if err != nil {
+			t.Fatalf("Expected valid URL, got error %v", err)
+		}
+
+		if u.Scheme+"://"+u.Host+u.Path != "https://openrouter.ai/auth" {
+			t.Errorf("Expected OpenRouter auth page, got %s", u)
+		}
+		query := u.Query()
+		if query.Get("callback_url") != "https://example.com/callback?state=1" {
+			t.Errorf("Expected callback URL, got %q", query.Get("callback_url"))
+		}
+		if query.Get("code_challenge") != challenge.Challenge {
+			t.Errorf("Expected code challenge %q, got %q", challenge.Challenge, query.Get("code_challenge"))
+		}
+		if query.Get("code_challenge_method") != "S256" {
+			t.Errorf("Expected code challenge method S256, got %q", query.Get("code_challenge_method"))
+		}
+	})
+
+	t.Run("CustomAuthURL", func(t *testing.T) {
+		flow := auth.New(auth.WithAuthURL("https://auth.example.com/login?tenant=acme"))
+		u, err := url.Parse(flow.AuthorizationURL("https://example.com/callback", challenge))
+		if err != nil {
+			t.Fatalf("Expected valid URL, got error %v", err)
+		}
+		if u.Host != "auth.example.com" || u.Query().Get("tenant") != "acme" {
+			t.Errorf("Expected custom auth URL with its query, got %s", u)
+		}
+		if u.Query().Get("code_challenge") == "" {
+			t.Errorf("Expected code challenge, got %s", u)
+		}
+	})
+}
+
+func TestExchangeCode(t *testing.T) {
+	challenge := auth.ChallengeFromVerifier("verifier")
+
+	t.Run("Success", func(t *testing.T) {
+		var body map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/auth/keys" {
+				t.Errorf("Expected POST /auth/keys, got %s %s", r.Method, r.URL.Path)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Expected JSON body, got error %v", err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"key": "sk-or-v1-user", "user_id": "user-1"}`)
+		}))
+		defer server.Close()
+
+		flow := auth.New(auth.WithBaseURL(server.URL))
+		key, err := flow.ExchangeCode(context.Background(), "auth-code", challenge)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if body["code"] != "auth-code" || body["code_verifier"] != "verifier" || body["code_challenge_method"] != "S256" {
+			t.Errorf("Expected code, verifier, and method in body, got %v", body)
+		}
+		if key.Key != "sk-or-v1-user" || key.UserID != "user-1" {
+			t.Errorf("Expected user key and ID, got %+v", key)
+		}
+	})
+
+	t.Run("MissingCode", func(t *testing.T) {
+		flow := auth.New()
+		_, err := flow.ExchangeCode(context.Background(), "", challenge)
+		if !errors.Is(err, auth.ErrMissingCode) {
+			t.Errorf("Expected ErrMissingCode, got %v", err)
+		}
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 403, "message": "Invalid code verifier"}}`)
+		}))
+		defer server.Close()
+
+		flow := auth.New(auth.WithBaseURL(server.URL))
+		_, err := flow.ExchangeCode(context.Background(), "auth-code", challenge)
+
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got %T: %v", err, err)
+		}
+		if apiErr.HTTPStatusCode != http.StatusForbidden || apiErr.Message != "Invalid code verifier" {
+			t.Errorf("Expected 403 with message, got %d: %s", apiErr.HTTPStatusCode, apiErr.Message)
+		}
+	})
+
+	t.Run("UnexpectedResponse", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = fmt.Fprint(w, `<html>Bad Gateway</html>`)
+		}))
+		defer server.Close()
+
+		flow := auth.New(auth.WithBaseURL(server.URL))
+		_, err := flow.ExchangeCode(context.Background(), "auth-code", challenge)
+
+		var reqErr *gopenrouter.RequestError
+		if !errors.As(err, &reqErr) {
+			t.Fatalf("Expected RequestError, got %T: %v", err, err)
+		}
+	})
+
+	t.Run("MissingKey", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{}`)
+		}))
+		defer server.Close()
+
+		flow := auth.New(auth.WithBaseURL(server.URL))
+		if _, err := flow.ExchangeCode(context.Background(), "auth-code", challenge); err == nil {
+			t.Error("Expected error for response without key, got nil")
+		}
+	})
+
+	t.Run("MalformedKeyResponse", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"key": "sk-or-v1-secret", "user_id": `)
+		}))
+		defer server.Close()
+
+		flow := auth.New(auth.WithBaseURL(server.URL))
+		_, err := flow.ExchangeCode(context.Background(), "auth-code", challenge)
+		if err == nil {
+			t.Fatal("Expected error for malformed response, got nil")
+		}
+		if strings.Contains(err.Error(), "sk-or-v1-secret") {
+			t.Errorf("Expected the error not to contain the key, got %v", err)
+		}
+	})
+
+	t.Run("ResponseTooLarge", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"key": "sk-or-v1-secret", "padding": "%s"}`, strings.Repeat("x", 2<<20))
+		}))
+		defer server.Close()
+
+		flow := auth.New(auth.WithBaseURL(server.URL))
+		_, err := flow.ExchangeCode(context.Background(), "auth-code", challenge)
+		if !errors.Is(err, gopenrouter.ErrResponseTooLarge) {
+			t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package gopenrouter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxBudgetLookups is the number of generation records the budget retrieves at once.
+const maxBudgetLookups = 8
+
+// budget tracks the credits spent by a client within a window.
+type budget struct {
+	limit  float64
+	window time.Duration
+	now    func() time.Time
+	// catalog provides the pricing used to estimate the cost of requests, if set
+	catalog *ModelCatalog
+	// lookups limits the generation records retrieved at once for responses without usage
+	lookups chan struct{}
+
+	mu    sync.Mutex
+	spent float64
+	start time.Time
+}
+
+// BudgetOption configures the budget set with WithBudget.
+type BudgetOption func(*budget)
+
+// WithBudgetCatalog estimates the cost of each request from the pricing of its model in
+// catalog, and rejects requests whose estimate exceeds the remaining budget before they are
+// sent. The estimate assumes the completion uses all MaxTokens of the request, so requests
+// without MaxTokens are only checked for the cost of their prompt.
+//
+// Requests to models missing from the catalog, or whose pricing is variable, are sent as
+// long as the budget is not spent. The catalog should be backed by a client without a
+// budget, since its model listings are not completions.
+func WithBudgetCatalog(catalog *ModelCatalog) BudgetOption {
+	return func(b *budget) {
+		b.catalog = catalog
+	}
+}
+
+// WithBudget limits the credits the client may spend on completions within a window.
+//
+// The cost of each completion and stream is taken from the usage reported by OpenRouter,
+// so usage accounting is enabled on requests that do not configure it. A reported cost of
+// zero, such as for free models, is charged as is. Responses that report no usage, such
+// as streams closed before their usage chunk, are charged the cost of their generation
+// record, retrieved in the background with the GenerationLookup settings of
+// WithGenerationHandler, if set. At most 8 records are retrieved at once; responses
+// without usage completed while as many are pending are not charged.
+//
+// As the cost of a request is only known once it completes, requests are rejected with
+// ErrBudgetExceeded once the spend reaches the limit, and the last request allowed may
+// exceed it by its full cost. WithBudgetCatalog rejects requests whose estimated cost
+// exceeds the remaining budget instead. Concurrent requests are checked against the
+// spend of the requests completed so far, so together they may still exceed the budget.
+//
+// The window starts with the first request and the spend is reset once it has elapsed.
+// A window of zero never resets the spend.
+func WithBudget(limit float64, window time.Duration, opts ...BudgetOption) Option {
+	return func(c *Client) {
+		c.budget = &budget{
+			limit:   limit,
+			window:  window,
+			now:     time.Now,
+			lookups: make(chan struct{}, maxBudgetLookups),
+		}
+		for _, opt := range opts {
+			opt(c.budget)
+		}
+	}
+}
+
+// allow returns an error wrapping ErrBudgetExceeded if the budget is exhausted, or if
+// spending estimate more would exceed it.
+func (b *budget) allow(estimate float64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+	if b.spent >= b.limit {
+		return fmt.Errorf("%w: spent %g of %g credits", ErrBudgetExceeded, b.spent, b.limit)
+	}
+	if b.spent+estimate > b.limit {
+		return fmt.Errorf("%w: estimated cost %g exceeds the remaining %g of %g credits",
+			ErrBudgetExceeded, estimate, b.limit-b.spent, b.limit)
+	}
+	return nil
+}
+
+// add records the cost of a completed request.
+func (b *budget) add(cost float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+	b.spent += cost
+}
+
+// rollover starts a new window if the current one has elapsed. It must be called
+// with b.mu held.
+func (b *budget) rollover() {
+	now := b.now()
+	if b.start.IsZero() {
+		b.start = now
+		return
+	}
+	if b.window > 0 && now.Sub(b.start) >= b.window {
+		b.start = now
+		b.spent = 0
+	}
+}
+
+// estimateCost projects the cost of a request to model with estimate, using the pricing
+// of the budget catalog. It returns zero if the cost cannot be estimated.
+func (b *budget) estimateCost(ctx context.Context, model string, estimate func(ModelPricing) (CostEstimate, error)) float64 {
+	if b.catalog == nil {
+		return 0
+	}
+	data, err := b.catalog.ByID(ctx, model)
+	if err != nil {
+		return 0
+	}
+	cost, err := estimate(data.Pricing)
+	if err != nil {
+		return 0
+	}
+	return cost.TotalCost
+}
+
+// checkBudget returns an error if the budget set with WithBudget is exhausted, or cannot
+// cover the cost projected by estimate, and enables usage accounting for usage to report
+// the cost of the request.
+func (c *Client) checkBudget(ctx context.Context, model string, usage **UsageOptions, estimate func(ModelPricing) (CostEstimate, error)) error {
+	if c.budget == nil {
+		return nil
+	}
+	if err := c.budget.allow(c.budget.estimateCost(ctx, model, estimate)); err != nil {
+		return err
+	}
+	if *usage == nil {
+		include := true
+		*usage = &UsageOptions{Include: &include}
+	}
+	return nil
+}
+
+// chargeBudget records the cost of the response with the given generation ID in the
+// budget, if set. Responses that report no usage are charged the total cost of their
+// generation record, which is retrieved in the background unless maxBudgetLookups
+// retrievals are already running.
+func (c *Client) chargeBudget(ctx context.Context, id string, usage *Usage) {
+	if c.budget == nil {
+		return
+	}
+	if usage != nil {
+		c.budget.add(usage.Cost)
+		return
+	}
+	if id == "" {
+		return
+	}
+	select {
+	case c.budget.lookups <- struct{}{}:
+	default:
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer func() { <-c.budget.lookups }()
+		data, err := c.lookupGeneration(ctx, id, c.generationLookup())
+		if err == nil {
+			c.budget.add(data.TotalCost)
+		}
+	}()
+}
@@ -0,0 +1,277 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestWithBudget(t *testing.T) {
+	ctx := context.Background()
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	// newBudgetServer serves completions costing 0.4 credits, checking that usage
+	// accounting is enabled.
+	newBudgetServer := func(t *testing.T, requests *atomic.Int32) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			var body struct {
+				Stream bool `json:"stream"`
+				Usage  struct {
+					Include bool `json:"include"`
+				} `json:"usage"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			if !body.Usage.Include {
+				t.Error("Expected usage accounting to be enabled")
+			}
+			if body.Stream {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = w.Write([]byte(
+					"data: {\"id\":\"gen-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n" +
+						"data: {\"id\":\"gen-1\",\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":1,\"total_tokens\":4,\"cost\":0.4}}\n\n" +
+						"data: [DONE]\n\n"))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"gen-1","choices":[{"message":{"role":"assistant","content":"Hi"},"text":"Hi"}],` +
+				`"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4,"cost":0.4}}`))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	t.Run("Exceeded", func(t *testing.T) {
+		var requests atomic.Int32
+		server := newBudgetServer(t, &requests)
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithBudget(1, 0))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		for i := range 3 {
+			if _, err := client.ChatCompletion(ctx, *request); err != nil {
+				t.Fatalf("Unexpected error on request %d: %v", i+1, err)
+			}
+		}
+		if _, err := client.ChatCompletion(ctx, *request); !errors.Is(err, gopenrouter.ErrBudgetExceeded) {
+			t.Errorf("Expected ErrBudgetExceeded, got %v", err)
+		}
+		completion := gopenrouter.NewCompletionRequestBuilder("openai/gpt-4o", "Hello").Build()
+		if _, err := client.Completion(ctx, *completion); !errors.Is(err, gopenrouter.ErrBudgetExceeded) {
+			t.Errorf("Expected ErrBudgetExceeded from Completion, got %v", err)
+		}
+		if _, err := client.CompletionStream(ctx, *completion); !errors.Is(err, gopenrouter.ErrBudgetExceeded) {
+			t.Errorf("Expected ErrBudgetExceeded from CompletionStream, got %v", err)
+		}
+		if n := requests.Load(); n != 3 {
+			t.Errorf("Expected 3 requests, got %d", n)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		var requests atomic.Int32
+		server := newBudgetServer(t, &requests)
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithBudget(0.5, 0))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		for range 2 {
+			stream, err := client.ChatCompletionStream(ctx, *request)
+			if err != nil {
+				if errors.Is(err, gopenrouter.ErrBudgetExceeded) {
+					break
+				}
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for {
+				if _, err := stream.Recv(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+			_ = stream.Close()
+		}
+		if _, err := client.ChatCompletionStream(ctx, *request); !errors.Is(err, gopenrouter.ErrBudgetExceeded) {
+			t.Errorf("Expected ErrBudgetExceeded, got %v", err)
+		}
+		if n := requests.Load(); n != 2 {
+			t.Errorf("Expected 2 requests, got %d", n)
+		}
+	})
+
+	t.Run("StreamUsageCountedOnce", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.Header().Set("Content-Type", "text/event-stream")
+			usage := "data: {\"id\":\"gen-1\",\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":1,\"total_tokens\":4,\"cost\":0.4}}\n\n"
+			_, _ = w.Write([]byte(usage + usage + "data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithBudget(0.5, 0))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		stream, err := client.ChatCompletionStream(ctx, *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		_ = stream.Close()
+
+		stream, err = client.ChatCompletionStream(ctx, *request)
+		if err != nil {
+			t.Fatalf("Expected the repeated usage to be counted once, got %v", err)
+		}
+		_ = stream.Close()
+	})
+
+	t.Run("Estimate", func(t *testing.T) {
+		var requests atomic.Int32
+		server := newBudgetServer(t, &requests)
+		catalogServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"openai/gpt-4o","pricing":{"prompt":"0","completion":"0.001"}}]}`))
+		}))
+		defer catalogServer.Close()
+		catalog := gopenrouter.NewModelCatalog(gopenrouter.New("test-key", gopenrouter.WithBaseURL(catalogServer.URL)))
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithBudget(1, 0, gopenrouter.WithBudgetCatalog(catalog)))
+
+		small := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).WithMaxTokens(500).Build()
+		if _, err := client.ChatCompletion(ctx, *small); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		// 0.4 credits spent, leaving 0.6 for a request estimated at 0.7
+		large := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).WithMaxTokens(700).Build()
+		if _, err := client.ChatCompletion(ctx, *large); !errors.Is(err, gopenrouter.ErrBudgetExceeded) {
+			t.Errorf("Expected ErrBudgetExceeded, got %v", err)
+		}
+		if n := requests.Load(); n != 1 {
+			t.Errorf("Expected 1 request, got %d", n)
+		}
+		unknown := gopenrouter.NewChatCompletionRequestBuilder("unknown/model", messages).WithMaxTokens(700).Build()
+		if _, err := client.ChatCompletion(ctx, *unknown); err != nil {
+			t.Errorf("Expected requests to unknown models to be sent, got %v", err)
+		}
+	})
+
+	t.Run("GenerationFallback", func(t *testing.T) {
+		var lookups atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path == "/generation" {
+				lookups.Add(1)
+				if id := r.URL.Query().Get("id"); id != "gen-1" {
+					t.Errorf("Expected generation gen-1, got %q", id)
+				}
+				_, _ = w.Write([]byte(`{"data":{"id":"gen-1","total_cost":0.4}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"gen-1","choices":[{"message":{"role":"assistant","content":"Hi"}}]}`))
+		}))
+		defer server.Close()
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithBudget(0.4, 0),
+			gopenrouter.WithGenerationHandler(
+				func(context.Context, string, gopenrouter.GenerationData, error) {},
+				gopenrouter.GenerationLookup{Delay: time.Millisecond},
+			))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		if _, err := client.ChatCompletion(ctx, *request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		deadline := time.Now().Add(time.Second)
+		for {
+			_, err := client.ChatCompletion(ctx, *request)
+			if errors.Is(err, gopenrouter.ErrBudgetExceeded) {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected the cost of the generation to be charged, got %v", err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if lookups.Load() == 0 {
+			t.Error("Expected the generation to be looked up")
+		}
+	})
+
+	t.Run("ZeroCost", func(t *testing.T) {
+		var lookups atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path == "/generation" {
+				lookups.Add(1)
+				_, _ = w.Write([]byte(`{"data":{"id":"gen-1","total_cost":0.4}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"gen-1","choices":[{"message":{"role":"assistant","content":"Hi"}}],` +
+				`"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4,"cost":0}}`))
+		}))
+		defer server.Close()
+		var handled atomic.Int32
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithBudget(0.4, 0),
+			gopenrouter.WithGenerationHandler(
+				func(context.Context, string, gopenrouter.GenerationData, error) { handled.Add(1) },
+				gopenrouter.GenerationLookup{Delay: time.Millisecond},
+			))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o:free", messages).Build()
+
+		for range 3 {
+			if _, err := client.ChatCompletion(ctx, *request); err != nil {
+				t.Fatalf("Expected free responses not to be charged, got %v", err)
+			}
+		}
+		deadline := time.Now().Add(time.Second)
+		for handled.Load() < 3 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		// Only the generation handler looks the responses up, not the budget
+		if n := lookups.Load(); n != 3 {
+			t.Errorf("Expected 3 lookups, got %d", n)
+		}
+		if _, err := client.ChatCompletion(ctx, *request); err != nil {
+			t.Errorf("Expected free responses not to be charged, got %v", err)
+		}
+	})
+
+	t.Run("WindowReset", func(t *testing.T) {
+		var requests atomic.Int32
+		server := newBudgetServer(t, &requests)
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithBudget(0.4, 50*time.Millisecond))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		if _, err := client.ChatCompletion(ctx, *request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := client.ChatCompletion(ctx, *request); !errors.Is(err, gopenrouter.ErrBudgetExceeded) {
+			t.Errorf("Expected ErrBudgetExceeded, got %v", err)
+		}
+		time.Sleep(60 * time.Millisecond)
+		if _, err := client.ChatCompletion(ctx, *request); err != nil {
+			t.Errorf("Expected budget to reset after the window, got %v", err)
+		}
+	})
+}
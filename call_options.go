@@ -0,0 +1,133 @@
+package gopenrouter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// CallOption configures a single API call, allowing one client to send requests
+// with differing metadata. Call options take precedence over the client's settings.
+type CallOption func(*callOptions)
+
+// callOptions holds the per-call overrides applied to an HTTP request.
+type callOptions struct {
+	header    http.Header
+	params    url.Values
+	body      map[string]any
+	siteURL   *string
+	siteTitle *string
+}
+
+// WithCallHeader adds a header sent with this call only. Call headers take precedence
+// over the headers set with WithHeader and ContextWithHeader, replacing all values of a
+// header of the same name.
+func WithCallHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// WithCallQueryParam adds a query parameter to the URL of this call.
+func WithCallQueryParam(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.params == nil {
+			o.params = make(url.Values)
+		}
+		o.params.Add(key, value)
+	}
+}
+
+// WithCallBodyField sets a top-level field of the JSON request body, overriding the
+// value produced from the request struct. This allows sending parameters the library
+// does not model yet. A nil value removes the field from the body.
+func WithCallBodyField(key string, value any) CallOption {
+	return func(o *callOptions) {
+		if o.body == nil {
+			o.body = make(map[string]any)
+		}
+		o.body[key] = value
+	}
+}
+
+// WithCallSiteURL overrides the site URL sent in the HTTP-Referer header for this call.
+// An empty URL omits the header.
+func WithCallSiteURL(siteURL string) CallOption {
+	return func(o *callOptions) {
+		o.siteURL = &siteURL
+	}
+}
+
+// WithCallSiteTitle overrides the site title sent in the X-Title header for this call.
+// An empty title omits the header.
+func WithCallSiteTitle(siteTitle string) CallOption {
+	return func(o *callOptions) {
+		o.siteTitle = &siteTitle
+	}
+}
+
+// withCallOptions applies the given call options to an HTTP request.
+func withCallOptions(opts []CallOption) requestOption {
+	return func(args *requestOptions) {
+		if len(opts) == 0 {
+			return
+		}
+		call := &callOptions{}
+		for _, opt := range opts {
+			opt(call)
+		}
+		for key, values := range call.params {
+			args.params[key] = append(args.params[key], values...)
+		}
+		args.call = call
+	}
+}
+
+// overrideBody merges the body overrides into the marshaled JSON request body.
+func (o *callOptions) overrideBody(body []byte) ([]byte, error) {
+	if len(o.body) == 0 {
+		return body, nil
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range o.body {
+		if value == nil {
+			delete(fields, key)
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = raw
+	}
+	return json.Marshal(fields)
+}
+
+// setHeaders sets the per-call headers, overriding the headers set by the client.
+func (o *callOptions) setHeaders(req *http.Request) {
+	if o.siteURL != nil {
+		setOrDelete(req.Header, "HTTP-Referer", *o.siteURL)
+	}
+	if o.siteTitle != nil {
+		setOrDelete(req.Header, "X-Title", *o.siteTitle)
+	}
+	for key, values := range o.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+}
+
+// setOrDelete sets the header to value, or removes it if value is empty.
+func setOrDelete(header http.Header, key, value string) {
+	if value == "" {
+		header.Del(key)
+		return
+	}
+	header.Set(key, value)
+}
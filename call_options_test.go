@@ -0,0 +1,199 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestCallOptions(t *testing.T) {
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	// recordedRequest holds the parts of the last request received by the server
+	type recordedRequest struct {
+		header http.Header
+		query  url.Values
+		body   map[string]any
+	}
+
+	// newServer returns a server recording the last request and responding with response
+	newServer := func(recorded *recordedRequest, contentType, response string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorded.header = r.Header.Clone()
+			recorded.query = r.URL.Query()
+			recorded.body = nil
+			if err := json.NewDecoder(r.Body).Decode(&recorded.body); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			w.Header().Set("Content-Type", contentType)
+			_, _ = w.Write([]byte(response))
+		}))
+	}
+
+	t.Run("ChatCompletion", func(t *testing.T) {
+		var recorded recordedRequest
+		server := newServer(&recorded, "application/json", `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithSiteURL("https://example.com"),
+			gopenrouter.WithSiteTitle("Example"),
+		)
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).
+			WithTemperature(0.5).
+			Build()
+
+		_, err := client.ChatCompletion(context.Background(), *request,
+			gopenrouter.WithCallHeader("X-Tenant", "acme"),
+			gopenrouter.WithCallQueryParam("trace", "1"),
+			gopenrouter.WithCallBodyField("temperature", 0.9),
+			gopenrouter.WithCallBodyField("custom_param", "value"),
+			gopenrouter.WithCallSiteURL("https://acme.example.com"),
+			gopenrouter.WithCallSiteTitle(""),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := recorded.header.Get("X-Tenant"); got != "acme" {
+			t.Errorf("Expected X-Tenant to be 'acme', got '%s'", got)
+		}
+		if got := recorded.header.Get("HTTP-Referer"); got != "https://acme.example.com" {
+			t.Errorf("Expected HTTP-Referer to be 'https://acme.example.com', got '%s'", got)
+		}
+		if _, ok := recorded.header["X-Title"]; ok {
+			t.Errorf("Expected no X-Title header, got '%s'", recorded.header.Get("X-Title"))
+		}
+		if got := recorded.query.Get("trace"); got != "1" {
+			t.Errorf("Expected trace query parameter to be '1', got '%s'", got)
+		}
+		if got := recorded.body["temperature"]; got != 0.9 {
+			t.Errorf("Expected temperature to be 0.9, got %v", got)
+		}
+		if got := recorded.body["custom_param"]; got != "value" {
+			t.Errorf("Expected custom_param to be 'value', got %v", got)
+		}
+		if got := recorded.body["model"]; got != "test-model" {
+			t.Errorf("Expected model to be 'test-model', got %v", got)
+		}
+	})
+
+	t.Run("RemoveBodyField", func(t *testing.T) {
+		var recorded recordedRequest
+		server := newServer(&recorded, "application/json", `{"id":"cmpl-1","choices":[{"text":"Hi","index":0}]}`)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "Hello").
+			WithMaxTokens(10).
+			Build()
+
+		_, err := client.Completion(context.Background(), *request, gopenrouter.WithCallBodyField("max_tokens", nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, ok := recorded.body["max_tokens"]; ok {
+			t.Errorf("Expected max_tokens to be removed, got %v", recorded.body["max_tokens"])
+		}
+	})
+
+	t.Run("DoNotAffectOtherCalls", func(t *testing.T) {
+		var recorded recordedRequest
+		server := newServer(&recorded, "application/json", `{"id":"cmpl-1","choices":[{"text":"Hi","index":0}]}`)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithSiteTitle("Example"),
+		)
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "Hello").Build()
+
+		_, err := client.Completion(context.Background(), *request, gopenrouter.WithCallSiteTitle("Other"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := recorded.header.Get("X-Title"); got != "Other" {
+			t.Errorf("Expected X-Title to be 'Other', got '%s'", got)
+		}
+
+		_, err = client.Completion(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := recorded.header.Get("X-Title"); got != "Example" {
+			t.Errorf("Expected X-Title to be 'Example', got '%s'", got)
+		}
+	})
+
+	t.Run("HeaderPrecedence", func(t *testing.T) {
+		var recorded recordedRequest
+		server := newServer(&recorded, "application/json", `{"id":"cmpl-1","choices":[{"text":"Hi","index":0}]}`)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithHeader("X-Client", "client"),
+			gopenrouter.WithHeader("X-Context", "client"),
+			gopenrouter.WithHeader("X-Call", "client"),
+		)
+		ctx := gopenrouter.ContextWithHeader(context.Background(), "X-Context", "context")
+		ctx = gopenrouter.ContextWithHeader(ctx, "X-Call", "context")
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "Hello").Build()
+
+		if _, err := client.Completion(ctx, *request, gopenrouter.WithCallHeader("X-Call", "call")); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for key, expected := range map[string]string{"X-Client": "client", "X-Context": "context", "X-Call": "call"} {
+			if got := recorded.header.Values(key); len(got) != 1 || got[0] != expected {
+				t.Errorf("Expected %s to be %q, got %v", key, expected, got)
+			}
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		var recorded recordedRequest
+		server := newServer(&recorded, "text/event-stream", "data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\ndata: [DONE]\n\n")
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request,
+			gopenrouter.WithCallHeader("X-Tenant", "acme"),
+			gopenrouter.WithCallBodyField("custom_param", "value"),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer stream.Close()
+
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		if got := recorded.header.Get("X-Tenant"); got != "acme" {
+			t.Errorf("Expected X-Tenant to be 'acme', got '%s'", got)
+		}
+		if got := recorded.header.Get("Accept"); got != "text/event-stream" {
+			t.Errorf("Expected Accept to be 'text/event-stream', got '%s'", got)
+		}
+		if got := recorded.body["custom_param"]; got != "value" {
+			t.Errorf("Expected custom_param to be 'value', got %v", got)
+		}
+		if got := recorded.body["stream"]; got != true {
+			t.Errorf("Expected stream to be true, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,260 @@
+package gopenrouter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCatalogTTL is the duration for which a model catalog serves cached data.
+const defaultCatalogTTL = time.Hour
+
+// ModelCatalog caches the list of models and their endpoints in memory, since the list
+// is large and changes rarely. Cached data is refreshed on access once it is older than
+// the TTL, or periodically with StartBackgroundRefresh. If a refresh fails while cached
+// data is available, the stale data keeps being served and the error is reported to the
+// handler set with WithCatalogRefreshErrorHandler.
+//
+// A ModelCatalog is safe for concurrent use.
+type ModelCatalog struct {
+	client         *Client
+	ttl            time.Duration
+	options        ListModelsOptions
+	onRefreshError func(error)
+
+	// refreshMu serializes refreshes, so concurrent callers share a single request
+	refreshMu sync.Mutex
+
+	mu        sync.RWMutex
+	models    []ModelData
+	byID      map[string]int
+	fetched   time.Time
+	endpoints map[string]catalogEndpoints
+}
+
+// catalogEndpoints holds the cached endpoints of a model.
+type catalogEndpoints struct {
+	data    EndpointData
+	fetched time.Time
+}
+
+// CatalogOption configures a ModelCatalog.
+type CatalogOption func(*ModelCatalog)
+
+// WithCatalogTTL sets the duration for which cached data is served before being
+// refreshed. The default is one hour.
+func WithCatalogTTL(ttl time.Duration) CatalogOption {
+	return func(m *ModelCatalog) {
+		m.ttl = ttl
+	}
+}
+
+// WithCatalogListOptions sets the filters applied when listing the models of the catalog.
+func WithCatalogListOptions(options ListModelsOptions) CatalogOption {
+	return func(m *ModelCatalog) {
+		m.options = options
+	}
+}
+
+// WithCatalogRefreshErrorHandler sets a function called with the errors of refreshes
+// that fell back to stale data, including background refreshes.
+func WithCatalogRefreshErrorHandler(fn func(error)) CatalogOption {
+	return func(m *ModelCatalog) {
+		m.onRefreshError = fn
+	}
+}
+
+// NewModelCatalog creates a model catalog backed by the given client.
+// No request is sent until the catalog is first accessed.
+func NewModelCatalog(client *Client, opts ...CatalogOption) *ModelCatalog {
+	m := &ModelCatalog{
+		client:    client,
+		ttl:       defaultCatalogTTL,
+		endpoints: make(map[string]catalogEndpoints),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Models returns all models of the catalog, refreshing them if the cache is stale.
+// The returned slice must not be modified.
+func (m *ModelCatalog) Models(ctx context.Context) ([]ModelData, error) {
+	if err := m.ensureFresh(ctx); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.models, nil
+}
+
+// ByID returns the model with the given ID, or ErrModelNotFound if the catalog has no such model.
+func (m *ModelCatalog) ByID(ctx context.Context, id string) (ModelData, error) {
+	if err := m.ensureFresh(ctx); err != nil {
+		return ModelData{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	i, ok := m.byID[id]
+	if !ok {
+		return ModelData{}, fmt.Errorf("%w: %s", ErrModelNotFound, id)
+	}
+	return m.models[i], nil
+}
+
+// SupportsParameter reports whether the model with the given ID supports a request
+// parameter, such as "tools" or "response_format".
+func (m *ModelCatalog) SupportsParameter(ctx context.Context, id, param string) (bool, error) {
+	model, err := m.ByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return model.SupportsParameters(param), nil
+}
+
+// ContextLength returns the maximum number of tokens the model with the given ID can
+// process, or zero if unknown.
+func (m *ModelCatalog) ContextLength(ctx context.Context, id string) (int, error) {
+	model, err := m.ByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return modelContextLength(model), nil
+}
+
+// modelContextLength returns the context length of the model, falling back to that of
+// its top provider, or zero if unknown.
+func modelContextLength(model ModelData) int {
+	switch {
+	case model.ContextLength != nil:
+		return int(*model.ContextLength)
+	case model.TopProvider.ContextLength != nil:
+		return int(*model.TopProvider.ContextLength)
+	default:
+		return 0
+	}
+}
+
+// Endpoints returns the provider endpoints of the model with the given ID, in
+// "author/slug" format, fetching them if they are not cached or stale.
+func (m *ModelCatalog) Endpoints(ctx context.Context, id string) (EndpointData, error) {
+	m.mu.RLock()
+	cached, ok := m.endpoints[id]
+	m.mu.RUnlock()
+	if ok && time.Since(cached.fetched) < m.ttl {
+		return cached.data, nil
+	}
+
+	author, slug, found := strings.Cut(id, "/")
+	if !found {
+		return EndpointData{}, fmt.Errorf("invalid model ID %q: expected author/slug", id)
+	}
+	data, err := m.client.ListEndpoints(ctx, author, slug)
+	if err != nil {
+		if ok {
+			m.reportRefreshError(err)
+			return cached.data, nil
+		}
+		return EndpointData{}, err
+	}
+
+	m.mu.Lock()
+	m.endpoints[id] = catalogEndpoints{data: data, fetched: time.Now()}
+	m.mu.Unlock()
+	return data, nil
+}
+
+// Refresh fetches the list of models, replacing the cached data. Cached endpoints are
+// kept until they expire.
+func (m *ModelCatalog) Refresh(ctx context.Context) error {
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+	return m.refresh(ctx)
+}
+
+// StartBackgroundRefresh refreshes the list of models once per TTL until ctx is done,
+// so that accesses are never delayed by a refresh.
+func (m *ModelCatalog) StartBackgroundRefresh(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.ttl)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := m.Refresh(ctx); err != nil && ctx.Err() == nil {
+					m.reportRefreshError(err)
+				}
+			}
+		}
+	}()
+}
+
+// ensureFresh refreshes the list of models if it is missing or stale. Stale data is kept
+// if the refresh fails.
+func (m *ModelCatalog) ensureFresh(ctx context.Context) error {
+	if m.fresh() {
+		return nil
+	}
+
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+	// Another caller may have refreshed the data while waiting for the lock
+	if m.fresh() {
+		return nil
+	}
+
+	err := m.refresh(ctx)
+	if err == nil {
+		return nil
+	}
+	m.mu.RLock()
+	hasData := m.models != nil
+	m.mu.RUnlock()
+	if !hasData {
+		return err
+	}
+	m.reportRefreshError(err)
+	return nil
+}
+
+// fresh reports whether the cached list of models is present and younger than the TTL.
+func (m *ModelCatalog) fresh() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.models != nil && time.Since(m.fetched) < m.ttl
+}
+
+// refresh fetches the list of models. The caller must hold refreshMu.
+func (m *ModelCatalog) refresh(ctx context.Context) error {
+	models, err := m.client.ListModelsWithOptions(ctx, m.options)
+	if err != nil {
+		return err
+	}
+	if models == nil {
+		models = []ModelData{}
+	}
+
+	byID := make(map[string]int, len(models))
+	for i, model := range models {
+		byID[model.ID] = i
+	}
+
+	m.mu.Lock()
+	m.models = models
+	m.byID = byID
+	m.fetched = time.Now()
+	m.mu.Unlock()
+	return nil
+}
+
+// reportRefreshError passes the error of a refresh to the error handler, if set.
+func (m *ModelCatalog) reportRefreshError(err error) {
+	if m.onRefreshError != nil {
+		m.onRefreshError(err)
+	}
+}
@@ -0,0 +1,200 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// catalogServer serves a fixed list of models and endpoints, counting the requests and
+// failing them while failing is set.
+type catalogServer struct {
+	*httptest.Server
+	modelRequests    atomic.Int32
+	endpointRequests atomic.Int32
+	failing          atomic.Bool
+}
+
+func newCatalogServer(t *testing.T) *catalogServer {
+	t.Helper()
+	s := &catalogServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if s.failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 500, "message": "Internal error"}}`)
+			return
+		}
+		switch r.URL.Path {
+		case "/models":
+			s.modelRequests.Add(1)
+			_, _ = fmt.Fprint(w, `{"data": [
+				{"id": "openai/gpt-4o", "context_length": 128000, "supported_parameters": ["tools", "response_format"]},
+				{"id": "meta-llama/llama-3-8b", "top_provider": {"context_length": 8192}, "supported_parameters": ["temperature"]},
+				{"id": "unknown/context"}
+			]}`)
+		case "/models/openai/gpt-4o/endpoints":
+			s.endpointRequests.Add(1)
+			_, _ = fmt.Fprint(w, `{"data": {"id": "openai/gpt-4o", "endpoints": [{"provider_name": "OpenAI"}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 404, "message": "Not found"}}`)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestModelCatalog(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Lookups", func(t *testing.T) {
+		server := newCatalogServer(t)
+		catalog := gopenrouter.NewModelCatalog(gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)))
+
+		models, err := catalog.Models(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(models) != 3 {
+			t.Errorf("Expected 3 models, got %d", len(models))
+		}
+
+		model, err := catalog.ByID(ctx, "openai/gpt-4o")
+		if err != nil || model.ID != "openai/gpt-4o" {
+			t.Errorf("Expected openai/gpt-4o, got %q with error %v", model.ID, err)
+		}
+		if _, err := catalog.ByID(ctx, "missing/model"); !errors.Is(err, gopenrouter.ErrModelNotFound) {
+			t.Errorf("Expected ErrModelNotFound, got %v", err)
+		}
+
+		supported, err := catalog.SupportsParameter(ctx, "openai/gpt-4o", "tools")
+		if err != nil || !supported {
+			t.Errorf("Expected tools to be supported, got %t with error %v", supported, err)
+		}
+		supported, err = catalog.SupportsParameter(ctx, "meta-llama/llama-3-8b", "tools")
+		if err != nil || supported {
+			t.Errorf("Expected tools to be unsupported, got %t with error %v", supported, err)
+		}
+
+		for id, expect := range map[string]int{"openai/gpt-4o": 128000, "meta-llama/llama-3-8b": 8192, "unknown/context": 0} {
+			length, err := catalog.ContextLength(ctx, id)
+			if err != nil || length != expect {
+				t.Errorf("Expected context length %d for %s, got %d with error %v", expect, id, length, err)
+			}
+		}
+
+		if n := server.modelRequests.Load(); n != 1 {
+			t.Errorf("Expected a single models request, got %d", n)
+		}
+	})
+
+	t.Run("ConcurrentAccess", func(t *testing.T) {
+		server := newCatalogServer(t)
+		catalog := gopenrouter.NewModelCatalog(gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)))
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := catalog.ByID(ctx, "openai/gpt-4o"); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if n := server.modelRequests.Load(); n != 1 {
+			t.Errorf("Expected concurrent accesses to share a single request, got %d", n)
+		}
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		server := newCatalogServer(t)
+		var refreshErrs atomic.Int32
+		catalog := gopenrouter.NewModelCatalog(
+			gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)),
+			gopenrouter.WithCatalogTTL(20*time.Millisecond),
+			gopenrouter.WithCatalogRefreshErrorHandler(func(error) { refreshErrs.Add(1) }),
+		)
+
+		if _, err := catalog.Models(ctx); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		time.Sleep(30 * time.Millisecond)
+		if _, err := catalog.Models(ctx); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if n := server.modelRequests.Load(); n != 2 {
+			t.Errorf("Expected stale data to be refreshed, got %d requests", n)
+		}
+
+		// Stale data is served if the refresh fails
+		server.failing.Store(true)
+		time.Sleep(30 * time.Millisecond)
+		models, err := catalog.Models(ctx)
+		if err != nil || len(models) != 3 {
+			t.Errorf("Expected stale models, got %d with error %v", len(models), err)
+		}
+		if refreshErrs.Load() != 1 {
+			t.Errorf("Expected the refresh error to be reported, got %d reports", refreshErrs.Load())
+		}
+	})
+
+	t.Run("InitialFailure", func(t *testing.T) {
+		server := newCatalogServer(t)
+		server.failing.Store(true)
+		catalog := gopenrouter.NewModelCatalog(gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)))
+
+		if _, err := catalog.Models(ctx); err == nil {
+			t.Error("Expected error without cached data, got nil")
+		}
+	})
+
+	t.Run("Endpoints", func(t *testing.T) {
+		server := newCatalogServer(t)
+		catalog := gopenrouter.NewModelCatalog(gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)))
+
+		for range 2 {
+			data, err := catalog.Endpoints(ctx, "openai/gpt-4o")
+			if err != nil || len(data.Endpoints) != 1 {
+				t.Errorf("Expected 1 endpoint, got %d with error %v", len(data.Endpoints), err)
+			}
+		}
+		if n := server.endpointRequests.Load(); n != 1 {
+			t.Errorf("Expected endpoints to be cached, got %d requests", n)
+		}
+		if _, err := catalog.Endpoints(ctx, "invalid"); err == nil {
+			t.Error("Expected error for invalid model ID, got nil")
+		}
+	})
+
+	t.Run("BackgroundRefresh", func(t *testing.T) {
+		server := newCatalogServer(t)
+		catalog := gopenrouter.NewModelCatalog(
+			gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)),
+			gopenrouter.WithCatalogTTL(10*time.Millisecond),
+		)
+
+		refreshCtx, cancel := context.WithCancel(ctx)
+		catalog.StartBackgroundRefresh(refreshCtx)
+		deadline := time.Now().Add(time.Second)
+		for server.modelRequests.Load() < 2 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		cancel()
+
+		if n := server.modelRequests.Load(); n < 2 {
+			t.Errorf("Expected periodic refreshes, got %d requests", n)
+		}
+	})
+}
@@ -1,11 +1,7 @@
 package gopenrouter
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
 	"strings"
 )
@@ -22,6 +18,8 @@ type ChatCompletionRequest struct {
 	// Optional fields
 	// Models provides an alternate list of models for routing overrides
 	Models []string `json:"models,omitempty"`
+	// Preset is the slug of a preset configured in the OpenRouter dashboard to apply
+	Preset string `json:"preset,omitempty"`
 	// Provider contains preferences for provider routing
 	Provider *ProviderOptions `json:"provider,omitempty"`
 	// Reasoning configures model reasoning/thinking tokens
@@ -30,8 +28,12 @@ type ChatCompletionRequest struct {
 	Usage *UsageOptions `json:"usage,omitempty"`
 	// Transforms lists prompt transformations (OpenRouter-only feature)
 	Transforms []string `json:"transforms,omitempty"`
+	// Plugins lists the OpenRouter plugins enabled for the request, such as web search
+	Plugins []Plugin `json:"plugins,omitempty"`
 	// Stream enables streaming of results as they are generated
 	Stream *bool `json:"stream,omitempty"`
+	// StreamOptions configures streaming behavior, such as including a trailing usage chunk
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
 	// MaxTokens limits the maximum number of tokens in the response
 	MaxTokens *int `json:"max_tokens,omitempty"`
 	// Temperature controls randomness in generation (range: [0, 2])
@@ -59,9 +61,50 @@ type ChatCompletionRequest struct {
 	// Logprobs enables returning log probabilities of output tokens
 	Logprobs *bool `json:"logprobs,omitempty"`
 	// Stop specifies sequences where the model will stop generating tokens
-	Stop []string `json:"stop,omitempty"`
+	Stop Stop `json:"stop,omitempty"`
 	// User is a stable identifier for end-users, used to help detect and prevent abuse
 	User *string `json:"user,omitempty"`
+	// WebSearchOptions configures the native web search of models supporting it
+	WebSearchOptions *WebSearchOptions `json:"web_search_options,omitempty"`
+}
+
+// Clone returns a deep copy of the chat completion request, so that a base request can be
+// reused across calls and modified without affecting it.
+func (r *ChatCompletionRequest) Clone() *ChatCompletionRequest {
+	return cloneRequest(r)
+}
+
+// Apply returns a copy of the chat completion request whose fields are replaced by those
+// set in overrides, leaving the request unchanged. Fields left at their zero value in
+// overrides are kept, so fields cannot be unset with Apply; modify a Clone instead.
+//
+// Example usage:
+//
+//	request := base.Apply(gopenrouter.ChatCompletionRequest{Messages: messages})
+func (r *ChatCompletionRequest) Apply(overrides ChatCompletionRequest) *ChatCompletionRequest {
+	return applyOverrides(r, overrides)
+}
+
+// SearchContextSize represents the amount of search results retrieved by the native
+// web search of a model. Larger sizes improve answers at a higher cost.
+type SearchContextSize string
+
+const (
+	// SearchContextLow retrieves the least context, at the lowest cost
+	SearchContextLow SearchContextSize = "low"
+
+	// SearchContextMedium retrieves a moderate amount of context
+	SearchContextMedium SearchContextSize = "medium"
+
+	// SearchContextHigh retrieves the most context, at the highest cost
+	SearchContextHigh SearchContextSize = "high"
+)
+
+// WebSearchOptions configures the native web search of models supporting it, such as
+// the OpenAI search models, without the web search plugin.
+type WebSearchOptions struct {
+	// SearchContextSize determines how much search context is retrieved
+	SearchContextSize SearchContextSize `json:"search_context_size,omitempty"`
 }
 
 // ChatMessage represents a single message in a conversation.
@@ -71,15 +114,49 @@ type ChatMessage struct {
 	Role string `json:"role"`
 	// Content is the text content of the message
 	Content string `json:"content"`
+	// ToolCalls contains the tool calls requested by the model (assistant messages only)
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// ToolCall represents a tool (function) invocation requested by the model.
+type ToolCall struct {
+	// Index is the position of the tool call in the list, used to merge streamed fragments
+	Index *int `json:"index,omitempty"`
+	// ID is the unique identifier of the tool call
+	ID string `json:"id,omitempty"`
+	// Type is the type of the tool, typically "function"
+	Type string `json:"type,omitempty"`
+	// Function contains the name of the function and its JSON-encoded arguments
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall contains the name and arguments of a function the model wants to call.
+type FunctionCall struct {
+	// Name is the name of the function to call
+	Name string `json:"name,omitempty"`
+	// Arguments contains the JSON-encoded arguments, which may arrive in fragments when streaming
+	Arguments string `json:"arguments,omitempty"`
 }
 
 // ChatCompletionResponse represents the response from a chat completion request.
 // It contains the generated messages and metadata about the request.
 type ChatCompletionResponse struct {
+	httpHeader
+
 	// ID is the unique identifier for this chat completion request
 	ID string `json:"id"`
+	// Provider is the name of the AI provider that generated the response
+	Provider string `json:"provider,omitempty"`
+	// Model is the name of the model that generated the response
+	Model string `json:"model,omitempty"`
+	// Object is the object type, typically "chat.completion"
+	Object string `json:"object,omitempty"`
+	// Created is the Unix timestamp when the response was created
+	Created int64 `json:"created,omitempty"`
 	// Choices contains the generated chat message responses
 	Choices []ChatChoice `json:"choices"`
+	// SystemFingerprint is a unique identifier for the backend configuration
+	SystemFingerprint *string `json:"system_fingerprint,omitempty"`
 	// Usage provides token usage statistics for the request
 	Usage Usage `json:"usage,omitzero"`
 }
@@ -120,6 +197,12 @@ func (b *ChatCompletionRequestBuilder) WithModels(models []string) *ChatCompleti
 	return b
 }
 
+// WithPreset sets the preset whose configuration is applied to the request.
+func (b *ChatCompletionRequestBuilder) WithPreset(slug string) *ChatCompletionRequestBuilder {
+	b.request.Preset = slug
+	return b
+}
+
 // WithProvider sets provider preferences for routing.
 func (b *ChatCompletionRequestBuilder) WithProvider(provider *ProviderOptions) *ChatCompletionRequestBuilder {
 	b.request.Provider = provider
@@ -132,6 +215,18 @@ func (b *ChatCompletionRequestBuilder) WithReasoning(reasoning *ReasoningOptions
 	return b
 }
 
+// WithWebSearchOptions configures the native web search of the model.
+func (b *ChatCompletionRequestBuilder) WithWebSearchOptions(options *WebSearchOptions) *ChatCompletionRequestBuilder {
+	b.request.WebSearchOptions = options
+	return b
+}
+
+// WithSearchContextSize sets how much context the native web search of the model retrieves.
+func (b *ChatCompletionRequestBuilder) WithSearchContextSize(size SearchContextSize) *ChatCompletionRequestBuilder {
+	b.request.WebSearchOptions = &WebSearchOptions{SearchContextSize: size}
+	return b
+}
+
 // WithUsage sets whether to include usage information in the response.
 func (b *ChatCompletionRequestBuilder) WithUsage(include bool) *ChatCompletionRequestBuilder {
 	b.request.Usage = &UsageOptions{
@@ -146,12 +241,40 @@ func (b *ChatCompletionRequestBuilder) WithTransforms(transforms []string) *Chat
 	return b
 }
 
+// WithPlugins sets the list of plugins enabled for the request.
+func (b *ChatCompletionRequestBuilder) WithPlugins(plugins []Plugin) *ChatCompletionRequestBuilder {
+	b.request.Plugins = plugins
+	return b
+}
+
+// WithPlugin adds a plugin to the list of plugins enabled for the request.
+func (b *ChatCompletionRequestBuilder) WithPlugin(plugin Plugin) *ChatCompletionRequestBuilder {
+	b.request.Plugins = append(b.request.Plugins, plugin)
+	return b
+}
+
+// WithWebSearch enables the web search plugin with the given options.
+func (b *ChatCompletionRequestBuilder) WithWebSearch(options WebPluginOptions) *ChatCompletionRequestBuilder {
+	return b.WithPlugin(WebPlugin(options))
+}
+
+// WithPDFEngine enables the file parser plugin, parsing PDFs with the given engine.
+func (b *ChatCompletionRequestBuilder) WithPDFEngine(engine PDFEngine) *ChatCompletionRequestBuilder {
+	return b.WithPlugin(FileParserPlugin(engine))
+}
+
 // WithStream enables or disables streaming for the request.
 func (b *ChatCompletionRequestBuilder) WithStream(stream bool) *ChatCompletionRequestBuilder {
 	b.request.Stream = &stream
 	return b
 }
 
+// WithStreamIncludeUsage sets whether a trailing usage chunk is sent when streaming.
+func (b *ChatCompletionRequestBuilder) WithStreamIncludeUsage(include bool) *ChatCompletionRequestBuilder {
+	b.request.StreamOptions = &StreamOptions{IncludeUsage: include}
+	return b
+}
+
 // WithMaxTokens sets the maximum number of tokens for the response.
 func (b *ChatCompletionRequestBuilder) WithMaxTokens(maxTokens int) *ChatCompletionRequestBuilder {
 	b.request.MaxTokens = &maxTokens
@@ -236,6 +359,12 @@ func (b *ChatCompletionRequestBuilder) WithStop(stop []string) *ChatCompletionRe
 	return b
 }
 
+// WithStopSequence adds a sequence to the stop sequences for token generation.
+func (b *ChatCompletionRequestBuilder) WithStopSequence(sequence string) *ChatCompletionRequestBuilder {
+	b.request.Stop = append(b.request.Stop, sequence)
+	return b
+}
+
 // WithUser sets the user identifier for the request.
 func (b *ChatCompletionRequestBuilder) WithUser(user string) *ChatCompletionRequestBuilder {
 	b.request.User = &user
@@ -247,6 +376,39 @@ func (b *ChatCompletionRequestBuilder) Build() *ChatCompletionRequest {
 	return b.request
 }
 
+// BuildE returns the constructed ChatCompletionRequest after validating it. The ranges of
+// the sampling parameters are checked, along with options that conflict with each other,
+// such as top_logprobs without logprobs, and the provider options.
+//
+// Returns:
+//   - *ChatCompletionRequest: The constructed request
+//   - error: A *ValidationError listing every invalid parameter
+func (b *ChatCompletionRequestBuilder) BuildE() (*ChatCompletionRequest, error) {
+	r := b.request
+	var v validator
+	v.check(samplingParameters{
+		provider:          r.Provider,
+		reasoning:         r.Reasoning,
+		stream:            r.Stream,
+		streamOptions:     r.StreamOptions,
+		maxTokens:         r.MaxTokens,
+		temperature:       r.Temperature,
+		topP:              r.TopP,
+		topK:              r.TopK,
+		frequencyPenalty:  r.FrequencyPenalty,
+		presencePenalty:   r.PresencePenalty,
+		repetitionPenalty: r.RepetitionPenalty,
+		topLogProbs:       r.TopLogProbs,
+		minP:              r.MinP,
+		topA:              r.TopA,
+		logprobs:          r.Logprobs,
+	})
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 // ChatStreamingChoice represents a streaming chat completion choice with delta content
 type ChatStreamingChoice struct {
 	// Index is the position of this choice in the array of choices
@@ -266,6 +428,8 @@ type ChatDelta struct {
 	Role *string `json:"role,omitempty"`
 	// Content contains the incremental text content being streamed for this chunk
 	Content *string `json:"content,omitempty"`
+	// ToolCalls contains incremental tool call fragments for this chunk
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 }
 
 // ChatCompletionStreamResponse represents a single chunk in a streaming chat completion response
@@ -284,65 +448,124 @@ type ChatCompletionStreamResponse struct {
 	Usage *Usage `json:"usage,omitempty"`
 }
 
+// Text returns the content delta carried by the chunk, concatenated across all choices.
+// For requests generating multiple choices, inspect Choices to tell them apart.
+func (r ChatCompletionStreamResponse) Text() string {
+	var text strings.Builder
+	for _, choice := range r.Choices {
+		if choice.Delta.Content != nil {
+			text.WriteString(*choice.Delta.Content)
+		}
+	}
+	return text.String()
+}
+
 // ChatCompletionStreamReader implements StreamReader for chat completion responses
 type ChatCompletionStreamReader struct {
-	reader   *bufio.Scanner
-	response *http.Response
+	stream      *streamReader[ChatCompletionStreamResponse]
+	accumulator ChatCompletionAccumulator
+	chunk       ChatCompletionStreamResponse
 }
 
 // NewChatCompletionStreamReader creates a new stream reader for chat completion responses
 func NewChatCompletionStreamReader(response *http.Response) *ChatCompletionStreamReader {
 	return &ChatCompletionStreamReader{
-		reader:   bufio.NewScanner(response.Body),
-		response: response,
+		stream: newStreamReader[ChatCompletionStreamResponse](response),
 	}
 }
 
 // Recv reads the next chat completion chunk from the stream
 func (r *ChatCompletionStreamReader) Recv() (ChatCompletionStreamResponse, error) {
-	var response ChatCompletionStreamResponse
-
-	for {
-		if !r.reader.Scan() {
-			if err := r.reader.Err(); err != nil {
-				return response, fmt.Errorf("error reading stream: %w", err)
-			}
-			return response, io.EOF
-		}
+	return r.RecvContext(context.Background())
+}
+
+// RecvContext reads the next chat completion chunk from the stream, giving up when ctx is done.
+// Unlike cancelling the request context, an expired ctx only abandons the current read:
+// the stream stays open and the next call to Recv or RecvContext returns the chunk the
+// abandoned read was waiting for. This allows per-chunk deadlines independent of the
+// deadline of the whole request.
+func (r *ChatCompletionStreamReader) RecvContext(ctx context.Context) (ChatCompletionStreamResponse, error) {
+	chunk, err := r.stream.recv(ctx)
+	if err == nil {
+		r.accumulator.AddChunk(chunk)
+	}
+	return chunk, err
+}
 
-		line := strings.TrimSpace(r.reader.Text())
+// RecvChunk reads the next chat completion chunk from the stream like RecvContext, but decodes
+// it into a chunk owned by the reader instead of allocating a new one. The returned chunk
+// is only valid until the next call to RecvChunk, which overwrites it; copy the chunk to
+// retain it. This reduces allocations when consuming high-throughput streams.
+//
+// Like RecvRaw, RecvChunk does not add its chunks to the response assembled by Final.
+func (r *ChatCompletionStreamReader) RecvChunk(ctx context.Context) (*ChatCompletionStreamResponse, error) {
+	if err := r.stream.recvInto(ctx, &r.chunk); err != nil {
+		return nil, err
+	}
+	return &r.chunk, nil
+}
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, ":") {
-			continue
-		}
+// RecvRaw reads the next chat completion chunk from the stream and returns its JSON payload
+// without decoding it. This is useful for logging, persisting, or forwarding the
+// unmodified server-sent events. Malformed chunks are returned as-is.
+func (r *ChatCompletionStreamReader) RecvRaw() ([]byte, error) {
+	return r.stream.recvRaw(context.Background())
+}
 
-		// Parse SSE data
-		if strings.HasPrefix(line, "data: ") {
-			data := strings.TrimPrefix(line, "data: ")
+// RawChunk returns the JSON payload of the most recently received chunk,
+// or nil if no chunk has been received yet
+func (r *ChatCompletionStreamReader) RawChunk() []byte {
+	return r.stream.lastRaw
+}
 
-			// Check for stream end
-			if data == "[DONE]" {
-				return response, io.EOF
-			}
+// LastEventID returns the ID of the most recent server-sent event, if the server provides one
+func (r *ChatCompletionStreamReader) LastEventID() string {
+	return r.stream.lastEventID
+}
 
-			// Parse JSON chunk
-			if err := json.Unmarshal([]byte(data), &response); err != nil {
-				// Skip malformed chunks
-				continue
-			}
+// Done reports whether the [DONE] sentinel has been received, meaning the stream finished
+// cleanly. Streams cut short before the sentinel end with ErrStreamTruncated instead
+func (r *ChatCompletionStreamReader) Done() bool {
+	return r.stream.finished
+}
 
-			return response, nil
-		}
-	}
+// Final returns the chat completion response assembled from all chunks received with Recv
+// or RecvContext so far. Once Done reports true, it holds the complete response
+func (r *ChatCompletionStreamReader) Final() ChatCompletionResponse {
+	return r.accumulator.Response()
+}
+
+// SkippedChunks returns the number of chunks skipped by Recv because they could not be decoded
+func (r *ChatCompletionStreamReader) SkippedChunks() int {
+	return r.stream.skipped
+}
+
+// DroppedChunks returns the number of chunks discarded because the read-ahead buffer
+// was full, which can only happen with the StreamBufferDropOldest policy
+func (r *ChatCompletionStreamReader) DroppedChunks() int {
+	return r.stream.droppedChunks()
+}
+
+// Metrics returns performance measurements for the stream, such as time to first token
+// and token throughput. Metrics are final once the stream has finished or been closed
+func (r *ChatCompletionStreamReader) Metrics() StreamMetrics {
+	return r.stream.metrics.snapshot()
+}
+
+// Header returns the headers of the HTTP response carrying the stream
+func (r *ChatCompletionStreamReader) Header() http.Header {
+	return r.stream.header()
+}
+
+// RequestID returns the identifier assigned to the request by OpenRouter, which can be
+// referenced when reporting issues to OpenRouter support
+func (r *ChatCompletionStreamReader) RequestID() string {
+	return requestID(r.stream.header())
 }
 
 // Close closes the chat completion stream reader
 func (r *ChatCompletionStreamReader) Close() error {
-	if r.response != nil && r.response.Body != nil {
-		return r.response.Body.Close()
-	}
-	return nil
+	return r.stream.close()
 }
 
 // ChatCompletion sends a chat completion request to the OpenRouter API.
@@ -351,20 +574,34 @@ func (r *ChatCompletionStreamReader) Close() error {
 // OpenRouter API. The request can be customized with various parameters to control
 // the generation process, provider selection, and output format.
 //
-// The method takes a context for cancellation and timeout control, and a ChatCompletionRequest
-// containing the conversation messages and generation parameters.
+// The method takes a context for cancellation and timeout control, a ChatCompletionRequest
+// containing the conversation messages and generation parameters, and optional call options
+// overriding headers, query parameters, body fields, or attribution for this call.
 //
 // Returns a ChatCompletionResponse containing the generated messages and usage statistics,
-// or an error if the request fails.
+// or an error if the request fails. If the response contains no choices, it is returned
+// along with ErrNoChoices.
 func (c *Client) ChatCompletion(
 	ctx context.Context,
 	request ChatCompletionRequest,
+	opts ...CallOption,
 ) (response ChatCompletionResponse, err error) {
 	if request.Stream != nil && *request.Stream {
 		err = ErrCompletionStreamNotSupported
 		return
 	}
 
+	c.defaults.applyChat(&request)
+	if err = c.validateParameters(ctx, request.Model, request); err != nil {
+		return
+	}
+	c.checkProviderPreferences(ctx, request.Model, request.Provider)
+	if err = c.checkBudget(ctx, request.Model, &request.Usage, func(pricing ModelPricing) (CostEstimate, error) {
+		return EstimateChatCompletionCost(pricing, request, nil)
+	}); err != nil {
+		return
+	}
+
 	urlSuffix := "/chat/completions"
 
 	req, err := c.newRequest(
@@ -372,22 +609,74 @@ func (c *Client) ChatCompletion(
 		http.MethodPost,
 		c.fullURL(urlSuffix),
 		withBody(request),
+		withCallOptions(opts),
 	)
 	if err != nil {
 		return
 	}
 
 	err = c.sendRequest(req, &response)
+	if err == nil {
+		model := response.Model
+		if model == "" {
+			model = request.Model
+		}
+		c.observeUsage(ctx, response.ID, model, response.Provider, response.Usage)
+		c.enrichGeneration(ctx, response.ID)
+		if len(response.Choices) == 0 {
+			err = ErrNoChoices
+		}
+	}
 	return
 }
 
+// ChatCompletionResult holds the outcome of one request sent with BatchChatCompletion.
+type ChatCompletionResult struct {
+	// Response is the chat completion response, valid if Err is nil or ErrNoChoices
+	Response ChatCompletionResponse
+	// Err is the error that occurred while completing the request, if any
+	Err error
+}
+
+// BatchChatCompletion sends many chat completion requests concurrently, for offline and
+// bulk processing workloads such as evaluations or data labeling.
+//
+// The number of requests sent at the same time is limited, by default to 8, which can be
+// changed with WithBulkConcurrency. Requests are held back while the most recent response
+// reports that the rate limit is exhausted, and each request is retried on its own: rate
+// limited requests are retried once after the requested delay, or according to the
+// policy configured with WithRetry. Failed requests do not affect the others and are
+// reported in their result. If ctx is done before all requests were started, the
+// remaining requests report the context error.
+//
+// Parameters:
+//   - ctx: The context for the requests, which can be used for cancellation and timeouts
+//   - requests: The chat completion requests to send; streaming requests are rejected
+//   - opts: Call options applied to every request
+//
+// Returns:
+//   - []ChatCompletionResult: The response or error of each request, in the order of requests
+func (c *Client) BatchChatCompletion(ctx context.Context, requests []ChatCompletionRequest, opts ...CallOption) []ChatCompletionResult {
+	results := make([]ChatCompletionResult, len(requests))
+	runConcurrently(ctx, len(requests), c.bulkConcurrency, func(ctx context.Context, i int) {
+		results[i].Err = c.callRateLimited(ctx, func(ctx context.Context) (err error) {
+			results[i].Response, err = c.ChatCompletion(ctx, requests[i], opts...)
+			return err
+		})
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+	return results
+}
+
 // ChatCompletionStream sends a streaming chat completion request to the OpenRouter API.
 //
 // This method enables real-time streaming of chat completion responses, allowing applications
 // to display partial results as they are generated by the AI model.
 //
 // The method automatically sets the stream parameter to true in the request and returns
-// a ChatCompletionStreamReader for reading the streaming chunks.
+// a ChatCompletionStreamReader for reading the streaming chunks. Call options are applied
+// to the initial request and to every reconnection attempt.
 //
 // Example usage:
 //
@@ -412,38 +701,29 @@ func (c *Client) ChatCompletion(
 func (c *Client) ChatCompletionStream(
 	ctx context.Context,
 	request ChatCompletionRequest,
+	opts ...CallOption,
 ) (*ChatCompletionStreamReader, error) {
 	// Ensure stream is enabled
 	streamEnabled := true
 	request.Stream = &streamEnabled
 
-	urlSuffix := "/chat/completions"
-
-	req, err := c.newRequest(
-		ctx,
-		http.MethodPost,
-		c.fullURL(urlSuffix),
-		withBody(request),
-	)
-	if err != nil {
+	c.defaults.applyChat(&request)
+	if err := c.validateParameters(ctx, request.Model, request); err != nil {
+		return nil, err
+	}
+	c.checkProviderPreferences(ctx, request.Model, request.Provider)
+	if err := c.checkBudget(ctx, request.Model, &request.Usage, func(pricing ModelPricing) (CostEstimate, error) {
+		return EstimateChatCompletionCost(pricing, request, nil)
+	}); err != nil {
 		return nil, err
 	}
 
-	// Set accept header for streaming
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
+	urlSuffix := "/chat/completions"
 
-	resp, err := c.httpClient.Do(req)
+	stream, err := newClientStream[ChatCompletionStreamResponse](ctx, c, urlSuffix, request.Model, request, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
-		defer func() {
-			_ = resp.Body.Close()
-		}()
-		return nil, c.handleErrorResp(resp)
-	}
-
-	return NewChatCompletionStreamReader(resp), nil
+	return &ChatCompletionStreamReader{stream: stream}, nil
 }
@@ -0,0 +1,233 @@
+package gopenrouter
+
+import "context"
+
+// ChatOption sets a parameter of a chat completion request. Chat options are an
+// alternative to ChatCompletionRequestBuilder for building requests from a list of
+// parameters, as accepted by NewChatCompletionRequest and ChatCompletionWith.
+type ChatOption func(*ChatCompletionRequest)
+
+// NewChatCompletionRequest returns a chat completion request with the given options
+// applied in order, so that later options take precedence over earlier ones.
+//
+// Example usage:
+//
+//	request := gopenrouter.NewChatCompletionRequest(
+//		gopenrouter.Model("openai/gpt-4o-mini"),
+//		gopenrouter.SystemMessage("Answer in one word."),
+//		gopenrouter.UserMessage("What is the capital of France?"),
+//		gopenrouter.Temperature(0.7),
+//	)
+func NewChatCompletionRequest(opts ...ChatOption) *ChatCompletionRequest {
+	request := &ChatCompletionRequest{}
+	for _, opt := range opts {
+		opt(request)
+	}
+	return request
+}
+
+// ChatCompletionWith sends a chat completion request built from the given options.
+// It is equivalent to ChatCompletion with the request returned by NewChatCompletionRequest.
+//
+// Example usage:
+//
+//	response, err := client.ChatCompletionWith(ctx,
+//		gopenrouter.Model("openai/gpt-4o-mini"),
+//		gopenrouter.Messages(messages...),
+//		gopenrouter.Temperature(0.7),
+//	)
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - opts: The options setting the parameters of the request
+//
+// Returns:
+//   - ChatCompletionResponse: The response from the chat completion API
+//   - error: Any error that occurred during the request
+func (c *Client) ChatCompletionWith(ctx context.Context, opts ...ChatOption) (ChatCompletionResponse, error) {
+	return c.ChatCompletion(ctx, *NewChatCompletionRequest(opts...))
+}
+
+// ChatCompletionStreamWith opens a chat completion stream for a request built from the
+// given options. It is equivalent to ChatCompletionStream with the request returned by
+// NewChatCompletionRequest.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - opts: The options setting the parameters of the request
+//
+// Returns:
+//   - *ChatCompletionStreamReader: A reader for the streaming response
+//   - error: Any error that occurred while opening the stream
+func (c *Client) ChatCompletionStreamWith(ctx context.Context, opts ...ChatOption) (*ChatCompletionStreamReader, error) {
+	return c.ChatCompletionStream(ctx, *NewChatCompletionRequest(opts...))
+}
+
+// Model sets the model of the request.
+func Model(model string) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Model = model
+	}
+}
+
+// Models sets alternate models for routing overrides.
+func Models(models ...string) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Models = models
+	}
+}
+
+// Preset sets the preset whose configuration is applied to the request.
+func Preset(slug string) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Preset = slug
+	}
+}
+
+// Messages appends messages to the conversation of the request.
+func Messages(messages ...ChatMessage) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Messages = append(r.Messages, messages...)
+	}
+}
+
+// SystemMessage appends a system message to the conversation of the request.
+func SystemMessage(content string) ChatOption {
+	return Messages(ChatMessage{Role: "system", Content: content})
+}
+
+// UserMessage appends a user message to the conversation of the request.
+func UserMessage(content string) ChatOption {
+	return Messages(ChatMessage{Role: "user", Content: content})
+}
+
+// Provider sets the provider routing options of the request.
+func Provider(provider *ProviderOptions) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Provider = provider
+	}
+}
+
+// Reasoning sets the reasoning options of the request.
+func Reasoning(reasoning *ReasoningOptions) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Reasoning = reasoning
+	}
+}
+
+// IncludeUsage sets whether usage information, including cost, is included in the response.
+func IncludeUsage(include bool) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Usage = &UsageOptions{Include: &include}
+	}
+}
+
+// Plugins appends OpenRouter plugins, such as web search, to the request.
+func Plugins(plugins ...Plugin) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Plugins = append(r.Plugins, plugins...)
+	}
+}
+
+// Transforms sets the prompt transformations of the request.
+func Transforms(transforms ...string) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Transforms = transforms
+	}
+}
+
+// MaxTokens sets the maximum number of tokens for the response.
+func MaxTokens(maxTokens int) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.MaxTokens = &maxTokens
+	}
+}
+
+// Temperature sets the sampling temperature, in the range [0, 2].
+func Temperature(temperature float64) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Temperature = &temperature
+	}
+}
+
+// Seed sets the seed for deterministic outputs.
+func Seed(seed int) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Seed = &seed
+	}
+}
+
+// TopP sets the nucleus sampling parameter, in the range (0, 1].
+func TopP(topP float64) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.TopP = &topP
+	}
+}
+
+// TopK limits sampling to the K most likely tokens.
+func TopK(topK int) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.TopK = &topK
+	}
+}
+
+// FrequencyPenalty sets the frequency penalty, in the range [-2, 2].
+func FrequencyPenalty(penalty float64) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.FrequencyPenalty = &penalty
+	}
+}
+
+// PresencePenalty sets the presence penalty, in the range [-2, 2].
+func PresencePenalty(penalty float64) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.PresencePenalty = &penalty
+	}
+}
+
+// RepetitionPenalty sets the repetition penalty, in the range (0, 2].
+func RepetitionPenalty(penalty float64) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.RepetitionPenalty = &penalty
+	}
+}
+
+// MinP sets the minimum probability threshold for tokens, in the range [0, 1].
+func MinP(minP float64) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.MinP = &minP
+	}
+}
+
+// TopA sets the alternate top sampling parameter, in the range [0, 1].
+func TopA(topA float64) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.TopA = &topA
+	}
+}
+
+// Logprobs enables the log probabilities of the output tokens, returning those of the
+// topLogprobs most likely tokens at each position if positive.
+func Logprobs(topLogprobs int) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		logprobs := true
+		r.Logprobs = &logprobs
+		if topLogprobs > 0 {
+			r.TopLogProbs = &topLogprobs
+		}
+	}
+}
+
+// StopSequences sets the sequences where the model stops generating tokens.
+func StopSequences(stop ...string) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.Stop = stop
+	}
+}
+
+// User sets the stable identifier of the end-user, used to help detect and prevent abuse.
+func User(user string) ChatOption {
+	return func(r *ChatCompletionRequest) {
+		r.User = &user
+	}
+}
@@ -0,0 +1,125 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+func TestChatOptions(t *testing.T) {
+	t.Run("MatchesBuilder", func(t *testing.T) {
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+		provider := gopenrouter.NewProviderOptionsBuilder().WithSortStrategy(gopenrouter.SortPrice).Build()
+
+		got := gopenrouter.NewChatCompletionRequest(
+			gopenrouter.Model("test-model"),
+			gopenrouter.Models("fallback-model"),
+			gopenrouter.Messages(messages...),
+			gopenrouter.Provider(provider),
+			gopenrouter.IncludeUsage(true),
+			gopenrouter.MaxTokens(100),
+			gopenrouter.Temperature(0.7),
+			gopenrouter.TopP(0.9),
+			gopenrouter.TopK(40),
+			gopenrouter.Logprobs(3),
+			gopenrouter.StopSequences("END"),
+			gopenrouter.User("test-user"),
+		)
+		want := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).
+			WithModels([]string{"fallback-model"}).
+			WithProvider(provider).
+			WithUsage(true).
+			WithMaxTokens(100).
+			WithTemperature(0.7).
+			WithTopP(0.9).
+			WithTopK(40).
+			WithLogprobs(true).
+			WithTopLogprobs(3).
+			WithStop([]string{"END"}).
+			WithUser("test-user").
+			Build()
+		if diff := gopenroutertest.Diff(want, got); diff != "" {
+			t.Errorf("Expected the same request as the builder:\n%s", diff)
+		}
+	})
+
+	t.Run("Order", func(t *testing.T) {
+		request := gopenrouter.NewChatCompletionRequest(
+			gopenrouter.Temperature(0.2),
+			gopenrouter.SystemMessage("Be brief."),
+			gopenrouter.UserMessage("Hi"),
+			gopenrouter.Temperature(1),
+		)
+		expected := []gopenrouter.ChatMessage{{Role: "system", Content: "Be brief."}, {Role: "user", Content: "Hi"}}
+		if diff := gopenroutertest.Diff(expected, request.Messages); diff != "" {
+			t.Errorf("Expected messages to be appended in order:\n%s", diff)
+		}
+		if *request.Temperature != 1 {
+			t.Errorf("Expected the last temperature to win, got %v", *request.Temperature)
+		}
+	})
+
+	t.Run("ChatCompletionWith", func(t *testing.T) {
+		var recorded gopenrouter.ChatCompletionRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorded = gopenroutertest.DecodeChatCompletionRequest(t, r)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(gopenrouter.ChatCompletionResponse{
+				ID:      "gen-1",
+				Choices: []gopenrouter.ChatChoice{{Message: gopenrouter.ChatMessage{Role: "assistant", Content: "Paris"}}},
+			})
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		response, err := client.ChatCompletionWith(context.Background(),
+			gopenrouter.Model("test-model"),
+			gopenrouter.UserMessage("What is the capital of France?"),
+			gopenrouter.Temperature(0.7),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Choices[0].Message.Content != "Paris" {
+			t.Errorf("Expected content 'Paris', got '%s'", response.Choices[0].Message.Content)
+		}
+		if recorded.Model != "test-model" || recorded.Temperature == nil || *recorded.Temperature != 0.7 {
+			t.Errorf("Expected model and temperature to be sent, got %+v", recorded)
+		}
+	})
+
+	t.Run("ChatCompletionStreamWith", func(t *testing.T) {
+		stream := gopenroutertest.NewStream().
+			Chunk(gopenroutertest.ChatChunk("gen-1", "test-model", "Paris")).
+			Done()
+		server := httptest.NewServer(stream)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		reader, err := client.ChatCompletionStreamWith(context.Background(),
+			gopenrouter.Model("test-model"),
+			gopenrouter.UserMessage("What is the capital of France?"),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer reader.Close()
+
+		chunk, err := reader.Recv()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if chunk.Text() != "Paris" {
+			t.Errorf("Expected chunk 'Paris', got '%s'", chunk.Text())
+		}
+		if _, err := reader.Recv(); err != io.EOF {
+			t.Errorf("Expected io.EOF, got %v", err)
+		}
+	})
+}
@@ -3,13 +3,18 @@ package gopenrouter_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
 )
 
 func TestChatCompletionRequestBuilder(t *testing.T) {
@@ -52,6 +57,48 @@ func TestChatCompletionRequestBuilder(t *testing.T) {
 		}
 	})
 
+	t.Run("WithPreset", func(t *testing.T) {
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Test message"}}
+
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).
+			WithPreset("email-copywriter").
+			Build()
+		if request.Preset != "email-copywriter" {
+			t.Errorf("Expected preset to be 'email-copywriter', got %s", request.Preset)
+		}
+
+		data, err := json.Marshal(request)
+		if err != nil {
+			t.Fatalf("Failed to marshal request: %v", err)
+		}
+		if !strings.Contains(string(data), `"preset":"email-copywriter"`) {
+			t.Errorf("Expected preset in JSON, got %s", data)
+		}
+	})
+
+	t.Run("WithSearchContextSize", func(t *testing.T) {
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o-search-preview", nil).
+			WithSearchContextSize(gopenrouter.SearchContextHigh).
+			Build()
+
+		data, err := json.Marshal(request)
+		if err != nil {
+			t.Fatalf("Failed to marshal request: %v", err)
+		}
+		if !strings.Contains(string(data), `"web_search_options":{"search_context_size":"high"}`) {
+			t.Errorf("Expected web search options in JSON, got %s", data)
+		}
+	})
+
+	t.Run("PresetModel", func(t *testing.T) {
+		if model := gopenrouter.PresetModel("email-copywriter"); model != "@preset/email-copywriter" {
+			t.Errorf("Expected model '@preset/email-copywriter', got %s", model)
+		}
+		if model := gopenrouter.ModelWithPreset("openai/gpt-4o", "email-copywriter"); model != "openai/gpt-4o@preset/email-copywriter" {
+			t.Errorf("Expected model 'openai/gpt-4o@preset/email-copywriter', got %s", model)
+		}
+	})
+
 	t.Run("WithProviderOptions", func(t *testing.T) {
 		messages := []gopenrouter.ChatMessage{
 			{Role: "user", Content: "Test message"},
@@ -97,6 +144,20 @@ func TestChatCompletionRequestBuilder(t *testing.T) {
 		}
 	})
 
+	t.Run("WithStreamIncludeUsage", func(t *testing.T) {
+		messages := []gopenrouter.ChatMessage{
+			{Role: "user", Content: "Test message"},
+		}
+
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-3.5-turbo", messages).
+			WithStreamIncludeUsage(true).
+			Build()
+
+		if request.StreamOptions == nil || !request.StreamOptions.IncludeUsage {
+			t.Errorf("Expected stream options to include usage, got %v", request.StreamOptions)
+		}
+	})
+
 	t.Run("WithSamplingParameters", func(t *testing.T) {
 		messages := []gopenrouter.ChatMessage{
 			{Role: "user", Content: "Test sampling parameters"},
@@ -199,6 +260,13 @@ func TestChatCompletion(t *testing.T) {
 			},
 		}
 
+		// Create request
+		messages := []gopenrouter.ChatMessage{
+			{Role: "user", Content: "What is the capital of France?"},
+		}
+
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-3.5-turbo", messages).Build()
+
 		// Create test server
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if r.Method != http.MethodPost {
@@ -221,20 +289,8 @@ func TestChatCompletion(t *testing.T) {
 				t.Errorf("Expected Content-Type application/json, got %s", contentType)
 			}
 
-			// Parse request body
-			var req gopenrouter.ChatCompletionRequest
-			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-				t.Errorf("Failed to decode request body: %v", err)
-			}
-
 			// Validate request
-			if req.Model != "openai/gpt-3.5-turbo" {
-				t.Errorf("Expected model openai/gpt-3.5-turbo, got %s", req.Model)
-			}
-
-			if len(req.Messages) != 1 {
-				t.Errorf("Expected 1 message, got %d", len(req.Messages))
-			}
+			gopenroutertest.AssertRequestBody(t, r, request)
 
 			w.Header().Set("Content-Type", "application/json")
 			if err := json.NewEncoder(w).Encode(mockResponse); err != nil {
@@ -246,13 +302,6 @@ func TestChatCompletion(t *testing.T) {
 		// Create client
 		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
 
-		// Create request
-		messages := []gopenrouter.ChatMessage{
-			{Role: "user", Content: "What is the capital of France?"},
-		}
-
-		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-3.5-turbo", messages).Build()
-
 		// Make request
 		ctx := context.Background()
 		response, err := client.ChatCompletion(ctx, *request)
@@ -287,6 +336,26 @@ func TestChatCompletion(t *testing.T) {
 		}
 	})
 
+	t.Run("NoChoices", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"gen-123","choices":[]}`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		response, err := client.ChatCompletion(context.Background(), *request)
+		if !errors.Is(err, gopenrouter.ErrNoChoices) {
+			t.Fatalf("Expected ErrNoChoices, got %v", err)
+		}
+		if response.ID != "gen-123" {
+			t.Errorf("Expected response ID 'gen-123', got '%s'", response.ID)
+		}
+	})
+
 	t.Run("StreamNotSupported", func(t *testing.T) {
 		client := gopenrouter.New("test-api-key")
 
@@ -305,6 +374,68 @@ func TestChatCompletion(t *testing.T) {
 			t.Errorf("Expected ErrCompletionStreamNotSupported, got %v", err)
 		}
 	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"gen-1","provider":"OpenAI","model":"openai/gpt-4o-2024-08-06",` +
+				`"object":"chat.completion","created":1735689600,"system_fingerprint":"fp_abc123",` +
+				`"choices":[{"message":{"role":"assistant","content":"Hi"}}]}`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}).Build()
+
+		response, err := client.ChatCompletion(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Provider != "OpenAI" {
+			t.Errorf("Expected provider OpenAI, got %s", response.Provider)
+		}
+		if response.Model != "openai/gpt-4o-2024-08-06" {
+			t.Errorf("Expected model openai/gpt-4o-2024-08-06, got %s", response.Model)
+		}
+		if response.Object != "chat.completion" {
+			t.Errorf("Expected object chat.completion, got %s", response.Object)
+		}
+		if response.Created != 1735689600 {
+			t.Errorf("Expected created 1735689600, got %d", response.Created)
+		}
+		if response.SystemFingerprint == nil || *response.SystemFingerprint != "fp_abc123" {
+			t.Errorf("Expected system fingerprint fp_abc123, got %v", response.SystemFingerprint)
+		}
+	})
+
+	t.Run("UsageCost", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"gen-1","choices":[{"message":{"role":"assistant","content":"Hi"}}],` +
+				`"usage":{"prompt_tokens":10,"completion_tokens":2,"total_tokens":12,"cost":0.0012,"is_byok":true,` +
+				`"cost_details":{"upstream_inference_cost":0.012}}}`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}).
+			WithUsage(true).
+			Build()
+
+		response, err := client.ChatCompletion(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Usage.Cost != 0.0012 {
+			t.Errorf("Expected cost 0.0012, got %v", response.Usage.Cost)
+		}
+		if !response.Usage.IsBYOK {
+			t.Error("Expected IsBYOK to be true")
+		}
+		if response.Usage.CostDetails == nil || response.Usage.CostDetails.UpstreamInferenceCost != 0.012 {
+			t.Errorf("Expected upstream inference cost 0.012, got %+v", response.Usage.CostDetails)
+		}
+	})
 }
 
 func TestChatCompletionStream(t *testing.T) {
@@ -514,3 +645,142 @@ func TestChatStreamReaderClose(t *testing.T) {
 		t.Errorf("Close failed: %v", err)
 	}
 }
+
+func TestBatchChatCompletion(t *testing.T) {
+	// reply answers chat completion requests with the content of their last message
+	reply := func(w http.ResponseWriter, r *http.Request) {
+		request := gopenroutertest.DecodeChatCompletionRequest(t, r)
+		content := request.Messages[len(request.Messages)-1].Content
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(gopenrouter.ChatCompletionResponse{
+			ID:      "gen-" + content,
+			Model:   request.Model,
+			Choices: []gopenrouter.ChatChoice{{Message: gopenrouter.ChatMessage{Role: "assistant", Content: content}}},
+		})
+	}
+	batch := func(contents ...string) []gopenrouter.ChatCompletionRequest {
+		requests := make([]gopenrouter.ChatCompletionRequest, len(contents))
+		for i, content := range contents {
+			messages := []gopenrouter.ChatMessage{{Role: "user", Content: content}}
+			requests[i] = *gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+		}
+		return requests
+	}
+
+	t.Run("OrderedResults", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request := gopenroutertest.DecodeChatCompletionRequest(t, r)
+			switch request.Messages[0].Content {
+			case "fail":
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":{"code":400,"message":"Bad request"}}`))
+				return
+			case "slow":
+				time.Sleep(20 * time.Millisecond)
+			}
+			reply(w, r)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		results := client.BatchChatCompletion(context.Background(), batch("slow", "fail", "fast"))
+
+		if len(results) != 3 {
+			t.Fatalf("Expected 3 results, got %d", len(results))
+		}
+		for _, i := range []int{0, 2} {
+			if results[i].Err != nil {
+				t.Errorf("Expected no error for request %d, got %v", i, results[i].Err)
+			}
+		}
+		if content := results[0].Response.Choices[0].Message.Content; content != "slow" {
+			t.Errorf("Expected the first result to answer the first request, got '%s'", content)
+		}
+		if content := results[2].Response.Choices[0].Message.Content; content != "fast" {
+			t.Errorf("Expected the last result to answer the last request, got '%s'", content)
+		}
+		var apiErr *gopenrouter.APIError
+		if !errors.As(results[1].Err, &apiErr) || apiErr.HTTPStatusCode != http.StatusBadRequest {
+			t.Errorf("Expected 400 APIError for the failed request, got %v", results[1].Err)
+		}
+	})
+
+	t.Run("RetriesRateLimitedItems", func(t *testing.T) {
+		var limited atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request := gopenroutertest.DecodeChatCompletionRequest(t, r)
+			if request.Messages[0].Content == "limited" && limited.CompareAndSwap(false, true) {
+				w.Header().Set("Retry-After", "0.01")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"error":{"code":429,"message":"Rate limit exceeded"}}`))
+				return
+			}
+			reply(w, r)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		results := client.BatchChatCompletion(context.Background(), batch("ok", "limited"))
+
+		for i, result := range results {
+			if result.Err != nil {
+				t.Errorf("Expected request %d to succeed, got %v", i, result.Err)
+			}
+		}
+		if !limited.Load() {
+			t.Error("Expected the rate limited request to have been retried")
+		}
+	})
+
+	t.Run("BoundedConcurrency", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			reply(w, r)
+		}))
+		defer server.Close()
+
+		contents := make([]string, 12)
+		for i := range contents {
+			contents[i] = strconv.Itoa(i)
+		}
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithBulkConcurrency(3))
+		results := client.BatchChatCompletion(context.Background(), batch(contents...))
+
+		for i, result := range results {
+			if result.Err != nil || result.Response.ID != "gen-"+contents[i] {
+				t.Errorf("Expected response gen-%s, got %q and %v", contents[i], result.Response.ID, result.Err)
+			}
+		}
+		if maxInFlight.Load() > 3 {
+			t.Errorf("Expected at most 3 requests in flight, got %d", maxInFlight.Load())
+		}
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("Expected no request to be sent")
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithBulkConcurrency(1))
+		results := client.BatchChatCompletion(ctx, batch("a", "b"))
+		for i, result := range results {
+			if !errors.Is(result.Err, context.Canceled) {
+				t.Errorf("Expected context.Canceled for request %d, got %v", i, result.Err)
+			}
+		}
+	})
+}
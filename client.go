@@ -6,18 +6,44 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
 	// openRouterAPIURL is the default base URL for the OpenRouter API.
 	openRouterAPIURL = "https://openrouter.ai/api/v1"
+
+	// maxBodySnippetLength is the number of response body bytes included in decoding errors.
+	maxBodySnippetLength = 512
+
+	// maxDrainLength is the number of unread response body bytes discarded after decoding,
+	// so that the connection can be reused.
+	maxDrainLength = 4 << 10
+
+	// defaultMaxResponseSize is the default maximum size of non-streaming response bodies,
+	// well above the size of the model list.
+	defaultMaxResponseSize = 64 << 20
+
+	// maxPooledBufferSize is the capacity above which buffers are not returned to the pool,
+	// so that an occasional large error body is not retained.
+	maxPooledBufferSize = 64 << 10
 )
 
+// bufferPool holds the buffers error response bodies are read into.
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
 // Client represents the OpenRouter client for making API requests.
 // It holds API credentials and configuration for communicating with OpenRouter.
 type Client struct {
@@ -26,6 +52,76 @@ type Client struct {
 	siteURL    string
 	siteTitle  string
 	httpClient HTTPDoer
+	// authorization is the Authorization header value derived from apiKey
+	authorization string
+	// apiKeyProvider, if set, supplies the API key of each request instead of apiKey
+	apiKeyProvider func(ctx context.Context) (string, error)
+	// headers are custom headers sent with every request
+	headers http.Header
+	// userAgent identifies the client, followed by the optional userAgentSuffix
+	userAgent       string
+	userAgentSuffix string
+
+	// streamReconnects is the maximum number of reconnection attempts per stream
+	streamReconnects int
+	// streamIdleTimeout aborts streams that receive no data for this duration
+	streamIdleTimeout time.Duration
+	// streamBufferSize is the number of chunks read ahead of the consumer, if non-zero
+	streamBufferSize   int
+	streamBufferPolicy StreamBufferPolicy
+	// onDecodeError is called for stream chunks that cannot be decoded
+	onDecodeError func(raw []byte, err error)
+	// onComment is called for SSE comment lines received on streams
+	onComment func(comment string)
+
+	// defaults are applied to completion and chat completion requests
+	defaults requestDefaults
+
+	// requestTimeout is the default deadline of requests whose context has none
+	requestTimeout time.Duration
+	// bulkConcurrency limits the number of requests in flight during bulk operations
+	bulkConcurrency int
+	// maxResponseSize limits the size of non-streaming response bodies, if positive
+	maxResponseSize int64
+
+	// retryAttempts is the maximum number of attempts per request, including the first
+	retryAttempts int
+	retryPolicy   RetryPolicy
+
+	// debug dumps requests and responses when enabled
+	debug debugLogger
+
+	// middleware wrap every HTTP request, forming roundTrip together with httpClient
+	middleware []Middleware
+	roundTrip  RoundTripFunc
+	// callMiddleware wrap every API call, forming call together with the retry loop
+	callMiddleware []Middleware
+	call           RoundTripFunc
+
+	// hooks are invoked at well-defined points of the request lifecycle
+	hooks Hooks
+	// log emits structured events if a logger is configured
+	log clientLogger
+	// metrics receives request and token usage measurements, if set
+	metrics Metrics
+	// validation checks requests against the capabilities of their model, if set
+	validation *parameterValidation
+	// budget limits the credits spent on completions, if set
+	budget *budget
+	// enricher delivers the generation records of completions, if set
+	enricher *generationEnricher
+	// usageTracker sums the usage of completions, if set
+	usageTracker *UsageTracker
+	// accountProviders are the provider preferences of the account, if set
+	accountProviders *AccountProviderPreferences
+
+	// rateLimit holds the rate limit state reported by the most recent response carrying it
+	rateLimit atomic.Pointer[RateLimitInfo]
+
+	// modelsFlight and endpointsFlight share the responses of concurrent identical
+	// catalog requests
+	modelsFlight    flightGroup[modelsResponse]
+	endpointsFlight flightGroup[endpointsResponse]
 }
 
 // Option defines a client option function for modifying Client properties.
@@ -47,11 +143,19 @@ func New(apiKey string, options ...Option) *Client {
 		apiKey:     apiKey,
 		baseURL:    openRouterAPIURL,
 		httpClient: http.DefaultClient,
+		userAgent:  "gopenrouter/" + Version,
+		log:        clientLogger{levels: defaultLogLevels},
+
+		maxResponseSize: defaultMaxResponseSize,
 	}
 
 	for _, option := range options {
 		option(c)
 	}
+	if c.apiKey != "" {
+		c.authorization = "Bearer " + c.apiKey
+	}
+	c.buildRoundTrip()
 
 	return c
 }
@@ -72,6 +176,32 @@ func WithSiteTitle(siteTitle string) Option {
 	}
 }
 
+// WithAPIKeyProvider sets a function supplying the API key of each request, replacing the
+// key passed to New. It is called with the request context whenever a request is created,
+// allowing keys to be fetched from a secret manager, rotated at runtime, or chosen per
+// request. An error returned by the provider aborts the request.
+func WithAPIKeyProvider(provider func(ctx context.Context) (string, error)) Option {
+	return func(c *Client) {
+		c.apiKeyProvider = provider
+	}
+}
+
+// WithUserAgent replaces the User-Agent header sent with every request, which
+// defaults to "gopenrouter/<version>".
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier, such as "myapp/1.2.0",
+// to the User-Agent header so traffic can be attributed in proxies and analytics.
+func WithUserAgentSuffix(suffix string) Option {
+	return func(c *Client) {
+		c.userAgentSuffix = suffix
+	}
+}
+
 // WithHTTPClient sets a custom HTTP client for making requests.
 // Users can provide their own http.Client (or any HTTPDoer implementation)
 // to customize timeouts, transport settings, proxies, or add middleware for
@@ -90,12 +220,127 @@ func WithBaseURL(baseURL string) Option {
 	}
 }
 
+// WithStreamReconnect enables automatic reconnection of interrupted streams.
+// When a stream drops after the server has sent at least one event ID, the client
+// re-issues the request with the Last-Event-ID header so the server can resume the
+// generation. At most maxRetries reconnection attempts are made per stream.
+func WithStreamReconnect(maxRetries int) Option {
+	return func(c *Client) {
+		c.streamReconnects = maxRetries
+	}
+}
+
+// WithStreamIdleTimeout sets the maximum time a stream may go without receiving any data.
+// If the timeout elapses, the connection is closed and Recv returns ErrStreamStalled
+// instead of blocking indefinitely on a dead connection. A zero duration disables the watchdog.
+func WithStreamIdleTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.streamIdleTimeout = timeout
+	}
+}
+
+// WithStreamBuffer enables read-ahead buffering for streams. A background goroutine
+// reads chunks from the connection into a buffer holding up to size chunks, so slow
+// consumers don't cause upstream providers to time out the connection. The policy
+// determines whether reading pauses (StreamBufferBlock) or the oldest chunks are
+// discarded (StreamBufferDropOldest) when the buffer is full.
+func WithStreamBuffer(size int, policy StreamBufferPolicy) Option {
+	return func(c *Client) {
+		c.streamBufferSize = size
+		c.streamBufferPolicy = policy
+	}
+}
+
+// WithOnDecodeError sets a callback invoked for every stream chunk that cannot be decoded.
+// Such chunks are skipped by Recv; the callback receives the raw chunk payload and the
+// decoding error so the data loss can be logged or reported.
+func WithOnDecodeError(fn func(raw []byte, err error)) Option {
+	return func(c *Client) {
+		c.onDecodeError = fn
+	}
+}
+
+// WithOnComment sets a callback invoked for every SSE comment received on a stream.
+// OpenRouter sends comments such as "OPENROUTER PROCESSING" as keep-alives while a request
+// is being routed, which can be used to show a queued state before the first chunk arrives.
+// The comment is passed without the leading colon. When read-ahead buffering is enabled,
+// the callback runs on the buffering goroutine.
+func WithOnComment(fn func(comment string)) Option {
+	return func(c *Client) {
+		c.onComment = fn
+	}
+}
+
+// WithRequestTimeout sets a default timeout for requests whose context has no deadline.
+// For regular requests, the timeout covers the whole call, including retries and reading
+// the response. For streams, it only covers establishing the stream, so long generations
+// are not interrupted once the first response has been received.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = timeout
+	}
+}
+
+// WithMaxResponseSize sets the maximum size in bytes of the bodies of non-streaming
+// responses, including error responses, so that a misbehaving proxy returning an endless
+// body cannot exhaust memory. Larger bodies fail with ErrResponseTooLarge. The default is
+// 64 MiB; non-positive values remove the limit. Streams are not limited.
+func WithMaxResponseSize(size int64) Option {
+	return func(c *Client) {
+		c.maxResponseSize = size
+	}
+}
+
+// limitBody returns body limited to the maximum response size of the client.
+func (c *Client) limitBody(body io.Reader) io.Reader {
+	if c.maxResponseSize <= 0 {
+		return body
+	}
+	return &sizeLimitedReader{r: body, remaining: c.maxResponseSize}
+}
+
+// sizeLimitedReader reads from r until remaining bytes have been read, after which it
+// fails with ErrResponseTooLarge if r has more data.
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (n int, err error) {
+	if l.remaining <= 0 {
+		// Probe whether the body continues beyond the limit
+		var probe [1]byte
+		if n, err = l.r.Read(probe[:]); n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err = l.r.Read(p)
+	l.remaining -= int64(n)
+	return
+}
+
+// withRequestTimeout applies the client's request timeout to ctx if it has no deadline.
+func (c *Client) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.requestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.requestTimeout)
+}
+
 // requestOptions holds the configuration for an HTTP request.
 // It encapsulates request body, headers, and URL parameters.
 type requestOptions struct {
 	body   any
 	header http.Header
 	params url.Values
+	call   *callOptions
 }
 
 // requestOption defines a function that modifies requestOptions.
@@ -103,13 +348,25 @@ type requestOptions struct {
 type requestOption func(*requestOptions)
 
 // withBody sets the body for an HTTP request.
-// The body can be any value that can be marshaled to JSON or an io.Reader.
+// The body can be any value that can be marshaled to JSON, an encodedBody, or an io.Reader.
 func withBody(body any) requestOption {
 	return func(args *requestOptions) {
 		args.body = body
 	}
 }
 
+// encodedBody is a request body already marshaled to JSON, so that requests sent more
+// than once, such as the reconnections of a stream, marshal their body only once.
+type encodedBody []byte
+
+// encodeBody returns the JSON encoding of a request body.
+func encodeBody(body any) ([]byte, error) {
+	if encoded, ok := body.(encodedBody); ok {
+		return encoded, nil
+	}
+	return json.Marshal(body)
+}
+
 // withContentType sets the Content-Type header for an HTTP request.
 // This specifies the format of the request body.
 func withContentType(contentType string) requestOption {
@@ -127,9 +384,24 @@ func withQueryParam(name string, value string) requestOption {
 
 // setCommonHeaders sets common headers for all OpenRouter API requests.
 // These include authentication and attribution headers.
-func (c *Client) setCommonHeaders(req *http.Request) {
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+func (c *Client) setCommonHeaders(req *http.Request) error {
+	authorization := c.authorization
+	if c.apiKeyProvider != nil {
+		apiKey, ok := req.Context().Value(apiKeyContextKey{}).(string)
+		if !ok {
+			var err error
+			if apiKey, err = c.apiKeyProvider(req.Context()); err != nil {
+				return fmt.Errorf("error getting API key: %w", err)
+			}
+		}
+		authorization = ""
+		if apiKey != "" {
+			authorization = "Bearer " + apiKey
+		}
+	}
+
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
 	}
 
 	if c.siteURL != "" {
@@ -139,6 +411,13 @@ func (c *Client) setCommonHeaders(req *http.Request) {
 	if c.siteTitle != "" {
 		req.Header.Set("X-Title", c.siteTitle)
 	}
+
+	if userAgent := strings.TrimSpace(c.userAgent + " " + c.userAgentSuffix); userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	c.setCustomHeaders(req)
+	return nil
 }
 
 // newRequest creates a new HTTP request with the given method, URL and options.
@@ -160,12 +439,18 @@ func (c *Client) newRequest(ctx context.Context, method, requestURL string, sett
 		if v, ok := args.body.(io.Reader); ok {
 			bodyReader = v
 		} else {
-			var reqBytes []byte
-			reqBytes, err := json.Marshal(args.body)
+			reqBytes, err := encodeBody(args.body)
 			if err != nil {
 				return nil, err
 			}
-			bodyReader = bytes.NewBuffer(reqBytes)
+			if args.call != nil {
+				reqBytes, err = args.call.overrideBody(reqBytes)
+				if err != nil {
+					return nil, err
+				}
+			}
+			// A bytes.Reader lets the request be replayed on retries without copying
+			bodyReader = bytes.NewReader(reqBytes)
 		}
 	}
 
@@ -181,7 +466,12 @@ func (c *Client) newRequest(ctx context.Context, method, requestURL string, sett
 		req.Header = args.header
 	}
 
-	c.setCommonHeaders(req)
+	if err := c.setCommonHeaders(req); err != nil {
+		return nil, err
+	}
+	if args.call != nil {
+		args.call.setHeaders(req)
+	}
 
 	contentType := req.Header.Get("Content-Type")
 	if contentType == "" {
@@ -191,12 +481,78 @@ func (c *Client) newRequest(ctx context.Context, method, requestURL string, sett
 	return req, nil
 }
 
+// httpHeader holds the headers of the HTTP response a value was received in.
+// It is embedded in response types to expose the headers alongside the decoded data.
+// The headers are held by pointer so the response types embedding it stay comparable.
+type httpHeader struct {
+	header *http.Header
+}
+
+// Header returns the headers of the HTTP response.
+func (h httpHeader) Header() http.Header {
+	if h.header == nil {
+		return nil
+	}
+	return *h.header
+}
+
+// RequestID returns the identifier assigned to the request by OpenRouter or its
+// edge network, which can be referenced when reporting issues to OpenRouter support.
+// It returns an empty string if the response did not include one.
+func (h httpHeader) RequestID() string {
+	return requestID(h.Header())
+}
+
+// RateLimit returns the rate limit state reported in the X-RateLimit-* headers of the
+// HTTP response, or nil if the response did not include them.
+func (h httpHeader) RateLimit() *RateLimitInfo {
+	return parseRateLimit(h.Header(), time.Now())
+}
+
+// setHeader stores the headers of the HTTP response.
+func (h *httpHeader) setHeader(header http.Header) {
+	h.header = &header
+}
+
+// requestID returns the request identifier found in the response headers.
+func requestID(header http.Header) string {
+	for _, key := range []string{"X-Request-Id", "Cf-Ray"} {
+		if id := header.Get(key); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
 // sendRequest sends an HTTP request and processes the response.
 // It handles common error cases and deserializes the response body into the provided value.
+// Compressed responses are requested and decoded transparently.
 func (c *Client) sendRequest(req *http.Request, v any) error {
+	if v == nil {
+		return c.sendRequestFunc(req, nil)
+	}
+	return c.sendRequestFunc(req, func(header http.Header, decoder *json.Decoder) error {
+		if h, ok := v.(interface{ setHeader(http.Header) }); ok {
+			h.setHeader(header)
+		}
+		return decoder.Decode(v)
+	})
+}
+
+// sendRequestFunc sends an HTTP request like sendRequest, but lets decode read the
+// response body from a JSON decoder as it arrives, so that large responses can be
+// processed incrementally. Errors returned by decode are reported as decoding errors.
+func (c *Client) sendRequestFunc(req *http.Request, decode func(header http.Header, decoder *json.Decoder) error) error {
 	req.Header.Set("Accept", "application/json")
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+
+	ctx, cancel := c.withRequestTimeout(req.Context())
+	defer cancel()
+	req = req.WithContext(ctx)
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -207,38 +563,107 @@ func (c *Client) sendRequest(req *http.Request, v any) error {
 		}
 	}()
 
-	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusBadRequest {
-		return c.handleErrorResp(res)
+	if decode == nil {
+		return nil
 	}
 
-	if v == nil {
-		return nil
+	// Decode the body as it is read, rather than reading it into memory first
+	body := &responseReader{r: c.limitBody(res.Body)}
+	if err := decode(res.Header, json.NewDecoder(body)); err != nil {
+		if body.err != nil {
+			return fmt.Errorf("error, reading response body: %w", body.err)
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		// Read the rest of the snippet reported with the error
+		_, _ = io.CopyN(io.Discard, body, maxBodySnippetLength)
+		return fmt.Errorf(
+			"error, decoding response: %w, content type: %q, body: %s",
+			err, res.Header.Get("Content-Type"), bodySnippet(body.snippet),
+		)
 	}
-	return json.NewDecoder(res.Body).Decode(v)
+	_, _ = io.CopyN(io.Discard, res.Body, maxDrainLength)
+	return nil
+}
+
+// responseReader reads a response body being decoded, keeping its beginning for error
+// messages and the error that interrupted reading, if any.
+type responseReader struct {
+	r       io.Reader
+	snippet []byte
+	err     error
+}
+
+func (r *responseReader) Read(p []byte) (n int, err error) {
+	n, err = r.r.Read(p)
+	if room := maxBodySnippetLength + 1 - len(r.snippet); room > 0 {
+		r.snippet = append(r.snippet, p[:min(n, room)]...)
+	}
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return
+}
+
+// bodySnippet returns the beginning of a response body for inclusion in error messages.
+func bodySnippet(body []byte) string {
+	if len(body) <= maxBodySnippetLength {
+		return string(body)
+	}
+	return string(body[:maxBodySnippetLength]) + "..."
 }
 
 // handleErrorResp processes an error response from the API.
 // It extracts error details from the response body and returns an appropriate error.
 func (c *Client) handleErrorResp(resp *http.Response) error {
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("error, reading response body: %w", err)
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			buf.Reset()
+			bufferPool.Put(buf)
+		}
+	}()
+	_, readErr := buf.ReadFrom(c.limitBody(resp.Body))
+	if readErr != nil && !errors.Is(readErr, ErrResponseTooLarge) {
+		return fmt.Errorf("error, reading response body: %w", readErr)
 	}
+	body := buf.Bytes()
+	now := time.Now()
+	retryAfter := parseRetryAfter(resp.Header, now)
+	rateLimit := parseRateLimit(resp.Header, now)
+
 	var errRes ErrorResponse
-	err = json.Unmarshal(body, &errRes)
-	if err != nil || errRes.Error == nil {
+	err := json.Unmarshal(body, &errRes)
+	if err != nil || errRes.Error == nil || readErr != nil {
 		reqErr := &RequestError{
 			HTTPStatus:     resp.Status,
 			HTTPStatusCode: resp.StatusCode,
 			Err:            err,
-			Body:           body,
+			Body:           bytes.Clone(body),
+			Header:         resp.Header,
+			RetryAfter:     retryAfter,
+			RateLimit:      rateLimit,
+		}
+		if resp.Request != nil {
+			reqErr.Method = resp.Request.Method
+			reqErr.URL = resp.Request.URL.String()
 		}
 		if errRes.Error != nil {
 			reqErr.Err = errRes.Error
 		}
+		if readErr != nil {
+			// Report the status of an oversized error body along with its beginning
+			reqErr.Err = readErr
+		}
 		return reqErr
 	}
 
+	errRes.Error.HTTPStatus = resp.Status
+	errRes.Error.HTTPStatusCode = resp.StatusCode
+	errRes.Error.Header = resp.Header
+	errRes.Error.RetryAfter = retryAfter
+	errRes.Error.RateLimit = rateLimit
 	return errRes.Error
 }
 
@@ -2,12 +2,17 @@ package gopenrouter
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"testing/iotest"
+	"time"
 )
 
 func TestNewClientDefaults(t *testing.T) {
@@ -59,7 +64,9 @@ func TestClientSetCommonHeaders(t *testing.T) {
 
 	client := New(apiKey, WithSiteURL(siteURL), WithSiteTitle(siteTitle))
 	req, _ := http.NewRequest(http.MethodPost, "http://example.com", nil)
-	client.setCommonHeaders(req)
+	if err := client.setCommonHeaders(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	if req.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", apiKey) {
 		t.Error("Authorization header not set")
@@ -72,6 +79,51 @@ func TestClientSetCommonHeaders(t *testing.T) {
 	}
 }
 
+func TestAPIKeyProvider(t *testing.T) {
+	t.Run("KeyPerRequest", func(t *testing.T) {
+		var keys []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keys = append(keys, r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"data":{"total_credits":10,"total_usage":1}}`))
+		}))
+		defer server.Close()
+
+		calls := 0
+		client := New("static-key", WithBaseURL(server.URL), WithAPIKeyProvider(func(ctx context.Context) (string, error) {
+			calls++
+			return fmt.Sprintf("rotated-key-%d", calls), nil
+		}))
+
+		for range 2 {
+			if _, err := client.GetCredits(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		expected := []string{"Bearer rotated-key-1", "Bearer rotated-key-2"}
+		if len(keys) != len(expected) || keys[0] != expected[0] || keys[1] != expected[1] {
+			t.Errorf("expected Authorization headers %v, got %v", expected, keys)
+		}
+	})
+
+	t.Run("ProviderError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("request should not be sent")
+		}))
+		defer server.Close()
+
+		providerErr := errors.New("secret manager unavailable")
+		client := New("", WithBaseURL(server.URL), WithAPIKeyProvider(func(ctx context.Context) (string, error) {
+			return "", providerErr
+		}))
+
+		_, err := client.GetCredits(context.Background())
+		if !errors.Is(err, providerErr) {
+			t.Errorf("expected provider error, got %v", err)
+		}
+	})
+}
+
 func TestHandleErrorResp(t *testing.T) {
 	cases := []struct {
 		name         string
@@ -122,6 +174,9 @@ func TestHandleErrorResp(t *testing.T) {
 					if apiErr.Code != tc.expectCode || apiErr.Message != tc.expectMsg {
 						t.Errorf("unexpected APIError: %+v", apiErr)
 					}
+					if apiErr.HTTPStatusCode != tc.statusCode {
+						t.Errorf("unexpected HTTP status code in APIError: %+v", apiErr)
+					}
 					if apiErr.Code > 0 && !strings.Contains(errStr, fmt.Sprintf("%d", apiErr.Code)) {
 						t.Errorf("Error() string does not contain code: %s", errStr)
 					}
@@ -226,3 +281,470 @@ func TestClientNewRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestResponseHeaders(t *testing.T) {
+	newServer := func(statusCode int, body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Request-Id", "req-123")
+			w.Header().Set("X-Custom", "value")
+			w.WriteHeader(statusCode)
+			_, _ = w.Write([]byte(body))
+		}))
+	}
+
+	t.Run("Response", func(t *testing.T) {
+		server := newServer(http.StatusOK, `{"data":{"total_credits":10,"total_usage":1}}`)
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL))
+		data, err := client.GetCredits(context.Background())
+		if err != nil {
+			t.Fatalf("GetCredits failed: %v", err)
+		}
+		if data.RequestID() != "req-123" {
+			t.Errorf("Expected request ID 'req-123', got '%s'", data.RequestID())
+		}
+		if data.Header().Get("X-Custom") != "value" {
+			t.Errorf("Expected X-Custom header 'value', got '%s'", data.Header().Get("X-Custom"))
+		}
+	})
+
+	t.Run("Comparable", func(t *testing.T) {
+		server := newServer(http.StatusOK, `{"data":{"total_credits":10,"total_usage":1}}`)
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL))
+		data, err := client.GetCredits(context.Background())
+		if err != nil {
+			t.Fatalf("GetCredits failed: %v", err)
+		}
+		copied := data
+		if copied != data {
+			t.Error("Expected a copy of the response to compare equal")
+		}
+		if (CreditsData{}).Header() != nil {
+			t.Error("Expected no headers on a zero value")
+		}
+		_ = map[GenerationData]bool{{}: true}
+		_ = map[APIKey]bool{{}: true}
+		_ = map[CoinbaseCharge]bool{{}: true}
+	})
+
+	t.Run("ChatCompletion", func(t *testing.T) {
+		server := newServer(http.StatusOK, `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`)
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL))
+		request := NewChatCompletionRequestBuilder("test-model", []ChatMessage{{Role: "user", Content: "Hello"}}).Build()
+		response, err := client.ChatCompletion(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("ChatCompletion failed: %v", err)
+		}
+		if response.RequestID() != "req-123" {
+			t.Errorf("Expected request ID 'req-123', got '%s'", response.RequestID())
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		server := newServer(http.StatusOK, "data: [DONE]\n\n")
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL))
+		request := NewCompletionRequestBuilder("test-model", "Hello").Build()
+		stream, err := client.CompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("CompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		if stream.RequestID() != "req-123" {
+			t.Errorf("Expected request ID 'req-123', got '%s'", stream.RequestID())
+		}
+		if stream.Header().Get("X-Custom") != "value" {
+			t.Errorf("Expected X-Custom header 'value', got '%s'", stream.Header().Get("X-Custom"))
+		}
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		server := newServer(http.StatusBadRequest, `{"error":{"code":400,"message":"Invalid request"}}`)
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL))
+		_, err := client.GetCredits(context.Background())
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got %T: %v", err, err)
+		}
+		if apiErr.RequestID() != "req-123" {
+			t.Errorf("Expected request ID 'req-123', got '%s'", apiErr.RequestID())
+		}
+	})
+
+	t.Run("RequestError", func(t *testing.T) {
+		server := newServer(http.StatusInternalServerError, `Internal Server Error`)
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL))
+		_, err := client.GetCredits(context.Background())
+
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) {
+			t.Fatalf("Expected RequestError, got %T: %v", err, err)
+		}
+		if reqErr.RequestID() != "req-123" {
+			t.Errorf("Expected request ID 'req-123', got '%s'", reqErr.RequestID())
+		}
+		if reqErr.Header.Get("X-Custom") != "value" {
+			t.Errorf("Expected X-Custom header 'value', got '%s'", reqErr.Header.Get("X-Custom"))
+		}
+	})
+}
+
+func TestSendRequestDecodeError(t *testing.T) {
+	t.Run("IncludesContentTypeAndBody", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html>Maintenance</html>`))
+		}))
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL))
+		_, err := client.GetCredits(context.Background())
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		var syntaxErr *json.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("expected wrapped json.SyntaxError, got %T: %v", err, err)
+		}
+		if !strings.Contains(err.Error(), `"text/html"`) {
+			t.Errorf("error does not contain content type: %s", err)
+		}
+		if !strings.Contains(err.Error(), "<html>Maintenance</html>") {
+			t.Errorf("error does not contain body: %s", err)
+		}
+	})
+
+	t.Run("TruncatesLongBody", func(t *testing.T) {
+		body := strings.Repeat("x", maxBodySnippetLength*2)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}))
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL))
+		_, err := client.GetCredits(context.Background())
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if strings.Contains(err.Error(), body) {
+			t.Errorf("expected body to be truncated: %s", err)
+		}
+		if !strings.Contains(err.Error(), strings.Repeat("x", maxBodySnippetLength)+"...") {
+			t.Errorf("expected truncated body snippet: %s", err)
+		}
+	})
+
+	t.Run("EmptyBody", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL))
+		_, err := client.GetCredits(context.Background())
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+
+	t.Run("ReadError", func(t *testing.T) {
+		readErr := errors.New("connection reset")
+		failing := func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       io.NopCloser(io.MultiReader(strings.NewReader(`{"data":`), iotest.ErrReader(readErr))),
+				}, nil
+			}
+		}
+
+		client := New("test-api-key", WithMiddleware(failing))
+		_, err := client.GetCredits(context.Background())
+		if !errors.Is(err, readErr) {
+			t.Fatalf("expected the read error, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "reading response body") {
+			t.Errorf("expected a read error message, got %s", err)
+		}
+	})
+}
+
+func TestHandleErrorRespBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("gateway error " + r.URL.Query().Get("n")))
+	}))
+	defer server.Close()
+
+	client := New("test-api-key", WithBaseURL(server.URL))
+	var bodies []string
+	var errs []*RequestError
+	for n := range 3 {
+		var reqErr *RequestError
+		err := client.Do(context.Background(), http.MethodGet, fmt.Sprintf("/credits?n=%d", n), nil, nil)
+		if !errors.As(err, &reqErr) {
+			t.Fatalf("Expected RequestError, got %T: %v", err, err)
+		}
+		errs = append(errs, reqErr)
+		bodies = append(bodies, fmt.Sprintf("gateway error %d", n))
+	}
+
+	// Error bodies must not share the pooled buffers they were read into
+	for i, reqErr := range errs {
+		if string(reqErr.Body) != bodies[i] {
+			t.Errorf("Expected body %q, got %q", bodies[i], reqErr.Body)
+		}
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	slowServer := func(delay time.Duration, body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Consume the body so the server notices when the client goes away
+			_, _ = io.Copy(io.Discard, r.Body)
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}))
+	}
+
+	t.Run("Request", func(t *testing.T) {
+		server := slowServer(time.Second, `{"data":{"total_credits":1,"total_usage":0}}`)
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithRequestTimeout(50*time.Millisecond))
+		_, err := client.GetCredits(context.Background())
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("CallerDeadlineTakesPrecedence", func(t *testing.T) {
+		server := slowServer(100*time.Millisecond, `{"data":{"total_credits":1,"total_usage":0}}`)
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithRequestTimeout(50*time.Millisecond))
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := client.GetCredits(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("StreamEstablishment", func(t *testing.T) {
+		server := slowServer(time.Second, "data: [DONE]\n\n")
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithRequestTimeout(50*time.Millisecond))
+		request := NewCompletionRequestBuilder("test-model", "Hello").Build()
+		_, err := client.CompletionStream(context.Background(), *request)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("EstablishedStreamNotInterrupted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			time.Sleep(150 * time.Millisecond)
+			_, _ = w.Write([]byte(`data: {"id":"cmpl-1","choices":[{"index":0,"text":"Hello"}]}` + "\n\n"))
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithRequestTimeout(50*time.Millisecond))
+		request := NewCompletionRequestBuilder("test-model", "Hello").Build()
+		stream, err := client.CompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("CompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		chunk, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		if chunk.Choices[0].Text != "Hello" {
+			t.Errorf("expected text 'Hello', got '%s'", chunk.Choices[0].Text)
+		}
+	})
+}
+
+// countingBody is a request body counting how often it is marshaled.
+type countingBody struct {
+	marshals *atomic.Int32
+}
+
+func (b countingBody) MarshalJSON() ([]byte, error) {
+	b.marshals.Add(1)
+	return []byte(`{"model":"test-model"}`), nil
+}
+
+func TestRequestBodyEncoding(t *testing.T) {
+	t.Run("StreamReconnectMarshalsOnce", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"model":"test-model"}` {
+				t.Errorf("Expected the encoded body, got %s", body)
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("id: 1\ndata: {\"id\":\"gen-1\"}\n\n"))
+			if attempts.Add(1) > 1 {
+				_, _ = w.Write([]byte("data: [DONE]\n\n"))
+			}
+		}))
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithStreamReconnect(1))
+		body := countingBody{marshals: new(atomic.Int32)}
+		stream, err := newClientStream[ChatCompletionStreamResponse](context.Background(), client, "/chat/completions", "test-model", body, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer func() { _ = stream.close() }()
+		for {
+			if _, err := stream.recv(context.Background()); err != nil {
+				if err != io.EOF {
+					t.Fatalf("Expected io.EOF, got %v", err)
+				}
+				break
+			}
+		}
+
+		if attempts.Load() != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts.Load())
+		}
+		if body.marshals.Load() != 1 {
+			t.Errorf("Expected the body to be marshaled once, got %d", body.marshals.Load())
+		}
+	})
+
+	t.Run("RetryReplaysBody", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"model":"test-model"}` {
+				t.Errorf("Expected the encoded body on attempt %d, got %s", attempts.Load()+1, body)
+			}
+			if attempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithRetry(2, RetryPolicy{InitialBackoff: time.Millisecond}))
+		body := countingBody{marshals: new(atomic.Int32)}
+		if err := client.Do(context.Background(), http.MethodPost, "/chat/completions", body, &struct{}{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if attempts.Load() != 2 || body.marshals.Load() != 1 {
+			t.Errorf("Expected 2 attempts and 1 marshal, got %d and %d", attempts.Load(), body.marshals.Load())
+		}
+	})
+
+	t.Run("Authorization", func(t *testing.T) {
+		client := New("test-api-key")
+		req, err := client.newRequest(context.Background(), http.MethodGet, client.fullURL("/models"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := req.Header.Get("Authorization"); got != "Bearer test-api-key" {
+			t.Errorf("Expected 'Bearer test-api-key', got '%s'", got)
+		}
+
+		client = New("test-api-key", WithAPIKeyProvider(func(context.Context) (string, error) {
+			return "", nil
+		}))
+		req, err = client.newRequest(context.Background(), http.MethodGet, client.fullURL("/models"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := req.Header.Get("Authorization"); got != "" {
+			t.Errorf("Expected no Authorization header for an empty provided key, got '%s'", got)
+		}
+	})
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	newServer := func(status int, body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}))
+	}
+	credits := `{"data":{"total_credits":10,"total_usage":1}}`
+
+	t.Run("WithinLimit", func(t *testing.T) {
+		server := newServer(http.StatusOK, credits)
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithMaxResponseSize(int64(len(credits))))
+		if _, err := client.GetCredits(context.Background()); err != nil {
+			t.Errorf("Expected a body of exactly the limit to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("Response", func(t *testing.T) {
+		server := newServer(http.StatusOK, credits)
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithMaxResponseSize(int64(len(credits)-1)))
+		_, err := client.GetCredits(context.Background())
+		if !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("ErrorResponse", func(t *testing.T) {
+		server := newServer(http.StatusBadGateway, "<html>"+strings.Repeat("x", 1000)+"</html>")
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithMaxResponseSize(100))
+		_, err := client.GetCredits(context.Background())
+
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) {
+			t.Fatalf("Expected RequestError, got %T: %v", err, err)
+		}
+		if reqErr.HTTPStatusCode != http.StatusBadGateway {
+			t.Errorf("Expected status 502, got %d", reqErr.HTTPStatusCode)
+		}
+		if len(reqErr.Body) != 100 {
+			t.Errorf("Expected the first 100 bytes of the body, got %d", len(reqErr.Body))
+		}
+		if !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		server := newServer(http.StatusOK, credits)
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithMaxResponseSize(0))
+		if _, err := client.GetCredits(context.Background()); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
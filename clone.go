@@ -0,0 +1,78 @@
+package gopenrouter
+
+import "reflect"
+
+// deepCopy returns a copy of v that shares no pointers, slices, or maps with it.
+// Unexported struct fields are copied shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := range v.NumField() {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem()))
+		return c
+	default:
+		return v
+	}
+}
+
+// cloneRequest returns a deep copy of a request.
+func cloneRequest[T any](request *T) *T {
+	clone := deepClone(*request)
+	return &clone
+}
+
+// deepClone returns a deep copy of v, such as a response shared between callers.
+func deepClone[T any](v T) T {
+	return deepCopy(reflect.ValueOf(&v).Elem()).Interface().(T)
+}
+
+// applyOverrides returns a deep copy of a request whose fields are replaced by the fields
+// set in overrides, that is those that are not zero.
+func applyOverrides[T any](request *T, overrides T) *T {
+	result := cloneRequest(request)
+	dst := reflect.ValueOf(result).Elem()
+	src := reflect.ValueOf(overrides)
+	for i := range src.NumField() {
+		if field := src.Field(i); !field.IsZero() && dst.Field(i).CanSet() {
+			dst.Field(i).Set(deepCopy(field))
+		}
+	}
+	return result
+}
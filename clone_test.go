@@ -0,0 +1,76 @@
+package gopenrouter_test
+
+import (
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+func TestChatCompletionRequestClone(t *testing.T) {
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+	base := gopenrouter.NewChatCompletionRequestBuilder("test/model", messages).
+		WithTemperature(0.7).
+		WithProvider(&gopenrouter.ProviderOptions{Order: []string{"openai"}}).
+		WithLogitBias(map[string]float64{"50256": -100}).
+		Build()
+
+	t.Run("Clone", func(t *testing.T) {
+		clone := base.Clone()
+		if diff := gopenroutertest.Diff(base, clone); diff != "" {
+			t.Fatalf("Expected an equal clone:\n%s", diff)
+		}
+
+		*clone.Temperature = 0.1
+		clone.Messages[0].Content = "Changed"
+		clone.Provider.Order[0] = "anthropic"
+		clone.LogitBias["50256"] = 0
+		if *base.Temperature != 0.7 || base.Messages[0].Content != "Hello" || base.Provider.Order[0] != "openai" || base.LogitBias["50256"] != -100 {
+			t.Errorf("Expected the base request to be unchanged, got %+v", base)
+		}
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		temperature := 0.2
+		prompt := []gopenrouter.ChatMessage{{Role: "user", Content: "Goodbye"}}
+		request := base.Apply(gopenrouter.ChatCompletionRequest{Messages: prompt, Temperature: &temperature})
+
+		if request.Model != "test/model" || request.Provider.Order[0] != "openai" {
+			t.Errorf("Expected unset overrides to keep the base fields, got %+v", request)
+		}
+		if request.Messages[0].Content != "Goodbye" || *request.Temperature != 0.2 {
+			t.Errorf("Expected the overridden fields, got %+v", request)
+		}
+		if base.Messages[0].Content != "Hello" || *base.Temperature != 0.7 {
+			t.Errorf("Expected the base request to be unchanged, got %+v", base)
+		}
+
+		temperature = 1
+		prompt[0].Content = "Changed"
+		if *request.Temperature != 0.2 || request.Messages[0].Content != "Goodbye" {
+			t.Error("Expected the request not to share the values of the overrides")
+		}
+	})
+}
+
+func TestCompletionRequestClone(t *testing.T) {
+	base := gopenrouter.NewCompletionRequestBuilder("test/model", "Once upon a time").
+		WithMaxTokens(100).
+		WithStop([]string{"\n"}).
+		Build()
+
+	clone := base.Clone()
+	*clone.MaxTokens = 10
+	clone.Stop[0] = "."
+	if *base.MaxTokens != 100 || base.Stop[0] != "\n" {
+		t.Errorf("Expected the base request to be unchanged, got %+v", base)
+	}
+
+	request := base.Apply(gopenrouter.CompletionRequest{Prompt: "In a galaxy far away"})
+	if request.Prompt != "In a galaxy far away" || *request.MaxTokens != 100 || request.Model != "test/model" {
+		t.Errorf("Expected the prompt to be overridden and other fields kept, got %+v", request)
+	}
+	if base.Prompt != "Once upon a time" {
+		t.Errorf("Expected the base prompt to be unchanged, got '%s'", base.Prompt)
+	}
+}
@@ -1,11 +1,10 @@
 package gopenrouter
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
+	"maps"
 	"net/http"
 	"strings"
 )
@@ -59,6 +58,40 @@ const (
 	QuantizationUnknown Quantization = "unknown"
 )
 
+// ProviderSort represents the strategy used to rank the providers of a model.
+// Setting a sort strategy disables load balancing between providers.
+type ProviderSort string
+
+const (
+	// SortPrice prioritizes the providers with the lowest price
+	SortPrice ProviderSort = "price"
+
+	// SortThroughput prioritizes the providers with the highest throughput
+	SortThroughput ProviderSort = "throughput"
+
+	// SortLatency prioritizes the providers with the lowest latency
+	SortLatency ProviderSort = "latency"
+)
+
+// Valid reports whether the sort strategy is one known to OpenRouter.
+func (s ProviderSort) Valid() bool {
+	switch s {
+	case SortPrice, SortThroughput, SortLatency:
+		return true
+	}
+	return false
+}
+
+// ParseProviderSort converts a string, such as a configuration value, to a sort strategy,
+// returning an error if it is not a known strategy.
+func ParseProviderSort(s string) (ProviderSort, error) {
+	sort := ProviderSort(strings.ToLower(strings.TrimSpace(s)))
+	if !sort.Valid() {
+		return "", fmt.Errorf("invalid provider sort %q: must be one of price, throughput, latency", s)
+	}
+	return sort, nil
+}
+
 // CompletionRequest represents a request payload for the completions endpoint.
 // It contains all parameters needed to generate text completions from AI models.
 type CompletionRequest struct {
@@ -71,6 +104,8 @@ type CompletionRequest struct {
 	// Optional fields
 	// Models provides an alternate list of models for routing overrides
 	Models []string `json:"models,omitempty"`
+	// Preset is the slug of a preset configured in the OpenRouter dashboard to apply
+	Preset string `json:"preset,omitempty"`
 	// Provider contains preferences for provider routing
 	Provider *ProviderOptions `json:"provider,omitempty"`
 	// Reasoning configures model reasoning/thinking tokens
@@ -79,8 +114,12 @@ type CompletionRequest struct {
 	Usage *UsageOptions `json:"usage,omitempty"`
 	// Transforms lists prompt transformations (OpenRouter-only feature)
 	Transforms []string `json:"transforms,omitempty"`
+	// Plugins lists the OpenRouter plugins enabled for the request, such as web search
+	Plugins []Plugin `json:"plugins,omitempty"`
 	// Stream enables streaming of results as they are generated
 	Stream *bool `json:"stream,omitempty"`
+	// StreamOptions configures streaming behavior, such as including a trailing usage chunk
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
 	// MaxTokens limits the maximum number of tokens in the response
 	MaxTokens *int `json:"max_tokens,omitempty"`
 	// Temperature controls randomness in generation (range: [0, 2])
@@ -108,14 +147,73 @@ type CompletionRequest struct {
 	// Logprobs enables returning log probabilities of output tokens
 	Logprobs *bool `json:"logprobs,omitempty"`
 	// Stop specifies sequences where the model will stop generating tokens
-	Stop []string `json:"stop,omitempty"`
+	Stop Stop `json:"stop,omitempty"`
+	// User is a stable identifier for end-users, used to help detect and prevent abuse
+	User *string `json:"user,omitempty"`
+}
+
+// Clone returns a deep copy of the completion request, so that a base request can be reused
+// across calls and modified without affecting it.
+func (r *CompletionRequest) Clone() *CompletionRequest {
+	return cloneRequest(r)
+}
+
+// Apply returns a copy of the completion request whose fields are replaced by those set in
+// overrides, leaving the request unchanged. Fields left at their zero value in overrides
+// are kept, so fields cannot be unset with Apply; modify a Clone instead.
+//
+// Example usage:
+//
+//	request := base.Apply(gopenrouter.CompletionRequest{Prompt: prompt})
+func (r *CompletionRequest) Apply(overrides CompletionRequest) *CompletionRequest {
+	return applyOverrides(r, overrides)
 }
 
 // UsageOptions controls whether to include token usage information in the response.
 // When enabled, the API will return counts of prompt, completion, and total tokens.
 type UsageOptions struct {
 	// Include determines whether token usage information should be returned
-	Include *bool `json:"usage,omitempty"`
+	Include *bool `json:"include,omitempty"`
+}
+
+// StreamOptions configures optional behavior of streamed responses.
+// These options only take effect when streaming is enabled.
+type StreamOptions struct {
+	// IncludeUsage requests a final chunk containing token usage statistics for the whole request
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// Stop holds the sequences where the model stops generating tokens. The API accepts them
+// as a single string or an array: a single sequence is encoded as a string and several as
+// an array, and both forms are decoded.
+type Stop []string
+
+// MarshalJSON encodes a single stop sequence as a string and several as an array.
+func (s Stop) MarshalJSON() ([]byte, error) {
+	if len(s) == 1 {
+		return json.Marshal(s[0])
+	}
+	return json.Marshal([]string(s))
+}
+
+// UnmarshalJSON decodes stop sequences sent as a string or an array of strings.
+func (s *Stop) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var sequence string
+	if err := json.Unmarshal(data, &sequence); err == nil {
+		*s = Stop{sequence}
+		return nil
+	}
+
+	var sequences []string
+	if err := json.Unmarshal(data, &sequences); err != nil {
+		return fmt.Errorf("stop must be a string or an array of strings: %w", err)
+	}
+	*s = sequences
+	return nil
 }
 
 // ReasoningOptions configures how models allocate tokens for internal reasoning.
@@ -159,6 +257,18 @@ type Usage struct {
 	PromptTokensDetails *PromptTokensDetails `json:"prompt_tokens_details,omitempty"`
 	// CompletionTokensDetails provides detailed breakdown of completion tokens
 	CompletionTokensDetails *CompletionTokensDetails `json:"completion_tokens_details,omitempty"`
+	// Cost is the amount of credits charged for the request, reported when usage accounting is enabled
+	Cost float64 `json:"cost,omitempty"`
+	// IsBYOK indicates if the request was served with a "Bring Your Own Key" provider key
+	IsBYOK bool `json:"is_byok,omitempty"`
+	// CostDetails provides detailed breakdown of the cost
+	CostDetails *CostDetails `json:"cost_details,omitempty"`
+}
+
+// CostDetails provides detailed information about the cost of a request
+type CostDetails struct {
+	// UpstreamInferenceCost is the cost charged by the provider for "Bring Your Own Key" requests
+	UpstreamInferenceCost float64 `json:"upstream_inference_cost"`
 }
 
 // PromptTokensDetails provides detailed information about prompt token usage
@@ -232,6 +342,12 @@ func (b *CompletionRequestBuilder) WithModels(models []string) *CompletionReques
 	return b
 }
 
+// WithPreset sets the preset whose configuration is applied to the request
+func (b *CompletionRequestBuilder) WithPreset(slug string) *CompletionRequestBuilder {
+	b.request.Preset = slug
+	return b
+}
+
 // WithProvider sets provider routing options
 func (b *CompletionRequestBuilder) WithProvider(provider *ProviderOptions) *CompletionRequestBuilder {
 	b.request.Provider = provider
@@ -259,12 +375,40 @@ func (b *CompletionRequestBuilder) WithTransforms(transforms []string) *Completi
 	return b
 }
 
+// WithPlugins sets the list of plugins enabled for the request
+func (b *CompletionRequestBuilder) WithPlugins(plugins []Plugin) *CompletionRequestBuilder {
+	b.request.Plugins = plugins
+	return b
+}
+
+// WithPlugin adds a plugin to the list of plugins enabled for the request
+func (b *CompletionRequestBuilder) WithPlugin(plugin Plugin) *CompletionRequestBuilder {
+	b.request.Plugins = append(b.request.Plugins, plugin)
+	return b
+}
+
+// WithWebSearch enables the web search plugin with the given options
+func (b *CompletionRequestBuilder) WithWebSearch(options WebPluginOptions) *CompletionRequestBuilder {
+	return b.WithPlugin(WebPlugin(options))
+}
+
+// WithPDFEngine enables the file parser plugin, parsing PDFs with the given engine
+func (b *CompletionRequestBuilder) WithPDFEngine(engine PDFEngine) *CompletionRequestBuilder {
+	return b.WithPlugin(FileParserPlugin(engine))
+}
+
 // WithStream enables or disables streaming
 func (b *CompletionRequestBuilder) WithStream(stream bool) *CompletionRequestBuilder {
 	b.request.Stream = &stream
 	return b
 }
 
+// WithStreamIncludeUsage sets whether a trailing usage chunk is sent when streaming
+func (b *CompletionRequestBuilder) WithStreamIncludeUsage(include bool) *CompletionRequestBuilder {
+	b.request.StreamOptions = &StreamOptions{IncludeUsage: include}
+	return b
+}
+
 // WithMaxTokens sets the maximum tokens
 func (b *CompletionRequestBuilder) WithMaxTokens(maxTokens int) *CompletionRequestBuilder {
 	b.request.MaxTokens = &maxTokens
@@ -349,11 +493,56 @@ func (b *CompletionRequestBuilder) WithStop(stop []string) *CompletionRequestBui
 	return b
 }
 
+// WithStopSequence adds a sequence to the stop sequences for token generation
+func (b *CompletionRequestBuilder) WithStopSequence(sequence string) *CompletionRequestBuilder {
+	b.request.Stop = append(b.request.Stop, sequence)
+	return b
+}
+
+// WithUser sets the user identifier for the request
+func (b *CompletionRequestBuilder) WithUser(user string) *CompletionRequestBuilder {
+	b.request.User = &user
+	return b
+}
+
 // Build finalizes and returns the constructed CompletionRequest.
 func (b *CompletionRequestBuilder) Build() *CompletionRequest {
 	return b.request
 }
 
+// BuildE returns the constructed CompletionRequest after validating it. The ranges of the
+// sampling parameters are checked, along with options that conflict with each other, such
+// as top_logprobs without logprobs, and the provider options.
+//
+// Returns:
+//   - *CompletionRequest: The constructed request
+//   - error: A *ValidationError listing every invalid parameter
+func (b *CompletionRequestBuilder) BuildE() (*CompletionRequest, error) {
+	r := b.request
+	var v validator
+	v.check(samplingParameters{
+		provider:          r.Provider,
+		reasoning:         r.Reasoning,
+		stream:            r.Stream,
+		streamOptions:     r.StreamOptions,
+		maxTokens:         r.MaxTokens,
+		temperature:       r.Temperature,
+		topP:              r.TopP,
+		topK:              r.TopK,
+		frequencyPenalty:  r.FrequencyPenalty,
+		presencePenalty:   r.PresencePenalty,
+		repetitionPenalty: r.RepetitionPenalty,
+		topLogProbs:       r.TopLogProbs,
+		minP:              r.MinP,
+		topA:              r.TopA,
+		logprobs:          r.Logprobs,
+	})
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 // ProviderOptions specifies preferences for how OpenRouter should route requests to AI providers.
 // These options allow for fine-grained control over which providers are used and how they are selected.
 type ProviderOptions struct {
@@ -367,6 +556,9 @@ type ProviderOptions struct {
 	// Valid values: "deny", "allow"
 	DataCollection string `json:"data_collection,omitempty"`
 
+	// ZDR restricts routing to endpoints with a zero data retention policy
+	ZDR *bool `json:"zdr,omitempty"`
+
 	// Order specifies the ordered list of provider names to try (e.g. ["Anthropic", "OpenAI"])
 	Order []string `json:"order,omitempty"`
 
@@ -381,14 +573,28 @@ type ProviderOptions struct {
 	Quantizations []Quantization `json:"quantizations,omitempty"`
 
 	// Sort specifies how to rank available providers
-	// Valid values: "price", "throughput", "latency"
+	// Valid values: "price", "throughput", "latency", available as the ProviderSort constants
 	Sort string `json:"sort,omitempty"`
 
 	// MaxPrice sets the maximum pricing limits for this request
 	MaxPrice *MaxPrice `json:"max_price,omitempty"`
 
+	// PreferredMinThroughput deprioritizes providers whose throughput, in tokens per second,
+	// is below this value
+	PreferredMinThroughput *float64 `json:"preferred_min_throughput,omitempty"`
+
+	// PreferredMaxLatency deprioritizes providers whose latency to the first token, in seconds,
+	// is above this value
+	PreferredMaxLatency *float64 `json:"preferred_max_latency,omitempty"`
+
 	// Experimental contains experimental provider routing features
 	Experimental *ExperimentalOptions `json:"experimental,omitempty"`
+
+	// ProviderParameters maps provider slugs (e.g. "openai") to request parameters that
+	// override those of the request when it is routed to the provider, such as a different
+	// temperature or vendor-specific fields. They are sent as fields of the provider options,
+	// so slugs colliding with the name of an option, such as "sort", are rejected.
+	ProviderParameters map[string]map[string]any `json:"-"`
 }
 
 // MaxPrice specifies the maximum price limits for different components of a request.
@@ -445,6 +651,12 @@ func (b *ProviderOptionsBuilder) WithDataCollection(policy string) *ProviderOpti
 	return b
 }
 
+// WithZDR sets whether to restrict routing to zero data retention endpoints
+func (b *ProviderOptionsBuilder) WithZDR(zdr bool) *ProviderOptionsBuilder {
+	b.options.ZDR = &zdr
+	return b
+}
+
 // WithOrder sets the list of provider names to try in order
 func (b *ProviderOptionsBuilder) WithOrder(providers []string) *ProviderOptionsBuilder {
 	b.options.Order = providers
@@ -470,12 +682,19 @@ func (b *ProviderOptionsBuilder) WithQuantizations(quantizations []Quantization)
 }
 
 // WithSort sets the sorting strategy
-// Values should be "price", "throughput", or "latency"
+// Values should be "price", "throughput", or "latency". Unknown values are kept, and
+// reported by BuildE; use ParseProviderSort to check strings from other sources.
 func (b *ProviderOptionsBuilder) WithSort(sort string) *ProviderOptionsBuilder {
 	b.options.Sort = sort
 	return b
 }
 
+// WithSortStrategy sets the sorting strategy to one of the ProviderSort constants.
+func (b *ProviderOptionsBuilder) WithSortStrategy(sort ProviderSort) *ProviderOptionsBuilder {
+	b.options.Sort = string(sort)
+	return b
+}
+
 // WithMaxPrice sets the maximum pricing configuration
 func (b *ProviderOptionsBuilder) WithMaxPrice(maxPrice *MaxPrice) *ProviderOptionsBuilder {
 	b.options.MaxPrice = maxPrice
@@ -518,6 +737,33 @@ func (b *ProviderOptionsBuilder) WithMaxRequestPrice(price float64) *ProviderOpt
 	return b
 }
 
+// WithPreferredMinThroughput sets the minimum throughput in tokens per second
+// below which providers are deprioritized
+func (b *ProviderOptionsBuilder) WithPreferredMinThroughput(tokensPerSecond float64) *ProviderOptionsBuilder {
+	b.options.PreferredMinThroughput = &tokensPerSecond
+	return b
+}
+
+// WithPreferredMaxLatency sets the maximum latency to the first token in seconds
+// above which providers are deprioritized
+func (b *ProviderOptionsBuilder) WithPreferredMaxLatency(seconds float64) *ProviderOptionsBuilder {
+	b.options.PreferredMaxLatency = &seconds
+	return b
+}
+
+// WithProviderParameters sets request parameters overriding those of the request when it
+// is routed to the given provider, merging them with parameters set previously
+func (b *ProviderOptionsBuilder) WithProviderParameters(provider string, parameters map[string]any) *ProviderOptionsBuilder {
+	if b.options.ProviderParameters == nil {
+		b.options.ProviderParameters = make(map[string]map[string]any)
+	}
+	if b.options.ProviderParameters[provider] == nil {
+		b.options.ProviderParameters[provider] = make(map[string]any, len(parameters))
+	}
+	maps.Copy(b.options.ProviderParameters[provider], parameters)
+	return b
+}
+
 // WithForceChatCompletions sets whether to force using chat completions API
 func (b *ProviderOptionsBuilder) WithForceChatCompletions(force bool) *ProviderOptionsBuilder {
 	if b.options.Experimental == nil {
@@ -535,9 +781,26 @@ func (b *ProviderOptionsBuilder) Build() *ProviderOptions {
 	return &b.options
 }
 
+// BuildE finalizes and returns the constructed ProviderOptions after validating them.
+//
+// Returns:
+//   - *ProviderOptions: A pointer to the fully configured provider options object
+//   - error: A *ValidationError listing every invalid option, such as an unknown data
+//     collection policy, a negative price limit, or a provider both allowed and ignored
+func (b *ProviderOptionsBuilder) BuildE() (*ProviderOptions, error) {
+	var v validator
+	v.checkProvider(&b.options, "")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return &b.options, nil
+}
+
 // CompletionResponse represents the API response from a text completion request.
 // It contains the generated completions and associated metadata.
 type CompletionResponse struct {
+	httpHeader
+
 	// ID is the unique identifier for this completion request
 	ID string `json:"id"`
 	// Provider is the name of the AI provider that generated the completion
@@ -577,65 +840,122 @@ type StreamingChoice struct {
 	LogProbs           *LogProbs `json:"logprobs,omitempty"`
 }
 
+// Text returns the text delta carried by the chunk, concatenated across all choices.
+// For requests generating multiple choices, inspect Choices to tell them apart.
+func (r CompletionStreamResponse) Text() string {
+	var text strings.Builder
+	for _, choice := range r.Choices {
+		text.WriteString(choice.Text)
+	}
+	return text.String()
+}
+
 // CompletionStreamReader implements stream reader for completion responses
 type CompletionStreamReader struct {
-	reader   *bufio.Scanner
-	response *http.Response
+	stream      *streamReader[CompletionStreamResponse]
+	accumulator CompletionAccumulator
+	chunk       CompletionStreamResponse
 }
 
 // NewCompletionStreamReader creates a new stream reader for completion responses
 func NewCompletionStreamReader(response *http.Response) *CompletionStreamReader {
 	return &CompletionStreamReader{
-		reader:   bufio.NewScanner(response.Body),
-		response: response,
+		stream: newStreamReader[CompletionStreamResponse](response),
 	}
 }
 
 // Recv reads the next completion chunk from the stream
 func (r *CompletionStreamReader) Recv() (CompletionStreamResponse, error) {
-	var response CompletionStreamResponse
-
-	for {
-		if !r.reader.Scan() {
-			if err := r.reader.Err(); err != nil {
-				return response, fmt.Errorf("error reading stream: %w", err)
-			}
-			return response, io.EOF
-		}
+	return r.RecvContext(context.Background())
+}
 
-		line := strings.TrimSpace(r.reader.Text())
+// RecvContext reads the next completion chunk from the stream, giving up when ctx is done.
+// Unlike cancelling the request context, an expired ctx only abandons the current read:
+// the stream stays open and the next call to Recv or RecvContext returns the chunk the
+// abandoned read was waiting for. This allows per-chunk deadlines independent of the
+// deadline of the whole request.
+func (r *CompletionStreamReader) RecvContext(ctx context.Context) (CompletionStreamResponse, error) {
+	chunk, err := r.stream.recv(ctx)
+	if err == nil {
+		r.accumulator.AddChunk(chunk)
+	}
+	return chunk, err
+}
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, ":") {
-			continue
-		}
+// RecvChunk reads the next completion chunk from the stream like RecvContext, but decodes
+// it into a chunk owned by the reader instead of allocating a new one. The returned chunk
+// is only valid until the next call to RecvChunk, which overwrites it; copy the chunk to
+// retain it. This reduces allocations when consuming high-throughput streams.
+//
+// Like RecvRaw, RecvChunk does not add its chunks to the response assembled by Final.
+func (r *CompletionStreamReader) RecvChunk(ctx context.Context) (*CompletionStreamResponse, error) {
+	if err := r.stream.recvInto(ctx, &r.chunk); err != nil {
+		return nil, err
+	}
+	return &r.chunk, nil
+}
 
-		// Parse SSE data
-		if strings.HasPrefix(line, "data: ") {
-			data := strings.TrimPrefix(line, "data: ")
+// RecvRaw reads the next completion chunk from the stream and returns its JSON payload
+// without decoding it. This is useful for logging, persisting, or forwarding the
+// unmodified server-sent events. Malformed chunks are returned as-is.
+func (r *CompletionStreamReader) RecvRaw() ([]byte, error) {
+	return r.stream.recvRaw(context.Background())
+}
 
-			// Check for stream end
-			if data == "[DONE]" {
-				return response, io.EOF
-			}
+// RawChunk returns the JSON payload of the most recently received chunk,
+// or nil if no chunk has been received yet
+func (r *CompletionStreamReader) RawChunk() []byte {
+	return r.stream.lastRaw
+}
 
-			// Parse JSON chunk
-			if err := json.Unmarshal([]byte(data), &response); err != nil {
-				// Skip malformed chunks
-				continue
-			}
+// LastEventID returns the ID of the most recent server-sent event, if the server provides one
+func (r *CompletionStreamReader) LastEventID() string {
+	return r.stream.lastEventID
+}
 
-			return response, nil
-		}
-	}
+// Done reports whether the [DONE] sentinel has been received, meaning the stream finished
+// cleanly. Streams cut short before the sentinel end with ErrStreamTruncated instead
+func (r *CompletionStreamReader) Done() bool {
+	return r.stream.finished
+}
+
+// Final returns the completion response assembled from all chunks received with Recv
+// or RecvContext so far. Once Done reports true, it holds the complete response
+func (r *CompletionStreamReader) Final() CompletionResponse {
+	return r.accumulator.Response()
+}
+
+// SkippedChunks returns the number of chunks skipped by Recv because they could not be decoded
+func (r *CompletionStreamReader) SkippedChunks() int {
+	return r.stream.skipped
+}
+
+// DroppedChunks returns the number of chunks discarded because the read-ahead buffer
+// was full, which can only happen with the StreamBufferDropOldest policy
+func (r *CompletionStreamReader) DroppedChunks() int {
+	return r.stream.droppedChunks()
+}
+
+// Metrics returns performance measurements for the stream, such as time to first token
+// and token throughput. Metrics are final once the stream has finished or been closed
+func (r *CompletionStreamReader) Metrics() StreamMetrics {
+	return r.stream.metrics.snapshot()
+}
+
+// Header returns the headers of the HTTP response carrying the stream
+func (r *CompletionStreamReader) Header() http.Header {
+	return r.stream.header()
+}
+
+// RequestID returns the identifier assigned to the request by OpenRouter, which can be
+// referenced when reporting issues to OpenRouter support
+func (r *CompletionStreamReader) RequestID() string {
+	return requestID(r.stream.header())
 }
 
 // Close closes the completion stream reader
 func (r *CompletionStreamReader) Close() error {
-	if r.response != nil && r.response.Body != nil {
-		return r.response.Body.Close()
-	}
-	return nil
+	return r.stream.close()
 }
 
 // Completion sends a text completion request to the OpenRouter API.
@@ -647,20 +967,33 @@ func (r *CompletionStreamReader) Close() error {
 // Parameters:
 //   - ctx: The context for the request, which can be used for cancellation and timeouts
 //   - request: The completion request parameters
+//   - opts: Optional call options overriding headers, query parameters, body fields, or attribution
 //
 // Returns:
 //   - CompletionResponse: Contains the generated completions and metadata
 //   - error: Any error that occurred during the request, including ErrCompletionStreamNotSupported
-//     if streaming was requested
+//     if streaming was requested and ErrNoChoices if the response contains no completions
 func (c *Client) Completion(
 	ctx context.Context,
 	request CompletionRequest,
+	opts ...CallOption,
 ) (response CompletionResponse, err error) {
 	if request.Stream != nil && *request.Stream {
 		err = ErrCompletionStreamNotSupported
 		return
 	}
 
+	c.defaults.applyCompletion(&request)
+	if err = c.validateParameters(ctx, request.Model, request); err != nil {
+		return
+	}
+	c.checkProviderPreferences(ctx, request.Model, request.Provider)
+	if err = c.checkBudget(ctx, request.Model, &request.Usage, func(pricing ModelPricing) (CostEstimate, error) {
+		return EstimateCompletionCost(pricing, request, nil)
+	}); err != nil {
+		return
+	}
+
 	urlSuffix := "/completions"
 
 	req, err := c.newRequest(
@@ -668,12 +1001,20 @@ func (c *Client) Completion(
 		http.MethodPost,
 		c.fullURL(urlSuffix),
 		withBody(request),
+		withCallOptions(opts),
 	)
 	if err != nil {
 		return
 	}
 
 	err = c.sendRequest(req, &response)
+	if err == nil {
+		c.observeUsage(ctx, response.ID, response.Model, response.Provider, response.Usage)
+		c.enrichGeneration(ctx, response.ID)
+		if len(response.Choices) == 0 {
+			err = ErrNoChoices
+		}
+	}
 	return
 }
 
@@ -683,7 +1024,8 @@ func (c *Client) Completion(
 // to display partial results as they are generated by the AI model.
 //
 // The method automatically sets the stream parameter to true in the request and returns
-// a CompletionStreamReader for reading the streaming chunks.
+// a CompletionStreamReader for reading the streaming chunks. Call options are applied
+// to the initial request and to every reconnection attempt.
 //
 // Example usage:
 //
@@ -707,38 +1049,29 @@ func (c *Client) Completion(
 func (c *Client) CompletionStream(
 	ctx context.Context,
 	request CompletionRequest,
+	opts ...CallOption,
 ) (*CompletionStreamReader, error) {
 	// Ensure stream is enabled on a copy of the request
 	streamEnabled := true
 	request.Stream = &streamEnabled
 
-	urlSuffix := "/completions"
-
-	req, err := c.newRequest(
-		ctx,
-		http.MethodPost,
-		c.fullURL(urlSuffix),
-		withBody(request),
-	)
-	if err != nil {
+	c.defaults.applyCompletion(&request)
+	if err := c.validateParameters(ctx, request.Model, request); err != nil {
+		return nil, err
+	}
+	c.checkProviderPreferences(ctx, request.Model, request.Provider)
+	if err := c.checkBudget(ctx, request.Model, &request.Usage, func(pricing ModelPricing) (CostEstimate, error) {
+		return EstimateCompletionCost(pricing, request, nil)
+	}); err != nil {
 		return nil, err
 	}
 
-	// Set accept header for streaming
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
+	urlSuffix := "/completions"
 
-	resp, err := c.httpClient.Do(req)
+	stream, err := newClientStream[CompletionStreamResponse](ctx, c, urlSuffix, request.Model, request, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
-		defer func() {
-			_ = resp.Body.Close()
-		}()
-		return nil, c.handleErrorResp(resp)
-	}
-
-	return NewCompletionStreamReader(resp), nil
+	return &CompletionStreamReader{stream: stream}, nil
 }
@@ -2,6 +2,7 @@ package gopenrouter_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -118,6 +119,19 @@ func TestClientCompletion(t *testing.T) {
 			expectErr:     true,
 			expectErrType: gopenrouter.ErrCompletionStreamNotSupported,
 		},
+		{
+			name: "NoChoices",
+			request: gopenrouter.NewCompletionRequestBuilder(
+				"test-model",
+				"Say hello",
+			).Build(),
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprint(w, `{"id":"gen-123","choices":[]}`)
+			},
+			expectErr:     true,
+			expectErrType: gopenrouter.ErrNoChoices,
+		},
 	}
 
 	for _, tc := range cases {
@@ -240,6 +254,16 @@ func TestCompletionRequestBuilder(t *testing.T) {
 		}
 	})
 
+	t.Run("WithPreset", func(t *testing.T) {
+		request := gopenrouter.NewCompletionRequestBuilder(testModel, testPrompt).
+			WithPreset("summarizer").
+			Build()
+
+		if request.Preset != "summarizer" {
+			t.Errorf("Expected preset to be summarizer, got %q", request.Preset)
+		}
+	})
+
 	t.Run("WithAllScalarOptions", func(t *testing.T) {
 		stream := true
 		maxTokens := 100
@@ -255,6 +279,7 @@ func TestCompletionRequestBuilder(t *testing.T) {
 		topA := 0.8
 		logprobs := true
 		stop := []string{"stop1", "stop2"}
+		user := "test-user"
 
 		builder := gopenrouter.NewCompletionRequestBuilder(testModel, testPrompt)
 		request := builder.
@@ -272,6 +297,7 @@ func TestCompletionRequestBuilder(t *testing.T) {
 			WithTopA(topA).
 			WithLogprobs(logprobs).
 			WithStop(stop).
+			WithUser(user).
 			Build()
 
 		if *request.Stream != stream {
@@ -313,9 +339,12 @@ func TestCompletionRequestBuilder(t *testing.T) {
 		if *request.Logprobs != logprobs {
 			t.Errorf("Expected Logprobs to be %v, got %v", logprobs, *request.Logprobs)
 		}
-		if !reflect.DeepEqual(request.Stop, stop) {
+		if !reflect.DeepEqual(request.Stop, gopenrouter.Stop(stop)) {
 			t.Errorf("Expected Stop to be %v, got %v", stop, request.Stop)
 		}
+		if request.User == nil || *request.User != user {
+			t.Errorf("Expected User to be %s, got %v", user, request.User)
+		}
 	})
 
 	t.Run("WithArrayAndMapOptions", func(t *testing.T) {
@@ -341,7 +370,7 @@ func TestCompletionRequestBuilder(t *testing.T) {
 		if !reflect.DeepEqual(request.LogitBias, logitBias) {
 			t.Errorf("Expected LogitBias to be %v, got %v", logitBias, request.LogitBias)
 		}
-		if !reflect.DeepEqual(request.Stop, stop) {
+		if !reflect.DeepEqual(request.Stop, gopenrouter.Stop(stop)) {
 			t.Errorf("Expected Stop to be %v, got %v", stop, request.Stop)
 		}
 	})
@@ -360,6 +389,28 @@ func TestCompletionRequestBuilder(t *testing.T) {
 		}
 	})
 
+	t.Run("WithStreamIncludeUsageOption", func(t *testing.T) {
+		builder := gopenrouter.NewCompletionRequestBuilder(testModel, testPrompt)
+		request := builder.
+			WithStreamIncludeUsage(true).
+			Build()
+
+		if request.StreamOptions == nil {
+			t.Fatal("Expected StreamOptions to be non-nil")
+		}
+		if !request.StreamOptions.IncludeUsage {
+			t.Errorf("Expected StreamOptions.IncludeUsage to be true, got %v", request.StreamOptions.IncludeUsage)
+		}
+
+		body, err := json.Marshal(request)
+		if err != nil {
+			t.Fatalf("Failed to marshal request: %v", err)
+		}
+		if !strings.Contains(string(body), `"stream_options":{"include_usage":true}`) {
+			t.Errorf("Expected stream_options in request body, got %s", body)
+		}
+	})
+
 	t.Run("WithReasoningOption", func(t *testing.T) {
 		maxTokens := 50
 		exclude := true
@@ -408,6 +459,30 @@ func TestCompletionRequestBuilder(t *testing.T) {
 	})
 }
 
+func TestUsageOptionsMarshal(t *testing.T) {
+	request := gopenrouter.NewCompletionRequestBuilder("test-model", "test-prompt").
+		WithUsage(true).
+		Build()
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+
+	var body struct {
+		Usage map[string]any `json:"usage"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatalf("Failed to unmarshal request: %v", err)
+	}
+	if body.Usage["include"] != true {
+		t.Errorf("Expected usage.include to be true, got %v", body.Usage)
+	}
+	if _, ok := body.Usage["usage"]; ok {
+		t.Errorf("Expected no usage.usage field, got %v", body.Usage)
+	}
+}
+
 func TestProviderOptionsBuilder(t *testing.T) {
 	t.Run("EmptyBuilder", func(t *testing.T) {
 		builder := gopenrouter.NewProviderOptionsBuilder()
@@ -422,6 +497,9 @@ func TestProviderOptionsBuilder(t *testing.T) {
 		if options.DataCollection != "" {
 			t.Errorf("Expected DataCollection to be empty, got %q", options.DataCollection)
 		}
+		if options.ZDR != nil {
+			t.Errorf("Expected ZDR to be nil, got %v", *options.ZDR)
+		}
 		if options.Sort != "" {
 			t.Errorf("Expected Sort to be empty, got %q", options.Sort)
 		}
@@ -437,6 +515,7 @@ func TestProviderOptionsBuilder(t *testing.T) {
 			WithAllowFallbacks(allowFallbacks).
 			WithRequireParameters(requireParams).
 			WithForceChatCompletions(forceChatCompletions).
+			WithZDR(true).
 			Build()
 
 		if *options.AllowFallbacks != allowFallbacks {
@@ -445,6 +524,9 @@ func TestProviderOptionsBuilder(t *testing.T) {
 		if *options.RequireParameters != requireParams {
 			t.Errorf("Expected RequireParameters to be %v, got %v", requireParams, *options.RequireParameters)
 		}
+		if options.ZDR == nil || !*options.ZDR {
+			t.Errorf("Expected ZDR to be true, got %v", options.ZDR)
+		}
 		if options.Experimental == nil {
 			t.Fatal("Expected Experimental to be non-nil")
 		}
@@ -565,6 +647,54 @@ func TestProviderOptionsBuilder(t *testing.T) {
 		}
 	})
 
+	t.Run("SortOptions", func(t *testing.T) {
+		options := gopenrouter.NewProviderOptionsBuilder().
+			WithSortStrategy(gopenrouter.SortThroughput).
+			Build()
+		if options.Sort != "throughput" {
+			t.Errorf("Expected Sort to be 'throughput', got %q", options.Sort)
+		}
+
+		options = gopenrouter.NewProviderOptionsBuilder().
+			WithSort("fastest").
+			Build()
+		if options.Sort != "fastest" {
+			t.Errorf("Expected unknown sort to be kept, got %q", options.Sort)
+		}
+
+		for _, input := range []string{"price", " Latency ", "throughput"} {
+			if _, err := gopenrouter.ParseProviderSort(input); err != nil {
+				t.Errorf("Expected %q to parse, got %v", input, err)
+			}
+		}
+		if sort, err := gopenrouter.ParseProviderSort("cheapest"); err == nil {
+			t.Errorf("Expected error for unknown sort, got %q", sort)
+		}
+	})
+
+	t.Run("PerformanceOptions", func(t *testing.T) {
+		options := gopenrouter.NewProviderOptionsBuilder().
+			WithPreferredMinThroughput(50).
+			WithPreferredMaxLatency(1.5).
+			Build()
+
+		if options.PreferredMinThroughput == nil || *options.PreferredMinThroughput != 50 {
+			t.Errorf("Expected PreferredMinThroughput to be 50, got %v", options.PreferredMinThroughput)
+		}
+		if options.PreferredMaxLatency == nil || *options.PreferredMaxLatency != 1.5 {
+			t.Errorf("Expected PreferredMaxLatency to be 1.5, got %v", options.PreferredMaxLatency)
+		}
+
+		data, err := json.Marshal(options)
+		if err != nil {
+			t.Fatalf("Failed to marshal options: %v", err)
+		}
+		expected := `{"preferred_min_throughput":50,"preferred_max_latency":1.5}`
+		if string(data) != expected {
+			t.Errorf("Expected JSON %s, got %s", expected, data)
+		}
+	})
+
 	t.Run("MethodChaining", func(t *testing.T) {
 		allowFallbacks := true
 		dataCollection := "deny"
@@ -594,6 +724,70 @@ func TestProviderOptionsBuilder(t *testing.T) {
 	})
 }
 
+func TestStop(t *testing.T) {
+	t.Run("Marshal", func(t *testing.T) {
+		tests := []struct {
+			stop     gopenrouter.Stop
+			expected string
+		}{
+			{gopenrouter.Stop{"END"}, `{"model":"m","prompt":"p","stop":"END"}`},
+			{gopenrouter.Stop{"END", "STOP"}, `{"model":"m","prompt":"p","stop":["END","STOP"]}`},
+			{nil, `{"model":"m","prompt":"p"}`},
+		}
+		for _, test := range tests {
+			data, err := json.Marshal(gopenrouter.CompletionRequest{Model: "m", Prompt: "p", Stop: test.stop})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(data) != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, data)
+			}
+		}
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected gopenrouter.Stop
+		}{
+			{`{"stop":"END"}`, gopenrouter.Stop{"END"}},
+			{`{"stop":["END","STOP"]}`, gopenrouter.Stop{"END", "STOP"}},
+			{`{"stop":null}`, nil},
+		}
+		for _, test := range tests {
+			var request gopenrouter.ChatCompletionRequest
+			if err := json.Unmarshal([]byte(test.input), &request); err != nil {
+				t.Fatalf("Unexpected error decoding %s: %v", test.input, err)
+			}
+			if !reflect.DeepEqual(request.Stop, test.expected) {
+				t.Errorf("Expected %v decoding %s, got %v", test.expected, test.input, request.Stop)
+			}
+		}
+
+		var request gopenrouter.ChatCompletionRequest
+		if err := json.Unmarshal([]byte(`{"stop":42}`), &request); err == nil {
+			t.Error("Expected error for a non-string stop")
+		}
+	})
+
+	t.Run("WithStopSequence", func(t *testing.T) {
+		completion := gopenrouter.NewCompletionRequestBuilder("m", "p").
+			WithStopSequence("END").
+			Build()
+		if !reflect.DeepEqual(completion.Stop, gopenrouter.Stop{"END"}) {
+			t.Errorf("Expected Stop to be [END], got %v", completion.Stop)
+		}
+
+		chat := gopenrouter.NewChatCompletionRequestBuilder("m", nil).
+			WithStop([]string{"END"}).
+			WithStopSequence("STOP").
+			Build()
+		if !reflect.DeepEqual(chat.Stop, gopenrouter.Stop{"END", "STOP"}) {
+			t.Errorf("Expected Stop to be [END STOP], got %v", chat.Stop)
+		}
+	})
+}
+
 func TestCompletionStream(t *testing.T) {
 	t.Run("SuccessfulStream", func(t *testing.T) {
 		// Mock server that sends streaming response
@@ -770,6 +964,42 @@ func TestCompletionStream(t *testing.T) {
 		}
 	})
 
+	t.Run("StreamWithLogProbs", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`data: {"id":"cmpl-1","choices":[{"index":0,"text":"Hi","logprobs":{"content":[{"token":"Hi","bytes":[72,105],"logprob":-0.25,"top_logprobs":[{"token":"Hi","logprob":-0.25},{"token":"Hey","logprob":-1.5}]}]}}]}` + "\n\n"))
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "test prompt").Build()
+
+		stream, err := client.CompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("CompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		chunk, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+
+		logProbs := chunk.Choices[0].LogProbs
+		if logProbs == nil || len(logProbs.Content) != 1 {
+			t.Fatalf("Expected 1 logprob token, got %v", logProbs)
+		}
+		token := logProbs.Content[0]
+		if token.Token != "Hi" || token.LogProb != -0.25 {
+			t.Errorf("Expected token 'Hi' with logprob -0.25, got '%s' with %f", token.Token, token.LogProb)
+		}
+		if len(token.TopLogProbs) != 2 || token.TopLogProbs[1].Token != "Hey" {
+			t.Errorf("Expected 2 top logprobs with 'Hey' second, got %v", token.TopLogProbs)
+		}
+	})
+
 	t.Run("ServerError", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
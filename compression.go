@@ -0,0 +1,73 @@
+package gopenrouter
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding lists the content encodings decompressed by the client.
+const acceptEncoding = "gzip, deflate"
+
+// decompressBody replaces the body of a gzip or deflate encoded response with a reader
+// of the decoded content, as http.Transport does when it negotiates compression itself.
+// Responses decoded by the transport no longer carry a Content-Encoding header and are
+// left untouched.
+//
+// Non-streaming requests set Accept-Encoding themselves, so compression is negotiated
+// regardless of the HTTP client, including custom HTTPDoers and transports with
+// DisableCompression set. This turns off the transparent gzip decoding of http.Transport
+// for those requests, which decompressBody takes over. Streams leave the header unset.
+func decompressBody(resp *http.Response) error {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding != "gzip" && encoding != "deflate" {
+		return nil
+	}
+
+	body := bufio.NewReader(resp.Body)
+	var decoder io.ReadCloser
+	var err error
+	if encoding == "gzip" {
+		decoder, err = gzip.NewReader(body)
+	} else {
+		decoder, err = newDeflateReader(body)
+	}
+	if err != nil {
+		_ = resp.Body.Close()
+		return fmt.Errorf("error decompressing %s response: %w", encoding, err)
+	}
+
+	resp.Body = &decompressedBody{ReadCloser: decoder, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// newDeflateReader returns a reader of deflate encoded content. Although the deflate
+// content encoding is defined as zlib-wrapped data, some servers send raw deflate
+// data, so the zlib header is detected before choosing the decoder.
+func newDeflateReader(body *bufio.Reader) (io.ReadCloser, error) {
+	header, err := body.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(body)
+	}
+	return flate.NewReader(body), nil
+}
+
+// decompressedBody closes both the decoder and the underlying response body.
+type decompressedBody struct {
+	io.ReadCloser
+	body io.Closer
+}
+
+func (b *decompressedBody) Close() error {
+	_ = b.ReadCloser.Close()
+	return b.body.Close()
+}
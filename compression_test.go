@@ -0,0 +1,153 @@
+package gopenrouter_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// compress encodes data with the given content encoding.
+func compress(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Failed to compress data: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to compress data: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCompressedResponses(t *testing.T) {
+	body := []byte(`{"data":{"total_credits":10,"total_usage":1}}`)
+
+	for _, encoding := range []string{"gzip", "deflate", "raw-deflate"} {
+		t.Run(encoding, func(t *testing.T) {
+			var acceptEncoding string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				acceptEncoding = r.Header.Get("Accept-Encoding")
+				w.Header().Set("Content-Encoding", strings.TrimPrefix(encoding, "raw-"))
+				_, _ = w.Write(compress(t, encoding, body))
+			}))
+			defer server.Close()
+
+			client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+			data, err := client.GetCredits(context.Background())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if acceptEncoding != "gzip, deflate" {
+				t.Errorf("Expected Accept-Encoding 'gzip, deflate', got '%s'", acceptEncoding)
+			}
+			if data.TotalCredits != 10 {
+				t.Errorf("Expected total credits 10, got %f", data.TotalCredits)
+			}
+			if got := data.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("Expected Content-Encoding to be removed, got '%s'", got)
+			}
+		})
+	}
+
+	t.Run("DisableCompression", func(t *testing.T) {
+		var acceptEncoding string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			acceptEncoding = r.Header.Get("Accept-Encoding")
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write(compress(t, "gzip", body))
+		}))
+		defer server.Close()
+
+		// The transport neither requests nor decodes compression, so the client does both
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithHTTPClient(&http.Client{Transport: &http.Transport{DisableCompression: true}}),
+		)
+		data, err := client.GetCredits(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if acceptEncoding != "gzip, deflate" {
+			t.Errorf("Expected Accept-Encoding 'gzip, deflate', got '%s'", acceptEncoding)
+		}
+		if data.TotalCredits != 10 {
+			t.Errorf("Expected total credits 10, got %f", data.TotalCredits)
+		}
+	})
+
+	t.Run("ErrorResponse", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write(compress(t, "gzip", []byte(`{"error":{"code":401,"message":"invalid key"}}`)))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		_, err := client.GetCredits(context.Background())
+
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got %v", err)
+		}
+		if apiErr.Message != "invalid key" {
+			t.Errorf("Expected message 'invalid key', got '%s'", apiErr.Message)
+		}
+	})
+
+	t.Run("InvalidData", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write(body)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		_, err := client.GetCredits(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "error decompressing gzip response") {
+			t.Errorf("Expected decompression error, got %v", err)
+		}
+	})
+
+	t.Run("CustomAcceptEncoding", func(t *testing.T) {
+		var acceptEncoding string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			acceptEncoding = r.Header.Get("Accept-Encoding")
+			_, _ = w.Write(body)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithHeader("Accept-Encoding", "identity"),
+		)
+		if _, err := client.GetCredits(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if acceptEncoding != "identity" {
+			t.Errorf("Expected Accept-Encoding 'identity', got '%s'", acceptEncoding)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package gopenrouter
+
+import (
+	"context"
+	"fmt"
+)
+
+// ContextLengthError is returned by the context window checks of ModelCatalog when a
+// request would not fit in the context window of its model.
+type ContextLengthError struct {
+	// Model is the model the request is sent to
+	Model string
+	// ContextLength is the maximum number of tokens the model can process
+	ContextLength int
+	// PromptTokens is the estimated number of tokens of the prompt
+	PromptTokens int
+	// MaxTokens is the number of tokens reserved for the completion
+	MaxTokens int
+	// Overflow is the number of tokens by which the request exceeds the context length
+	Overflow int
+}
+
+func (e *ContextLengthError) Error() string {
+	return fmt.Sprintf(
+		"request exceeds the context length of %s by %d tokens: %d prompt tokens and %d max tokens, context length %d",
+		e.Model, e.Overflow, e.PromptTokens, e.MaxTokens, e.ContextLength,
+	)
+}
+
+// CheckChatCompletionFit reports whether a chat completion request fits in the context
+// window of its model before it is sent, so oversized conversations can be truncated
+// without spending credits on a rejected request. Prompt tokens are counted with counter,
+// or ApproximateTokens if nil, and MaxTokens of the request is reserved for the completion.
+//
+// It returns a *ContextLengthError, which IsContextLengthExceeded recognizes, if the request
+// does not fit. Models with an unknown context length are assumed to fit.
+func (m *ModelCatalog) CheckChatCompletionFit(ctx context.Context, request ChatCompletionRequest, counter TokenCounter) error {
+	return m.checkFit(ctx, request.Model, chatPromptTokens(request, counter), maxTokens(request.MaxTokens))
+}
+
+// CheckCompletionFit reports whether a text completion request fits in the context window
+// of its model, as CheckChatCompletionFit does for chat completion requests.
+func (m *ModelCatalog) CheckCompletionFit(ctx context.Context, request CompletionRequest, counter TokenCounter) error {
+	if counter == nil {
+		counter = ApproximateTokens
+	}
+	return m.checkFit(ctx, request.Model, counter(request.Model, request.Prompt), maxTokens(request.MaxTokens))
+}
+
+// checkFit compares the tokens of a request with the context length of the model.
+func (m *ModelCatalog) checkFit(ctx context.Context, model string, promptTokens, maxTokens int) error {
+	contextLength, err := m.ContextLength(ctx, model)
+	if err != nil {
+		return err
+	}
+	if contextLength <= 0 {
+		return nil
+	}
+
+	if overflow := promptTokens + maxTokens - contextLength; overflow > 0 {
+		return &ContextLengthError{
+			Model:         model,
+			ContextLength: contextLength,
+			PromptTokens:  promptTokens,
+			MaxTokens:     maxTokens,
+			Overflow:      overflow,
+		}
+	}
+	return nil
+}
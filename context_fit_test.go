@@ -0,0 +1,85 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestModelCatalogCheckFit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"data": [
+			{"id": "small/model", "context_length": 100},
+			{"id": "unknown/model"}
+		]}`)
+	}))
+	defer ts.Close()
+
+	catalog := gopenrouter.NewModelCatalog(gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL)))
+	ctx := context.Background()
+	// One token per character
+	counter := func(model, text string) int { return len(text) }
+
+	chat := func(model, content string, maxTokens int) gopenrouter.ChatCompletionRequest {
+		return *gopenrouter.NewChatCompletionRequestBuilder(model, []gopenrouter.ChatMessage{
+			{Role: "user", Content: content},
+		}).WithMaxTokens(maxTokens).Build()
+	}
+
+	t.Run("Fits", func(t *testing.T) {
+		// 40 characters plus 4 tokens of message overhead and 50 max tokens
+		err := catalog.CheckChatCompletionFit(ctx, chat("small/model", strings.Repeat("a", 40), 50), counter)
+		if err != nil {
+			t.Errorf("Expected request to fit, got %v", err)
+		}
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		err := catalog.CheckChatCompletionFit(ctx, chat("small/model", strings.Repeat("a", 60), 50), counter)
+
+		var fitErr *gopenrouter.ContextLengthError
+		if !errors.As(err, &fitErr) {
+			t.Fatalf("Expected ContextLengthError, got %T: %v", err, err)
+		}
+		if fitErr.PromptTokens != 64 || fitErr.MaxTokens != 50 || fitErr.ContextLength != 100 {
+			t.Errorf("Expected 64 prompt tokens, 50 max tokens, and context length 100, got %+v", fitErr)
+		}
+		if fitErr.Overflow != 14 {
+			t.Errorf("Expected overflow of 14 tokens, got %d", fitErr.Overflow)
+		}
+		if !gopenrouter.IsContextLengthExceeded(err) {
+			t.Error("Expected IsContextLengthExceeded to recognize the error")
+		}
+	})
+
+	t.Run("CompletionRequest", func(t *testing.T) {
+		request := gopenrouter.NewCompletionRequestBuilder("small/model", strings.Repeat("a", 101)).Build()
+		err := catalog.CheckCompletionFit(ctx, *request, counter)
+
+		var fitErr *gopenrouter.ContextLengthError
+		if !errors.As(err, &fitErr) || fitErr.Overflow != 1 {
+			t.Errorf("Expected overflow of 1 token, got %v", err)
+		}
+	})
+
+	t.Run("UnknownContextLength", func(t *testing.T) {
+		err := catalog.CheckChatCompletionFit(ctx, chat("unknown/model", strings.Repeat("a", 1000), 1000), counter)
+		if err != nil {
+			t.Errorf("Expected unknown context length to be assumed to fit, got %v", err)
+		}
+	})
+
+	t.Run("UnknownModel", func(t *testing.T) {
+		err := catalog.CheckChatCompletionFit(ctx, chat("missing/model", "hi", 10), counter)
+		if !errors.Is(err, gopenrouter.ErrModelNotFound) {
+			t.Errorf("Expected ErrModelNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package gopenrouter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// messageTokenOverhead approximates the tokens added by the chat template around each message.
+const messageTokenOverhead = 4
+
+// TokenCounter counts the tokens of text as tokenized by the given model. Exact counts
+// require the tokenizer of the model, which can be plugged in through this type.
+type TokenCounter func(model, text string) int
+
+// ApproximateTokens estimates the number of tokens of text with the common rule of thumb
+// of four characters per token. It ignores the model and tends to underestimate code and
+// non-English text, so estimates based on it should include a safety margin.
+func ApproximateTokens(model, text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// CostEstimate is the projected cost of a request, in USD.
+type CostEstimate struct {
+	// PromptTokens is the number of prompt tokens the estimate is based on
+	PromptTokens int
+	// CompletionTokens is the number of completion tokens the estimate is based on
+	CompletionTokens int
+	// PromptCost is the cost of the prompt tokens
+	PromptCost float64
+	// CompletionCost is the cost of the completion tokens
+	CompletionCost float64
+	// RequestCost is the fixed cost per request
+	RequestCost float64
+	// TotalCost is the sum of all costs
+	TotalCost float64
+}
+
+// EstimateCost projects the cost of a request to a model with the given pricing, such as
+// the Pricing of a ModelData, from the expected numbers of prompt and completion tokens.
+// It returns ErrVariablePricing for models whose price depends on the routed model.
+//
+// Example usage:
+//
+//	estimate, err := gopenrouter.EstimateCost(model.Pricing, 1200, 500)
+//	if err == nil && estimate.TotalCost > 0.05 {
+//		// refuse the request
+//	}
+func EstimateCost(pricing ModelPricing, promptTokens, completionTokens int) (CostEstimate, error) {
+	prompt, err := parsePrice("prompt", pricing.Prompt)
+	if err != nil {
+		return CostEstimate{}, err
+	}
+	completion, err := parsePrice("completion", pricing.Completion)
+	if err != nil {
+		return CostEstimate{}, err
+	}
+	request, err := parsePrice("request", pricing.Request)
+	if err != nil {
+		return CostEstimate{}, err
+	}
+
+	estimate := CostEstimate{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		PromptCost:       prompt * float64(promptTokens),
+		CompletionCost:   completion * float64(completionTokens),
+		RequestCost:      request,
+	}
+	estimate.TotalCost = estimate.PromptCost + estimate.CompletionCost + estimate.RequestCost
+	return estimate, nil
+}
+
+// EstimateChatCompletionCost projects the cost of a chat completion request to a model
+// with the given pricing. Prompt tokens are counted with counter, or ApproximateTokens if
+// nil. The completion is assumed to use all MaxTokens of the request, which makes the
+// estimate an upper bound; without MaxTokens, only the prompt is accounted for.
+func EstimateChatCompletionCost(pricing ModelPricing, request ChatCompletionRequest, counter TokenCounter) (CostEstimate, error) {
+	return EstimateCost(pricing, chatPromptTokens(request, counter), maxTokens(request.MaxTokens))
+}
+
+// EstimateCompletionCost projects the cost of a text completion request to a model with
+// the given pricing. Tokens are accounted for as by EstimateChatCompletionCost.
+func EstimateCompletionCost(pricing ModelPricing, request CompletionRequest, counter TokenCounter) (CostEstimate, error) {
+	if counter == nil {
+		counter = ApproximateTokens
+	}
+	return EstimateCost(pricing, counter(request.Model, request.Prompt), maxTokens(request.MaxTokens))
+}
+
+// chatPromptTokens counts the prompt tokens of a chat completion request, including the
+// tool calls of assistant messages.
+func chatPromptTokens(request ChatCompletionRequest, counter TokenCounter) int {
+	if counter == nil {
+		counter = ApproximateTokens
+	}
+	tokens := 0
+	for _, message := range request.Messages {
+		tokens += messageTokenOverhead + counter(request.Model, message.Content)
+		for _, call := range message.ToolCalls {
+			tokens += counter(request.Model, call.Function.Name) + counter(request.Model, call.Function.Arguments)
+		}
+	}
+	return tokens
+}
+
+// maxTokens returns the value of an optional token limit, or zero if unset.
+func maxTokens(limit *int) int {
+	if limit == nil {
+		return 0
+	}
+	return *limit
+}
+
+// parsePrice parses a price per token or per request as reported by OpenRouter.
+// Missing prices are free.
+func parsePrice(name, value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s price %q: %w", name, value, err)
+	}
+	if price < 0 {
+		return 0, ErrVariablePricing
+	}
+	return price, nil
+}
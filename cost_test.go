@@ -0,0 +1,133 @@
+package gopenrouter_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-12
+}
+
+func TestEstimateCost(t *testing.T) {
+	pricing := gopenrouter.ModelPricing{
+		Prompt:     "0.000003",
+		Completion: "0.000015",
+		Request:    "0.001",
+	}
+
+	t.Run("Breakdown", func(t *testing.T) {
+		estimate, err := gopenrouter.EstimateCost(pricing, 1000, 200)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !almostEqual(estimate.PromptCost, 0.003) {
+			t.Errorf("Expected prompt cost 0.003, got %v", estimate.PromptCost)
+		}
+		if !almostEqual(estimate.CompletionCost, 0.003) {
+			t.Errorf("Expected completion cost 0.003, got %v", estimate.CompletionCost)
+		}
+		if !almostEqual(estimate.RequestCost, 0.001) {
+			t.Errorf("Expected request cost 0.001, got %v", estimate.RequestCost)
+		}
+		if !almostEqual(estimate.TotalCost, 0.007) {
+			t.Errorf("Expected total cost 0.007, got %v", estimate.TotalCost)
+		}
+	})
+
+	t.Run("Free", func(t *testing.T) {
+		estimate, err := gopenrouter.EstimateCost(gopenrouter.ModelPricing{Prompt: "0", Completion: "0"}, 1000, 200)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if estimate.TotalCost != 0 {
+			t.Errorf("Expected no cost, got %v", estimate.TotalCost)
+		}
+	})
+
+	t.Run("VariablePricing", func(t *testing.T) {
+		_, err := gopenrouter.EstimateCost(gopenrouter.ModelPricing{Prompt: "-1", Completion: "-1"}, 1000, 200)
+		if !errors.Is(err, gopenrouter.ErrVariablePricing) {
+			t.Errorf("Expected ErrVariablePricing, got %v", err)
+		}
+	})
+
+	t.Run("InvalidPrice", func(t *testing.T) {
+		_, err := gopenrouter.EstimateCost(gopenrouter.ModelPricing{Prompt: "cheap"}, 1000, 200)
+		if err == nil {
+			t.Error("Expected error for invalid price, got nil")
+		}
+	})
+}
+
+func TestEstimateChatCompletionCost(t *testing.T) {
+	pricing := gopenrouter.ModelPricing{Prompt: "0.000001", Completion: "0.000002"}
+	request := gopenrouter.NewChatCompletionRequestBuilder("test-model", []gopenrouter.ChatMessage{
+		{Role: "system", Content: "12345678"},
+		{Role: "user", Content: "1234"},
+	}).WithMaxTokens(100).Build()
+
+	t.Run("CustomCounter", func(t *testing.T) {
+		// One token per character
+		counter := func(model, text string) int { return len(text) }
+		estimate, err := gopenrouter.EstimateChatCompletionCost(pricing, *request, counter)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		// 12 characters plus the overhead of 4 tokens per message
+		if estimate.PromptTokens != 20 {
+			t.Errorf("Expected 20 prompt tokens, got %d", estimate.PromptTokens)
+		}
+		if estimate.CompletionTokens != 100 {
+			t.Errorf("Expected 100 completion tokens, got %d", estimate.CompletionTokens)
+		}
+		if !almostEqual(estimate.TotalCost, 20*0.000001+100*0.000002) {
+			t.Errorf("Expected total cost %v, got %v", 20*0.000001+100*0.000002, estimate.TotalCost)
+		}
+	})
+
+	t.Run("ApproximateCounter", func(t *testing.T) {
+		estimate, err := gopenrouter.EstimateChatCompletionCost(pricing, *request, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		// 2 and 1 tokens plus the overhead of 4 tokens per message
+		if estimate.PromptTokens != 11 {
+			t.Errorf("Expected 11 prompt tokens, got %d", estimate.PromptTokens)
+		}
+	})
+}
+
+func TestEstimateCompletionCost(t *testing.T) {
+	pricing := gopenrouter.ModelPricing{Prompt: "0.000001", Completion: "0.000002"}
+	request := gopenrouter.NewCompletionRequestBuilder("test-model", "0123456789abcdef").Build()
+
+	estimate, err := gopenrouter.EstimateCompletionCost(pricing, *request, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if estimate.PromptTokens != 4 {
+		t.Errorf("Expected 4 prompt tokens, got %d", estimate.PromptTokens)
+	}
+	if estimate.CompletionTokens != 0 {
+		t.Errorf("Expected no completion tokens without MaxTokens, got %d", estimate.CompletionTokens)
+	}
+}
+
+func TestApproximateTokens(t *testing.T) {
+	cases := map[string]int{
+		"":          0,
+		"abc":       1,
+		"abcd":      1,
+		"abcde":     2,
+		"héllo wör": 3,
+	}
+	for text, expect := range cases {
+		if got := gopenrouter.ApproximateTokens("", text); got != expect {
+			t.Errorf("Expected %d tokens for %q, got %d", expect, text, got)
+		}
+	}
+}
@@ -8,12 +8,16 @@ import (
 // creditsResponse represents the internal API response structure when retrieving credits information.
 // It wraps the credits data in a standard response structure.
 type creditsResponse struct {
+	httpHeader
+
 	Data CreditsData `json:"data"`
 }
 
 // CreditsData contains information about a user's credits and usage.
 // This provides visibility into the account's financial standing with OpenRouter.
 type CreditsData struct {
+	httpHeader
+
 	// TotalCredits represents the total amount of credits purchased or added to the account
 	TotalCredits float64 `json:"total_credits"`
 	// TotalUsage represents the total amount of credits consumed by API requests
@@ -47,5 +51,118 @@ func (c *Client) GetCredits(ctx context.Context) (data CreditsData, err error) {
 	}
 
 	data = response.Data
+	data.httpHeader = response.httpHeader
+	return
+}
+
+// coinbaseChargeResponse represents the internal API response structure when creating a Coinbase charge.
+type coinbaseChargeResponse struct {
+	httpHeader
+
+	Data CoinbaseCharge `json:"data"`
+}
+
+// CoinbaseChargeRequest represents a request to purchase credits with cryptocurrency through Coinbase.
+type CoinbaseChargeRequest struct {
+	// Amount is the amount of credits to purchase, in USD
+	Amount float64 `json:"amount"`
+	// Sender is the address of the wallet sending the payment
+	Sender string `json:"sender"`
+	// ChainID is the identifier of the blockchain used for the payment (e.g., 1 for Ethereum, 137 for Polygon, 8453 for Base)
+	ChainID int `json:"chain_id"`
+}
+
+// CoinbaseCharge contains the data needed to complete a credit purchase on-chain.
+type CoinbaseCharge struct {
+	httpHeader
+
+	// ID is the unique identifier of the charge
+	ID string `json:"id"`
+	// CreatedAt is the timestamp when the charge was created
+	CreatedAt string `json:"created_at"`
+	// ExpiresAt is the timestamp after which the charge can no longer be paid
+	ExpiresAt string `json:"expires_at"`
+	// Web3Data contains the transaction to submit from the sender's wallet
+	Web3Data CoinbaseWeb3Data `json:"web3_data"`
+}
+
+// CoinbaseWeb3Data contains the on-chain payment details of a Coinbase charge.
+type CoinbaseWeb3Data struct {
+	// TransferIntent describes the transfer to execute with the Coinbase Commerce contract
+	TransferIntent CoinbaseTransferIntent `json:"transfer_intent"`
+}
+
+// CoinbaseTransferIntent describes a transfer to execute with the Coinbase Commerce contract.
+type CoinbaseTransferIntent struct {
+	// Metadata identifies the chain, contract, and sender of the transfer
+	Metadata CoinbaseTransferMetadata `json:"metadata"`
+	// CallData contains the arguments of the contract call
+	CallData CoinbaseCallData `json:"call_data"`
+}
+
+// CoinbaseTransferMetadata identifies the chain, contract, and sender of a transfer.
+type CoinbaseTransferMetadata struct {
+	// ChainID is the identifier of the blockchain
+	ChainID int `json:"chain_id"`
+	// ContractAddress is the address of the contract to call
+	ContractAddress string `json:"contract_address"`
+	// Sender is the address of the wallet sending the payment
+	Sender string `json:"sender"`
+}
+
+// CoinbaseCallData contains the arguments of a Coinbase Commerce transfer contract call.
+// Amounts are expressed in the smallest unit of the currency.
+type CoinbaseCallData struct {
+	// RecipientAmount is the amount received by OpenRouter
+	RecipientAmount string `json:"recipient_amount"`
+	// Deadline is the time after which the transfer is rejected
+	Deadline string `json:"deadline"`
+	// Recipient is the address receiving the payment
+	Recipient string `json:"recipient"`
+	// RecipientCurrency is the address of the currency received
+	RecipientCurrency string `json:"recipient_currency"`
+	// RefundDestination is the address refunded if the transfer fails
+	RefundDestination string `json:"refund_destination"`
+	// FeeAmount is the fee charged for the transfer
+	FeeAmount string `json:"fee_amount"`
+	// ID is the identifier of the transfer
+	ID string `json:"id"`
+	// Operator is the address of the transfer operator
+	Operator string `json:"operator"`
+	// Signature authorizes the transfer
+	Signature string `json:"signature"`
+	// Prefix is prepended to the signed message
+	Prefix string `json:"prefix"`
+}
+
+// CreateCoinbaseCharge creates a charge for purchasing credits with cryptocurrency.
+//
+// The returned calldata must be submitted as a transaction to the Coinbase Commerce
+// contract from the sender's wallet before the charge expires. Credits are added to
+// the account once the transaction is confirmed.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - request: The amount of credits, the sending wallet, and the chain of the payment
+//
+// Returns:
+//   - CoinbaseCharge: The charge, including the calldata of the transaction to submit
+//   - error: Any error that occurred during the request
+func (c *Client) CreateCoinbaseCharge(ctx context.Context, request CoinbaseChargeRequest) (charge CoinbaseCharge, err error) {
+	urlSuffix := "/credits/coinbase"
+	var response coinbaseChargeResponse
+
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	if err != nil {
+		return
+	}
+
+	charge = response.Data
+	charge.httpHeader = response.httpHeader
 	return
 }
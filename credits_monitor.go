@@ -0,0 +1,150 @@
+package gopenrouter
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultCreditsInterval is the interval at which a credits monitor checks the credits.
+const defaultCreditsInterval = 5 * time.Minute
+
+// CreditsMonitor periodically checks the remaining credits of the account and calls
+// handlers when they fall below configured thresholds, so that services can alert or
+// degrade gracefully before requests fail with ErrInsufficientCredits.
+//
+// The handler of a threshold is called once when the remaining credits fall below it, and
+// again only after they have risen back to or above it, for example after a purchase.
+//
+// A CreditsMonitor is safe for concurrent use.
+type CreditsMonitor struct {
+	client     *Client
+	interval   time.Duration
+	thresholds []creditsThreshold
+	onError    func(error)
+
+	// checkMu serializes checks, so that thresholds are evaluated in order
+	checkMu sync.Mutex
+
+	mu      sync.RWMutex
+	last    CreditsData
+	fetched time.Time
+}
+
+// creditsThreshold is a threshold of remaining credits and its handler.
+type creditsThreshold struct {
+	remaining float64
+	fn        func(CreditsData)
+	below     bool
+}
+
+// CreditsMonitorOption configures a CreditsMonitor.
+type CreditsMonitorOption func(*CreditsMonitor)
+
+// WithCreditsInterval sets the interval at which the credits are checked.
+// The default is five minutes.
+func WithCreditsInterval(interval time.Duration) CreditsMonitorOption {
+	return func(m *CreditsMonitor) {
+		m.interval = interval
+	}
+}
+
+// WithCreditsThreshold adds a handler called with the credits of the account when the
+// remaining credits fall below the given amount.
+func WithCreditsThreshold(remaining float64, fn func(CreditsData)) CreditsMonitorOption {
+	return func(m *CreditsMonitor) {
+		m.thresholds = append(m.thresholds, creditsThreshold{remaining: remaining, fn: fn})
+	}
+}
+
+// WithCreditsErrorHandler sets a function called with the errors of background checks.
+func WithCreditsErrorHandler(fn func(error)) CreditsMonitorOption {
+	return func(m *CreditsMonitor) {
+		m.onError = fn
+	}
+}
+
+// NewCreditsMonitor creates a credits monitor backed by the given client.
+// No request is sent until Check or Start is called.
+func NewCreditsMonitor(client *Client, opts ...CreditsMonitorOption) *CreditsMonitor {
+	m := &CreditsMonitor{
+		client:   client,
+		interval: defaultCreditsInterval,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	// Handlers of lower thresholds are called after those of higher ones
+	slices.SortStableFunc(m.thresholds, func(a, b creditsThreshold) int {
+		switch {
+		case a.remaining > b.remaining:
+			return -1
+		case a.remaining < b.remaining:
+			return 1
+		}
+		return 0
+	})
+	return m
+}
+
+// Remaining returns the credits left on the account.
+func (d CreditsData) Remaining() float64 {
+	return d.TotalCredits - d.TotalUsage
+}
+
+// Check retrieves the credits of the account and calls the handlers of the thresholds
+// that the remaining credits have fallen below.
+func (m *CreditsMonitor) Check(ctx context.Context) (CreditsData, error) {
+	m.checkMu.Lock()
+	defer m.checkMu.Unlock()
+
+	credits, err := m.client.GetCredits(ctx)
+	if err != nil {
+		return credits, err
+	}
+
+	m.mu.Lock()
+	m.last = credits
+	m.fetched = time.Now()
+	m.mu.Unlock()
+
+	remaining := credits.Remaining()
+	for i := range m.thresholds {
+		threshold := &m.thresholds[i]
+		below := remaining < threshold.remaining
+		if below && !threshold.below {
+			threshold.fn(credits)
+		}
+		threshold.below = below
+	}
+	return credits, nil
+}
+
+// Last returns the credits retrieved by the last successful check, and whether any
+// check has succeeded.
+func (m *CreditsMonitor) Last() (CreditsData, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.last, !m.fetched.IsZero()
+}
+
+// Start checks the credits immediately and then at the configured interval until ctx
+// is done. Errors of the checks are passed to the handler set with
+// WithCreditsErrorHandler.
+func (m *CreditsMonitor) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			if _, err := m.Check(ctx); err != nil && ctx.Err() == nil && m.onError != nil {
+				m.onError(err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,129 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// creditsServer serves the credits of an account with 100 credits, of which usage
+// have been consumed, failing requests while failing is set.
+type creditsServer struct {
+	*httptest.Server
+	usage   atomic.Int64
+	failing atomic.Bool
+}
+
+func newCreditsServer(t *testing.T) *creditsServer {
+	t.Helper()
+	s := &creditsServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if s.failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 500, "message": "Internal error"}}`)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"data": {"total_credits": 100, "total_usage": %d}}`, s.usage.Load())
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestCreditsMonitor(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Thresholds", func(t *testing.T) {
+		server := newCreditsServer(t)
+		var calls []string
+		monitor := gopenrouter.NewCreditsMonitor(
+			gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)),
+			gopenrouter.WithCreditsThreshold(10, func(c gopenrouter.CreditsData) {
+				calls = append(calls, fmt.Sprintf("10:%g", c.Remaining()))
+			}),
+			gopenrouter.WithCreditsThreshold(50, func(c gopenrouter.CreditsData) {
+				calls = append(calls, fmt.Sprintf("50:%g", c.Remaining()))
+			}),
+		)
+
+		for _, usage := range []int64{20, 60, 70, 95, 97, 0, 60} {
+			server.usage.Store(usage)
+			if _, err := monitor.Check(ctx); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		expected := []string{"50:40", "10:5", "50:40"}
+		if !reflect.DeepEqual(calls, expected) {
+			t.Errorf("Expected calls %v, got %v", expected, calls)
+		}
+	})
+
+	t.Run("Last", func(t *testing.T) {
+		server := newCreditsServer(t)
+		monitor := gopenrouter.NewCreditsMonitor(gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)))
+
+		if _, ok := monitor.Last(); ok {
+			t.Error("Expected no credits before the first check")
+		}
+		server.usage.Store(25)
+		if _, err := monitor.Check(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		server.failing.Store(true)
+		if _, err := monitor.Check(ctx); err == nil {
+			t.Error("Expected error, got nil")
+		}
+		last, ok := monitor.Last()
+		if !ok || last.Remaining() != 75 {
+			t.Errorf("Expected last remaining credits 75, got %v (ok=%t)", last.Remaining(), ok)
+		}
+	})
+
+	t.Run("Start", func(t *testing.T) {
+		server := newCreditsServer(t)
+		server.usage.Store(95)
+		server.failing.Store(true)
+
+		var mu sync.Mutex
+		var errs int
+		alerts := make(chan gopenrouter.CreditsData, 1)
+		monitor := gopenrouter.NewCreditsMonitor(
+			gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)),
+			gopenrouter.WithCreditsInterval(10*time.Millisecond),
+			gopenrouter.WithCreditsThreshold(10, func(c gopenrouter.CreditsData) { alerts <- c }),
+			gopenrouter.WithCreditsErrorHandler(func(error) {
+				mu.Lock()
+				errs++
+				mu.Unlock()
+				server.failing.Store(false)
+			}),
+		)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		monitor.Start(ctx)
+
+		select {
+		case c := <-alerts:
+			if c.Remaining() != 5 {
+				t.Errorf("Expected remaining credits 5, got %v", c.Remaining())
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected threshold handler to be called")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if errs != 1 {
+			t.Errorf("Expected 1 error, got %d", errs)
+		}
+	})
+}
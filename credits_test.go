@@ -2,6 +2,7 @@ package gopenrouter_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -88,3 +89,80 @@ func TestClientCredits(t *testing.T) {
 		})
 	}
 }
+
+func TestClientCreateCoinbaseCharge(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var body map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/credits/coinbase" {
+				t.Errorf("Expected POST /credits/coinbase, got %s %s", r.Method, r.URL.Path)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Expected JSON body, got error %v", err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"data": {
+				"id": "charge-1",
+				"created_at": "2025-01-01T00:00:00Z",
+				"expires_at": "2025-01-01T01:00:00Z",
+				"web3_data": {"transfer_intent": {
+					"metadata": {"chain_id": 8453, "contract_address": "0xcontract", "sender": "0xsender"},
+					"call_data": {
+						"recipient_amount": "10000000",
+						"deadline": "1735693200",
+						"recipient": "0xrecipient",
+						"recipient_currency": "0xusdc",
+						"refund_destination": "0xsender",
+						"fee_amount": "100000",
+						"id": "0xid",
+						"operator": "0xoperator",
+						"signature": "0xsignature",
+						"prefix": "0xprefix"
+					}
+				}}
+			}}`)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		charge, err := client.CreateCoinbaseCharge(context.Background(), gopenrouter.CoinbaseChargeRequest{
+			Amount:  10,
+			Sender:  "0xsender",
+			ChainID: 8453,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if body["amount"] != 10.0 || body["sender"] != "0xsender" || body["chain_id"] != 8453.0 {
+			t.Errorf("Expected amount, sender, and chain_id in body, got %v", body)
+		}
+		if charge.ID != "charge-1" {
+			t.Errorf("Expected charge ID charge-1, got %s", charge.ID)
+		}
+		intent := charge.Web3Data.TransferIntent
+		if intent.Metadata.ChainID != 8453 || intent.Metadata.ContractAddress != "0xcontract" {
+			t.Errorf("Expected chain 8453 and contract 0xcontract, got %d and %s", intent.Metadata.ChainID, intent.Metadata.ContractAddress)
+		}
+		if intent.CallData.RecipientAmount != "10000000" || intent.CallData.Signature != "0xsignature" {
+			t.Errorf("Expected recipient amount and signature, got %+v", intent.CallData)
+		}
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 400, "message": "Unsupported chain"}}`)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		_, err := client.CreateCoinbaseCharge(context.Background(), gopenrouter.CoinbaseChargeRequest{Amount: 10, Sender: "0xsender", ChainID: 1234})
+
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got %T: %v", err, err)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package gopenrouter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// redacted replaces the values of sensitive headers in debug output.
+const redacted = "[REDACTED]"
+
+// maxDebugBodySize is the size of the largest response body dumped in full. Larger
+// bodies are truncated, so debug mode does not hold unbounded responses in memory.
+const maxDebugBodySize = 64 << 10
+
+// debugLogger dumps HTTP requests and responses for troubleshooting.
+type debugLogger struct {
+	enabled atomic.Bool
+
+	mu sync.Mutex
+	w  io.Writer
+
+	// redaction hides sensitive values from the dumps
+	redaction Redaction
+}
+
+// WithDebug enables debug mode, dumping every HTTP request and response to w, including
+// headers, bodies, and the lines of event streams. The Authorization header is redacted;
+// further headers and body fields can be redacted with WithDebugRedaction. Response bodies
+// are dumped up to 64 KiB; larger bodies are truncated, or omitted if body fields are
+// redacted. Debug output can be toggled at runtime with SetDebug.
+func WithDebug(w io.Writer) Option {
+	return func(c *Client) {
+		c.debug.mu.Lock()
+		c.debug.w = w
+		c.debug.mu.Unlock()
+		c.debug.enabled.Store(w != nil)
+	}
+}
+
+// SetDebug enables or disables debug output at runtime. It has no effect unless a
+// writer has been configured with WithDebug, and is safe for concurrent use.
+func (c *Client) SetDebug(enabled bool) {
+	c.debug.enabled.Store(enabled)
+}
+
+// active reports whether debug output is enabled and has a destination.
+func (d *debugLogger) active() bool {
+	if !d.enabled.Load() {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.w != nil
+}
+
+// write writes a complete dump to the destination, so concurrent dumps do not interleave.
+func (d *debugLogger) write(p []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.w != nil {
+		_, _ = d.w.Write(p)
+	}
+}
+
+// dumpRequest writes the request line, headers, and body of req.
+func (d *debugLogger) dumpRequest(req *http.Request) {
+	if !d.active() {
+		return
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--> %s %s\n", req.Method, req.URL)
+	writeHeader(&buf, d.redaction.RedactHeader(req.Header))
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			_ = body.Close()
+			writeBody(&buf, d.redaction.RedactBody(data))
+		}
+	}
+	d.write(buf.Bytes())
+}
+
+// dumpResponse writes the status line, headers, and body of the response to req.
+// Event stream bodies are wrapped so their lines are written as they are read;
+// other bodies are read in full and replaced with an in-memory copy.
+func (d *debugLogger) dumpResponse(req *http.Request, resp *http.Response) {
+	if !d.active() {
+		return
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<-- %s %s\n", resp.Status, req.URL)
+	writeHeader(&buf, d.redaction.RedactHeader(resp.Header))
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		d.write(buf.Bytes())
+		resp.Body = &debugStreamBody{ReadCloser: resp.Body, debug: d}
+		return
+	}
+
+	// Only the beginning of large bodies is dumped, leaving the rest to be read, and
+	// limited, by the client
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDebugBodySize+1))
+	if len(data) > maxDebugBodySize {
+		resp.Body = &debugBody{Reader: io.MultiReader(bytes.NewReader(data), resp.Body), body: resp.Body}
+		// Clipped to its length, so appending to the dumped part copies it rather than
+		// overwriting the data still to be read
+		data = data[:maxDebugBodySize:maxDebugBodySize]
+		if len(d.redaction.Fields) > 0 {
+			// The fields of a partial JSON document cannot be redacted
+			fmt.Fprintf(&buf, "\n[body larger than %d bytes omitted]\n\n", maxDebugBodySize)
+		} else {
+			writeBody(&buf, append(d.redaction.RedactBody(data), "\n[truncated]"...))
+		}
+		d.write(buf.Bytes())
+		return
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), &errReader{err: err}))
+	writeBody(&buf, d.redaction.RedactBody(data))
+	d.write(buf.Bytes())
+}
+
+// debugBody reads the dumped beginning of a response body followed by its remainder,
+// and closes the response body.
+type debugBody struct {
+	io.Reader
+	body io.Closer
+}
+
+func (b *debugBody) Close() error {
+	return b.body.Close()
+}
+
+// dumpError writes a transport error returned for req.
+func (d *debugLogger) dumpError(req *http.Request, err error) {
+	if !d.active() {
+		return
+	}
+	d.write(fmt.Appendf(nil, "<-- error %s %s: %v\n\n", req.Method, req.URL, err))
+}
+
+// writeHeader writes the headers sorted by name.
+func writeHeader(buf *bytes.Buffer, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(buf, "%s: %s\n", key, value)
+		}
+	}
+}
+
+// writeBody writes a body separated from the headers by an empty line.
+func writeBody(buf *bytes.Buffer, body []byte) {
+	buf.WriteByte('\n')
+	if len(body) > 0 {
+		buf.Write(body)
+		if body[len(body)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+	buf.WriteByte('\n')
+}
+
+// errReader returns err once the data preceding it has been read. A nil err reads as io.EOF.
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
+
+// debugStreamBody writes the lines of an event stream to the debug output as they are read.
+type debugStreamBody struct {
+	io.ReadCloser
+	debug *debugLogger
+	line  []byte
+}
+
+func (b *debugStreamBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if !b.debug.active() {
+		b.line = b.line[:0]
+		return n, err
+	}
+
+	b.line = append(b.line, p[:n]...)
+	rest := b.line
+	for {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			break
+		}
+		if line := bytes.TrimRight(rest[:i], "\r"); len(line) > 0 {
+			b.debug.write(fmt.Appendf(nil, "<-- %s\n", b.debug.redaction.redactLine(line)))
+		}
+		rest = rest[i+1:]
+	}
+	b.line = append(b.line[:0], rest...)
+	if err != nil && len(b.line) > 0 {
+		b.debug.write(fmt.Appendf(nil, "<-- %s\n", b.debug.redaction.redactLine(b.line)))
+		b.line = b.line[:0]
+	}
+	return n, err
+}
@@ -0,0 +1,156 @@
+package gopenrouter_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestDebug(t *testing.T) {
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	t.Run("RequestAndResponse", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`))
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		client := gopenrouter.New("secret-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithDebug(&buf))
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		response, err := client.ChatCompletion(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Choices[0].Message.Content != "Hi" {
+			t.Errorf("Expected content 'Hi', got '%s'", response.Choices[0].Message.Content)
+		}
+
+		output := buf.String()
+		for _, expected := range []string{
+			"--> POST " + server.URL + "/chat/completions",
+			"Authorization: [REDACTED]",
+			`"model":"test-model"`,
+			"<-- 200 OK " + server.URL + "/chat/completions",
+			`"content":"Hi"`,
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected debug output to contain '%s', got:\n%s", expected, output)
+			}
+		}
+		if strings.Contains(output, "secret-key") {
+			t.Errorf("Expected API key to be redacted, got:\n%s", output)
+		}
+	})
+
+	t.Run("LargeBody", func(t *testing.T) {
+		large := strings.Repeat("x", 1<<20)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"total_credits":10,"total_usage":1,"padding":"` + large + `"}}`))
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithDebug(&buf))
+		data, err := client.GetCredits(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if data.TotalCredits != 10 {
+			t.Errorf("Expected the full body to be decoded, got %v", data.TotalCredits)
+		}
+		if buf.Len() > 128<<10 {
+			t.Errorf("Expected the dump to be truncated, got %d bytes", buf.Len())
+		}
+		if !strings.Contains(buf.String(), "[truncated]") {
+			t.Errorf("Expected the dump to be marked as truncated")
+		}
+
+		buf.Reset()
+		limited := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithDebug(&buf),
+			gopenrouter.WithMaxResponseSize(128<<10),
+		)
+		if _, err := limited.GetCredits(context.Background()); !errors.Is(err, gopenrouter.ErrResponseTooLarge) {
+			t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("StreamLines", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\ndata: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		client := gopenrouter.New("secret-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithDebug(&buf))
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer stream.Close()
+
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		output := buf.String()
+		for _, expected := range []string{
+			"Content-Type: text/event-stream",
+			`<-- data: {"id":"chatcmpl-1"`,
+			"<-- data: [DONE]",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected debug output to contain '%s', got:\n%s", expected, output)
+			}
+		}
+	})
+
+	t.Run("Toggle", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"data":{"total_credits":10,"total_usage":1}}`))
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		client := gopenrouter.New("secret-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithDebug(&buf))
+
+		client.SetDebug(false)
+		if _, err := client.GetCredits(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected no debug output while disabled, got:\n%s", buf.String())
+		}
+
+		client.SetDebug(true)
+		data, err := client.GetCredits(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if data.TotalCredits != 10 {
+			t.Errorf("Expected total credits 10, got %f", data.TotalCredits)
+		}
+		if !strings.Contains(buf.String(), "--> GET "+server.URL+"/credits") {
+			t.Errorf("Expected debug output after enabling, got:\n%s", buf.String())
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package gopenrouter
+
+// requestDefaults holds the values applied to completion and chat completion
+// requests that leave the corresponding fields unset.
+type requestDefaults struct {
+	model       string
+	maxTokens   *int
+	temperature *float64
+	topP        *float64
+	usage       *bool
+	provider    *ProviderOptions
+}
+
+// WithDefaultModel sets the model used by requests that don't specify one.
+func WithDefaultModel(model string) Option {
+	return func(c *Client) {
+		c.defaults.model = model
+	}
+}
+
+// WithDefaultMaxTokens sets the maximum number of tokens generated by requests
+// that don't specify a limit.
+func WithDefaultMaxTokens(maxTokens int) Option {
+	return func(c *Client) {
+		c.defaults.maxTokens = &maxTokens
+	}
+}
+
+// WithDefaultTemperature sets the sampling temperature of requests that don't specify one.
+func WithDefaultTemperature(temperature float64) Option {
+	return func(c *Client) {
+		c.defaults.temperature = &temperature
+	}
+}
+
+// WithDefaultTopP sets the nucleus sampling threshold of requests that don't specify one.
+func WithDefaultTopP(topP float64) Option {
+	return func(c *Client) {
+		c.defaults.topP = &topP
+	}
+}
+
+// WithDefaultUsage sets whether usage information is included in the responses of
+// requests that don't specify usage options.
+func WithDefaultUsage(include bool) Option {
+	return func(c *Client) {
+		c.defaults.usage = &include
+	}
+}
+
+// WithDefaultProviderOptions sets the provider routing preferences of requests that don't
+// specify their own, so policies such as denying data collection or restricting the allowed
+// providers are enforced in one place. Requests setting provider options replace the
+// defaults entirely rather than being merged with them. The options are copied, so later
+// changes to provider do not affect the client.
+func WithDefaultProviderOptions(provider *ProviderOptions) Option {
+	return func(c *Client) {
+		c.defaults.provider = nil
+		if provider != nil {
+			c.defaults.provider = cloneRequest(provider)
+		}
+	}
+}
+
+// applyChat fills the unset fields of a chat completion request with the defaults.
+func (d *requestDefaults) applyChat(request *ChatCompletionRequest) {
+	d.apply(&request.Model, &request.MaxTokens, &request.Temperature, &request.TopP, &request.Usage)
+	d.applyProvider(&request.Provider)
+}
+
+// applyCompletion fills the unset fields of a completion request with the defaults.
+func (d *requestDefaults) applyCompletion(request *CompletionRequest) {
+	d.apply(&request.Model, &request.MaxTokens, &request.Temperature, &request.TopP, &request.Usage)
+	d.applyProvider(&request.Provider)
+}
+
+// apply fills the given request fields with the defaults if they are unset.
+// Pointer defaults are copied, so requests never share them with the client.
+func (d *requestDefaults) apply(model *string, maxTokens **int, temperature **float64, topP **float64, usage **UsageOptions) {
+	if *model == "" {
+		*model = d.model
+	}
+	if *maxTokens == nil && d.maxTokens != nil {
+		value := *d.maxTokens
+		*maxTokens = &value
+	}
+	if *temperature == nil && d.temperature != nil {
+		value := *d.temperature
+		*temperature = &value
+	}
+	if *topP == nil && d.topP != nil {
+		value := *d.topP
+		*topP = &value
+	}
+	if *usage == nil && d.usage != nil {
+		include := *d.usage
+		*usage = &UsageOptions{Include: &include}
+	}
+}
+
+// applyProvider sets a deep copy of the default provider options if the request has none,
+// so requests never share their slices and price limits with the client.
+func (d *requestDefaults) applyProvider(provider **ProviderOptions) {
+	if *provider == nil && d.provider != nil {
+		*provider = cloneRequest(d.provider)
+	}
+}
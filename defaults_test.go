@@ -0,0 +1,215 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// newRecordingServer returns a server decoding every request body into body and
+// replying with the given response.
+func newRecordingServer(t *testing.T, body *map[string]any, response string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*body = nil
+		if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+}
+
+func TestRequestDefaults(t *testing.T) {
+	defaults := []gopenrouter.Option{
+		gopenrouter.WithDefaultModel("default-model"),
+		gopenrouter.WithDefaultMaxTokens(256),
+		gopenrouter.WithDefaultTemperature(0.2),
+		gopenrouter.WithDefaultTopP(0.9),
+		gopenrouter.WithDefaultUsage(true),
+	}
+
+	t.Run("ChatCompletionUnsetFields", func(t *testing.T) {
+		var body map[string]any
+		server := newRecordingServer(t, &body, `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", append(defaults, gopenrouter.WithBaseURL(server.URL))...)
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+		request := gopenrouter.NewChatCompletionRequestBuilder("", messages).Build()
+
+		if _, err := client.ChatCompletion(context.Background(), *request); err != nil {
+			t.Fatalf("ChatCompletion failed: %v", err)
+		}
+
+		if body["model"] != "default-model" {
+			t.Errorf("Expected model 'default-model', got %v", body["model"])
+		}
+		if body["max_tokens"] != float64(256) {
+			t.Errorf("Expected max_tokens 256, got %v", body["max_tokens"])
+		}
+		if body["temperature"] != 0.2 {
+			t.Errorf("Expected temperature 0.2, got %v", body["temperature"])
+		}
+		if body["top_p"] != 0.9 {
+			t.Errorf("Expected top_p 0.9, got %v", body["top_p"])
+		}
+		usage, _ := body["usage"].(map[string]any)
+		if usage["include"] != true {
+			t.Errorf("Expected usage to be included, got %v", body["usage"])
+		}
+		if request.MaxTokens != nil {
+			t.Error("Expected defaults not to modify the caller's request")
+		}
+	})
+
+	t.Run("CompletionExplicitFields", func(t *testing.T) {
+		var body map[string]any
+		server := newRecordingServer(t, &body, `{"id":"cmpl-1","choices":[{"index":0,"text":"Hi"}]}`)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", append(defaults, gopenrouter.WithBaseURL(server.URL))...)
+		request := gopenrouter.NewCompletionRequestBuilder("explicit-model", "Hello").
+			WithMaxTokens(10).
+			WithTemperature(1.5).
+			Build()
+
+		if _, err := client.Completion(context.Background(), *request); err != nil {
+			t.Fatalf("Completion failed: %v", err)
+		}
+
+		if body["model"] != "explicit-model" {
+			t.Errorf("Expected model 'explicit-model', got %v", body["model"])
+		}
+		if body["max_tokens"] != float64(10) {
+			t.Errorf("Expected max_tokens 10, got %v", body["max_tokens"])
+		}
+		if body["temperature"] != 1.5 {
+			t.Errorf("Expected temperature 1.5, got %v", body["temperature"])
+		}
+		if body["top_p"] != 0.9 {
+			t.Errorf("Expected default top_p 0.9, got %v", body["top_p"])
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		var body map[string]any
+		server := newRecordingServer(t, &body, "data: [DONE]\n\n")
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", append(defaults, gopenrouter.WithBaseURL(server.URL))...)
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+		request := gopenrouter.NewChatCompletionRequestBuilder("", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("ChatCompletionStream failed: %v", err)
+		}
+		_ = stream.Close()
+
+		if body["model"] != "default-model" {
+			t.Errorf("Expected model 'default-model', got %v", body["model"])
+		}
+	})
+
+	t.Run("NoDefaults", func(t *testing.T) {
+		var body map[string]any
+		server := newRecordingServer(t, &body, `{"id":"cmpl-1","choices":[{"index":0,"text":"Hi"}]}`)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "Hello").Build()
+
+		if _, err := client.Completion(context.Background(), *request); err != nil {
+			t.Fatalf("Completion failed: %v", err)
+		}
+		for _, field := range []string{"max_tokens", "temperature", "top_p", "usage"} {
+			if _, ok := body[field]; ok {
+				t.Errorf("Expected %s to be omitted, got %v", field, body[field])
+			}
+		}
+	})
+}
+
+func TestDefaultProviderOptions(t *testing.T) {
+	defaultProvider := gopenrouter.NewProviderOptionsBuilder().
+		WithDataCollection("deny").
+		WithOnly([]string{"OpenAI", "Anthropic"}).
+		Build()
+
+	t.Run("AppliedWhenUnset", func(t *testing.T) {
+		var body map[string]any
+		server := newRecordingServer(t, &body, `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithDefaultProviderOptions(defaultProvider))
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		if _, err := client.ChatCompletion(context.Background(), *request); err != nil {
+			t.Fatalf("ChatCompletion failed: %v", err)
+		}
+
+		provider, _ := body["provider"].(map[string]any)
+		if provider["data_collection"] != "deny" {
+			t.Errorf("Expected data_collection 'deny', got %v", body["provider"])
+		}
+		if only, _ := provider["only"].([]any); len(only) != 2 {
+			t.Errorf("Expected 2 allowed providers, got %v", provider["only"])
+		}
+	})
+
+	t.Run("Copied", func(t *testing.T) {
+		var body map[string]any
+		server := newRecordingServer(t, &body, `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`)
+		defer server.Close()
+
+		defaults := gopenrouter.NewProviderOptionsBuilder().
+			WithOnly([]string{"OpenAI"}).
+			WithMaxPromptPrice(1).
+			Build()
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithDefaultProviderOptions(defaults))
+		defaults.Only[0] = "Other"
+		*defaults.MaxPrice.Prompt = 2
+
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+		if _, err := client.ChatCompletion(context.Background(), *request); err != nil {
+			t.Fatalf("ChatCompletion failed: %v", err)
+		}
+
+		provider, _ := body["provider"].(map[string]any)
+		if only, _ := provider["only"].([]any); len(only) != 1 || only[0] != "OpenAI" {
+			t.Errorf("Expected the defaults to be copied, got %v", provider["only"])
+		}
+		if maxPrice, _ := provider["max_price"].(map[string]any); maxPrice["prompt"] != 1.0 {
+			t.Errorf("Expected the price limit to be copied, got %v", provider["max_price"])
+		}
+	})
+
+	t.Run("RequestOptionsTakePrecedence", func(t *testing.T) {
+		var body map[string]any
+		server := newRecordingServer(t, &body, `{"id":"cmpl-1","choices":[{"index":0,"text":"Hi"}]}`)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithDefaultProviderOptions(defaultProvider))
+		provider := gopenrouter.NewProviderOptionsBuilder().WithSort("price").Build()
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "Hello").WithProvider(provider).Build()
+
+		if _, err := client.Completion(context.Background(), *request); err != nil {
+			t.Fatalf("Completion failed: %v", err)
+		}
+
+		sent, _ := body["provider"].(map[string]any)
+		if sent["sort"] != "price" {
+			t.Errorf("Expected sort 'price', got %v", body["provider"])
+		}
+		if _, ok := sent["data_collection"]; ok {
+			t.Errorf("Expected default provider options not to be merged, got %v", body["provider"])
+		}
+	})
+}
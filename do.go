@@ -0,0 +1,64 @@
+package gopenrouter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Do sends a request to an arbitrary OpenRouter API endpoint, such as a new or beta
+// endpoint without a typed method yet. The request is authenticated and sent like the
+// typed methods, including custom headers, retries, hooks, and error handling.
+//
+// The path is relative to the base URL of the client (e.g., "/models/count") and may
+// include a query string. A non-nil body is sent as JSON, unless it is an io.Reader,
+// which is sent as is. The JSON response is decoded into out, unless out is nil.
+//
+// Example usage:
+//
+//	var out struct {
+//		Data map[string]any `json:"data"`
+//	}
+//	err := client.Do(ctx, http.MethodGet, "/models/count", nil, &out)
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - method: The HTTP method of the request
+//   - path: The path of the endpoint, relative to the base URL
+//   - body: The request body, or nil
+//   - out: A pointer to a value the JSON response is decoded into, or nil
+//   - opts: Options applied to this request only, such as extra headers
+//
+// Returns:
+//   - error: Any error that occurred during the request
+func (c *Client) Do(ctx context.Context, method, path string, body, out any, opts ...CallOption) error {
+	u, err := url.Parse(path)
+	if err != nil {
+		return fmt.Errorf("error parsing path: %w", err)
+	}
+	if u.IsAbs() || u.Host != "" {
+		return fmt.Errorf("path %q must be relative to the base URL", path)
+	}
+
+	query := u.Query()
+	setters := []requestOption{
+		func(args *requestOptions) {
+			for key, values := range query {
+				args.params[key] = append(args.params[key], values...)
+			}
+		},
+		withCallOptions(opts),
+	}
+	if body != nil {
+		setters = append(setters, withBody(body))
+	}
+
+	urlSuffix := "/" + strings.TrimPrefix(u.EscapedPath(), "/")
+	req, err := c.newRequest(ctx, method, c.fullURL(urlSuffix), setters...)
+	if err != nil {
+		return err
+	}
+
+	return c.sendRequest(req, out)
+}
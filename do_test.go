@@ -0,0 +1,138 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestClientDo(t *testing.T) {
+	t.Run("JSONBodyAndResponse", func(t *testing.T) {
+		var body map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/api/v1/beta/things" {
+				t.Errorf("Expected POST /api/v1/beta/things, got %s %s", r.Method, r.URL.Path)
+			}
+			if auth := r.Header.Get("Authorization"); auth != "Bearer test-key" {
+				t.Errorf("Expected authorization header, got %q", auth)
+			}
+			if r.Header.Get("X-Custom") != "value" {
+				t.Errorf("Expected client header, got %q", r.Header.Get("X-Custom"))
+			}
+			if r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("Expected JSON content type, got %q", r.Header.Get("Content-Type"))
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Expected JSON body, got error %v", err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"data": {"id": "thing-1"}}`)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL+"/api/v1/"),
+			gopenrouter.WithHeader("X-Custom", "value"),
+		)
+
+		var out struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		err := client.Do(context.Background(), http.MethodPost, "beta/things", map[string]any{"name": "thing"}, &out)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if body["name"] != "thing" {
+			t.Errorf("Expected name in body, got %v", body)
+		}
+		if out.Data.ID != "thing-1" {
+			t.Errorf("Expected ID thing-1, got %q", out.Data.ID)
+		}
+	})
+
+	t.Run("QueryAndCallOptions", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				t.Errorf("Expected GET, got %s", r.Method)
+			}
+			if r.ContentLength > 0 {
+				t.Errorf("Expected no body, got %d bytes", r.ContentLength)
+			}
+			query := r.URL.Query()
+			if query.Get("limit") != "10" || query.Get("trace") != "1" {
+				t.Errorf("Expected limit and trace query parameters, got %q", r.URL.RawQuery)
+			}
+			if r.Header.Get("X-Call") != "call" {
+				t.Errorf("Expected call header, got %q", r.Header.Get("X-Call"))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		err := client.Do(context.Background(), http.MethodGet, "/things?limit=10", nil, nil,
+			gopenrouter.WithCallQueryParam("trace", "1"),
+			gopenrouter.WithCallHeader("X-Call", "call"),
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("ReaderBody", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			if string(data) != `{"raw":true}` {
+				t.Errorf("Expected raw body, got %q", data)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{}`)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		err := client.Do(context.Background(), http.MethodPost, "/raw", strings.NewReader(`{"raw":true}`), nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 429, "message": "Rate limited"}}`)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		err := client.Do(context.Background(), http.MethodGet, "/things", nil, nil)
+
+		if !errors.Is(err, gopenrouter.ErrRateLimited) {
+			t.Errorf("Expected ErrRateLimited, got %v", err)
+		}
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("Expected APIError, got %T: %v", err, err)
+		}
+	})
+
+	t.Run("AbsoluteURL", func(t *testing.T) {
+		client := gopenrouter.New("test-key")
+		err := client.Do(context.Background(), http.MethodGet, "https://example.com/things", nil, nil)
+		if err == nil {
+			t.Error("Expected error for absolute URL, got nil")
+		}
+	})
+}
@@ -5,17 +5,22 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 // endpointsResponse represents the internal API response when retrieving endpoints for a model.
 // It wraps the endpoint data in a standard response structure.
 type endpointsResponse struct {
+	httpHeader
+
 	Data EndpointData `json:"data"`
 }
 
 // EndpointData contains information about a model and its available endpoints.
 // This includes both model metadata and a list of provider-specific endpoints.
 type EndpointData struct {
+	httpHeader
+
 	// ID is the unique identifier for the model
 	ID string `json:"id"`
 	// Name is the human-readable name of the model
@@ -87,18 +92,83 @@ type EndpointPricing struct {
 func (c *Client) ListEndpoints(ctx context.Context, author string, slug string) (data EndpointData, err error) {
 	// URL encode the author and slug to handle special characters
 	urlSuffix := fmt.Sprintf("/models/%s/%s/endpoints", url.PathEscape(author), url.PathEscape(slug))
-	var response endpointsResponse
 
-	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	// Concurrent requests for the same model share a single response
+	ctx, key, err := c.flightKey(ctx, urlSuffix)
 	if err != nil {
 		return
 	}
-
-	err = c.sendRequest(req, &response)
+	response, err := c.endpointsFlight.do(ctx, key, func(ctx context.Context) (response endpointsResponse, err error) {
+		req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+		if err != nil {
+			return
+		}
+		err = c.sendRequest(req, &response)
+		return
+	})
 	if err != nil {
 		return
 	}
 
-	data = response.Data
+	// Copy the shared data, so that callers can modify their own
+	data = deepClone(response.Data)
+	data.setHeader(response.Header().Clone())
 	return
 }
+
+// EndpointsResult holds the outcome of listing the endpoints of one model with
+// ListEndpointsForModels.
+type EndpointsResult struct {
+	// Data is the model information and its endpoints, valid if Err is nil
+	Data EndpointData
+	// Err is the error that occurred while listing the endpoints, if any
+	Err error
+}
+
+// ListEndpointsForModels retrieves the endpoints of many models concurrently, for example
+// to present the providers of several models in a selection UI.
+//
+// Models are identified by their full ID in "author/slug" format, such as "openai/gpt-4o".
+// The number of requests sent at the same time is limited, by default to 8, which can be
+// changed with WithBulkConcurrency. Requests are held back while the most recent response
+// reports that the rate limit is exhausted, and rate limited requests are retried once
+// after the requested delay unless retries are enabled with WithRetry. Failed lookups do
+// not affect the others and are reported in the result of their model.
+//
+// Parameters:
+//   - ctx: The context for the requests, which can be used for cancellation and timeouts
+//   - modelIDs: The IDs of the models whose endpoints to retrieve
+//
+// Returns:
+//   - map[string]EndpointsResult: The endpoints or error of each model, keyed by model ID
+func (c *Client) ListEndpointsForModels(ctx context.Context, modelIDs []string) map[string]EndpointsResult {
+	unique := make([]string, 0, len(modelIDs))
+	seen := make(map[string]bool, len(modelIDs))
+	for _, id := range modelIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	results := make([]EndpointsResult, len(unique))
+	runConcurrently(ctx, len(unique), c.bulkConcurrency, func(ctx context.Context, i int) {
+		author, slug, ok := strings.Cut(unique[i], "/")
+		if !ok || author == "" || slug == "" {
+			results[i].Err = fmt.Errorf("invalid model ID %q: expected author/slug", unique[i])
+			return
+		}
+		results[i].Err = c.callRateLimited(ctx, func(ctx context.Context) (err error) {
+			results[i].Data, err = c.ListEndpoints(ctx, author, slug)
+			return err
+		})
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+
+	byID := make(map[string]EndpointsResult, len(unique))
+	for i, id := range unique {
+		byID[id] = results[i]
+	}
+	return byID
+}
@@ -7,7 +7,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/bkovacki/gopenrouter"
 )
@@ -284,3 +288,86 @@ func TestListEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func TestListEndpointsForModels(t *testing.T) {
+	t.Run("MergedResults", func(t *testing.T) {
+		var attempts atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/models/openai/gpt-4o/endpoints":
+				_, _ = fmt.Fprint(w, `{"data": {"id": "openai/gpt-4o", "endpoints": [{"provider_name": "OpenAI"}, {"provider_name": "Azure"}]}}`)
+			case "/models/anthropic/claude-3.5-sonnet/endpoints":
+				// Rate limit the first attempt
+				if attempts.Add(1) == 1 {
+					w.Header().Set("Retry-After", "0.01")
+					w.WriteHeader(http.StatusTooManyRequests)
+					_, _ = fmt.Fprint(w, `{"error": {"code": 429, "message": "Rate limit exceeded"}}`)
+					return
+				}
+				_, _ = fmt.Fprint(w, `{"data": {"id": "anthropic/claude-3.5-sonnet", "endpoints": [{"provider_name": "Anthropic"}]}}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = fmt.Fprint(w, `{"error": {"code": 404, "message": "Model not found"}}`)
+			}
+		}))
+		defer ts.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL))
+		results := client.ListEndpointsForModels(context.Background(), []string{
+			"openai/gpt-4o", "anthropic/claude-3.5-sonnet", "unknown/model", "invalid",
+		})
+
+		if len(results) != 4 {
+			t.Fatalf("Expected 4 results, got %d", len(results))
+		}
+		if r := results["openai/gpt-4o"]; r.Err != nil || len(r.Data.Endpoints) != 2 {
+			t.Errorf("Expected 2 endpoints for openai/gpt-4o, got %+v", r)
+		}
+		if r := results["anthropic/claude-3.5-sonnet"]; r.Err != nil || len(r.Data.Endpoints) != 1 {
+			t.Errorf("Expected rate limited request to be retried, got %+v", r)
+		}
+		if attempts.Load() != 2 {
+			t.Errorf("Expected 2 attempts for the rate limited model, got %d", attempts.Load())
+		}
+		var apiErr *gopenrouter.APIError
+		if !errors.As(results["unknown/model"].Err, &apiErr) {
+			t.Errorf("Expected APIError for unknown/model, got %v", results["unknown/model"].Err)
+		}
+		if err := results["invalid"].Err; err == nil || !strings.Contains(err.Error(), "author/slug") {
+			t.Errorf("Expected invalid model ID error, got %v", err)
+		}
+	})
+
+	t.Run("WaitsForRateLimitReset", func(t *testing.T) {
+		var last time.Time
+		var gap time.Duration
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			if !last.IsZero() {
+				gap = now.Sub(last)
+			}
+			last = now
+			// Report an exhausted rate limit resetting shortly
+			reset := now.Add(50 * time.Millisecond).UnixMilli()
+			w.Header().Set("X-RateLimit-Limit", "10")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"data": {"endpoints": []}}`)
+		}))
+		defer ts.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL), gopenrouter.WithBulkConcurrency(1))
+		results := client.ListEndpointsForModels(context.Background(), []string{"a/one", "b/two"})
+
+		for id, r := range results {
+			if r.Err != nil {
+				t.Errorf("Expected no error for %s, got %v", id, r.Err)
+			}
+		}
+		if gap < 30*time.Millisecond {
+			t.Errorf("Expected the second request to wait for the rate limit reset, got a gap of %v", gap)
+		}
+	})
+}
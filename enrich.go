@@ -0,0 +1,118 @@
+package gopenrouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Default settings of generation lookups.
+const (
+	defaultGenerationLookupDelay    = time.Second
+	defaultGenerationLookupAttempts = 5
+)
+
+// GenerationHandler receives the generation record of a completion, identified by the
+// ID of its response, or the error that prevented retrieving it.
+type GenerationHandler func(ctx context.Context, id string, data GenerationData, err error)
+
+// GenerationLookup configures the retrieval of generation records for
+// WithGenerationHandler. Generation records become available shortly after a completion
+// ends, so lookups are delayed and retried while the record is not found.
+type GenerationLookup struct {
+	// Delay is the time waited before the first lookup, doubled before each further
+	// attempt. Defaults to one second.
+	Delay time.Duration
+	// MaxAttempts is the number of lookups attempted before giving up. Defaults to 5.
+	MaxAttempts int
+}
+
+// generationEnricher delivers the generation records of completions to a handler.
+type generationEnricher struct {
+	handler GenerationHandler
+	lookup  GenerationLookup
+}
+
+// WithGenerationHandler sets a handler receiving the generation record of each completion
+// and stream, which reports the actual cost, latency, and provider of the request.
+// Records are retrieved in the background once a completion returns or a stream is read
+// to its end, so the handler is called from another goroutine, possibly after the
+// context of the request is done.
+//
+// Example usage:
+//
+//	client := gopenrouter.New(apiKey, gopenrouter.WithGenerationHandler(
+//		func(ctx context.Context, id string, data gopenrouter.GenerationData, err error) {
+//			if err == nil {
+//				billing.Record(id, data.TotalCost)
+//			}
+//		},
+//		gopenrouter.GenerationLookup{},
+//	))
+func WithGenerationHandler(handler GenerationHandler, lookup GenerationLookup) Option {
+	if lookup.Delay <= 0 {
+		lookup.Delay = defaultGenerationLookupDelay
+	}
+	if lookup.MaxAttempts <= 0 {
+		lookup.MaxAttempts = defaultGenerationLookupAttempts
+	}
+	return func(c *Client) {
+		c.enricher = &generationEnricher{handler: handler, lookup: lookup}
+	}
+}
+
+// enrichGeneration retrieves the generation record of a completion in the background
+// and passes it to the handler set with WithGenerationHandler, if any.
+func (c *Client) enrichGeneration(ctx context.Context, id string) {
+	if c.enricher == nil || id == "" {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		data, err := c.lookupGeneration(ctx, id, c.enricher.lookup)
+		c.enricher.handler(ctx, id, data, err)
+	}()
+}
+
+// generationLookup returns the lookup settings of WithGenerationHandler, or the defaults
+// if it is not set.
+func (c *Client) generationLookup() GenerationLookup {
+	if c.enricher != nil {
+		return c.enricher.lookup
+	}
+	return GenerationLookup{Delay: defaultGenerationLookupDelay, MaxAttempts: defaultGenerationLookupAttempts}
+}
+
+// lookupGeneration retrieves a generation record, retrying while it is not found.
+func (c *Client) lookupGeneration(ctx context.Context, id string, lookup GenerationLookup) (data GenerationData, err error) {
+	delay := lookup.Delay
+	for range lookup.MaxAttempts {
+		if err = sleep(ctx, delay); err != nil {
+			return
+		}
+		data, err = c.GetGeneration(ctx, id)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound {
+			return
+		}
+		delay *= 2
+	}
+	return
+}
+
+// generationReporter is implemented by stream chunks identifying their generation.
+type generationReporter interface {
+	generationID() string
+}
+
+// generationID returns the ID of the generation the chunk belongs to.
+func (r *ChatCompletionStreamResponse) generationID() string {
+	return r.ID
+}
+
+// generationID returns the ID of the generation the chunk belongs to.
+func (r *CompletionStreamResponse) generationID() string {
+	return r.ID
+}
@@ -0,0 +1,153 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// generationResult is a generation record delivered to a GenerationHandler.
+type generationResult struct {
+	id   string
+	data gopenrouter.GenerationData
+	err  error
+}
+
+// newEnrichServer serves completions and their generation records, which are only
+// found from the given lookup attempt on.
+func newEnrichServer(t *testing.T, foundFrom int32, lookups *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/generation":
+			if lookups.Add(1) < foundFrom {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = fmt.Fprint(w, `{"error": {"code": 404, "message": "Generation not found"}}`)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"data": {"id": %q, "total_cost": 0.002, "provider_name": "OpenAI", "latency": 420}}`, r.URL.Query().Get("id"))
+		case "/chat/completions":
+			if r.Header.Get("Accept") == "text/event-stream" {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = fmt.Fprint(w,
+					"data: {\"id\":\"gen-stream\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n"+
+						"data: [DONE]\n\n")
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"id": "gen-chat", "choices": [{"message": {"role": "assistant", "content": "Hi"}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWithGenerationHandler(t *testing.T) {
+	ctx := context.Background()
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+	lookup := gopenrouter.GenerationLookup{Delay: time.Millisecond, MaxAttempts: 3}
+
+	newClient := func(server *httptest.Server, results chan<- generationResult) *gopenrouter.Client {
+		return gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithGenerationHandler(func(ctx context.Context, id string, data gopenrouter.GenerationData, err error) {
+				results <- generationResult{id: id, data: data, err: err}
+			}, lookup),
+		)
+	}
+
+	receive := func(t *testing.T, results <-chan generationResult) generationResult {
+		t.Helper()
+		select {
+		case result := <-results:
+			return result
+		case <-time.After(time.Second):
+			t.Fatal("Expected generation handler to be called")
+		}
+		return generationResult{}
+	}
+
+	t.Run("ChatCompletion", func(t *testing.T) {
+		var lookups atomic.Int32
+		server := newEnrichServer(t, 2, &lookups)
+		results := make(chan generationResult, 1)
+		client := newClient(server, results)
+
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+		if _, err := client.ChatCompletion(ctx, *request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		result := receive(t, results)
+		if result.err != nil {
+			t.Fatalf("Unexpected error: %v", result.err)
+		}
+		if result.id != "gen-chat" || result.data.ID != "gen-chat" {
+			t.Errorf("Expected generation gen-chat, got %s (data %s)", result.id, result.data.ID)
+		}
+		if result.data.TotalCost != 0.002 || result.data.ProviderName != "OpenAI" || result.data.Latency != 420 {
+			t.Errorf("Unexpected generation data: %+v", result.data)
+		}
+		if n := lookups.Load(); n != 2 {
+			t.Errorf("Expected 2 lookups, got %d", n)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		var lookups atomic.Int32
+		server := newEnrichServer(t, 1, &lookups)
+		results := make(chan generationResult, 1)
+		client := newClient(server, results)
+
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+		stream, err := client.ChatCompletionStream(ctx, *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer stream.Close()
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		result := receive(t, results)
+		if result.err != nil || result.id != "gen-stream" {
+			t.Errorf("Expected generation gen-stream, got %s (err %v)", result.id, result.err)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		var lookups atomic.Int32
+		server := newEnrichServer(t, 10, &lookups)
+		results := make(chan generationResult, 1)
+		client := newClient(server, results)
+
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+		if _, err := client.ChatCompletion(ctx, *request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		result := receive(t, results)
+		var apiErr *gopenrouter.APIError
+		if !errors.As(result.err, &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound {
+			t.Errorf("Expected 404 APIError, got %v", result.err)
+		}
+		if n := lookups.Load(); n != 3 {
+			t.Errorf("Expected 3 lookups, got %d", n)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package gopenrouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ModelResult holds the outcome of sending a request to one model with RunAll or RunFastest.
+type ModelResult struct {
+	// Model is the model the request was sent to
+	Model string
+	// Response is the chat completion response, valid if Err is nil
+	Response ChatCompletionResponse
+	// Err is the error that occurred while completing the request, if any
+	Err error
+	// Latency is the time from sending the request to receiving its response or error
+	Latency time.Duration
+}
+
+// EnsembleResult holds the outcomes of sending the same request to several models.
+type EnsembleResult struct {
+	// Results holds the outcome of each model, in the order the models were given
+	Results []ModelResult
+	// Fastest is the index in Results of the first successful response, or -1 if no
+	// model responded successfully
+	Fastest int
+	// Usage is the summed usage of all responses received. Costs are only reported with
+	// usage accounting enabled, see ChatCompletionRequestBuilder.WithUsage.
+	Usage UsageTotals
+}
+
+// Succeeded returns the results of the models that responded successfully.
+func (r EnsembleResult) Succeeded() []ModelResult {
+	var succeeded []ModelResult
+	for _, result := range r.Results {
+		if result.Err == nil {
+			succeeded = append(succeeded, result)
+		}
+	}
+	return succeeded
+}
+
+// RunAll sends the same chat completion request to each of the given models concurrently
+// and waits for all of them to respond, for example to evaluate models against each other
+// or to build a consensus from their answers.
+//
+// The Model of request is replaced by each model in turn, and its fallback Models are
+// cleared so that every response comes from the model it was sent to. Failed requests do
+// not affect the others and are reported in their result.
+//
+// Parameters:
+//   - ctx: The context for the requests, which can be used for cancellation and timeouts
+//   - request: The chat completion request sent to every model
+//   - models: The models to send the request to
+//   - opts: Call options applied to every request
+//
+// Returns:
+//   - EnsembleResult: The response or error of each model and their summed usage
+func (c *Client) RunAll(ctx context.Context, request ChatCompletionRequest, models []string, opts ...CallOption) EnsembleResult {
+	return c.runEnsemble(ctx, request, models, false, opts)
+}
+
+// RunFastest sends the same chat completion request to each of the given models
+// concurrently and returns as soon as one of them responds successfully, cancelling the
+// requests still in flight. This trades the cost of the extra requests for lower latency.
+//
+// The Model of request is replaced by each model in turn, and its fallback Models are
+// cleared. The result holds the outcome of every model: cancelled requests report
+// context.Canceled, and the usage only includes the responses received, although
+// cancelled requests may still be charged for the tokens generated until then.
+//
+// Parameters:
+//   - ctx: The context for the requests, which can be used for cancellation and timeouts
+//   - request: The chat completion request sent to every model
+//   - models: The models to race
+//   - opts: Call options applied to every request
+//
+// Returns:
+//   - ModelResult: The first successful response
+//   - EnsembleResult: The outcome of every model and their summed usage
+//   - error: The joined errors of all models if none of them responded successfully
+func (c *Client) RunFastest(ctx context.Context, request ChatCompletionRequest, models []string, opts ...CallOption) (fastest ModelResult, result EnsembleResult, err error) {
+	result = c.runEnsemble(ctx, request, models, true, opts)
+	if result.Fastest >= 0 {
+		fastest = result.Results[result.Fastest]
+		return
+	}
+
+	if len(models) == 0 {
+		err = errors.New("no models to run")
+		return
+	}
+	errs := make([]error, len(result.Results))
+	for i, modelResult := range result.Results {
+		errs[i] = fmt.Errorf("%s: %w", modelResult.Model, modelResult.Err)
+	}
+	err = errors.Join(errs...)
+	return
+}
+
+// runEnsemble sends request to each model concurrently. If race is set, the requests
+// still in flight are cancelled once the first of them succeeds.
+func (c *Client) runEnsemble(ctx context.Context, request ChatCompletionRequest, models []string, race bool, opts []CallOption) EnsembleResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	result := EnsembleResult{Results: make([]ModelResult, len(models)), Fastest: -1}
+	runConcurrently(ctx, len(models), len(models), func(ctx context.Context, i int) {
+		modelRequest := request
+		modelRequest.Model = models[i]
+		modelRequest.Models = nil
+
+		start := time.Now()
+		response, err := c.ChatCompletion(ctx, modelRequest, opts...)
+
+		mu.Lock()
+		defer mu.Unlock()
+		result.Results[i] = ModelResult{Model: models[i], Response: response, Err: err, Latency: time.Since(start)}
+		if response.ID != "" {
+			result.Usage.add(response.Usage)
+		}
+		if err == nil && result.Fastest < 0 {
+			result.Fastest = i
+			if race {
+				cancel()
+			}
+		}
+	}, func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Results[i] = ModelResult{Model: models[i], Err: err}
+	})
+	return result
+}
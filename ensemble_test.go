@@ -0,0 +1,131 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+// ensembleServer answers chat completions after the delay configured for their model,
+// failing for models without a delay.
+func ensembleServer(t *testing.T, delays map[string]time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := gopenroutertest.DecodeChatCompletionRequest(t, r)
+		if len(request.Models) > 0 {
+			t.Errorf("Expected fallback models to be cleared, got %v", request.Models)
+		}
+		delay, ok := delays[request.Model]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":400,"message":"Unknown model"}}`))
+			return
+		}
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(gopenrouter.ChatCompletionResponse{
+			ID:      "gen-" + request.Model,
+			Model:   request.Model,
+			Choices: []gopenrouter.ChatChoice{{Message: gopenrouter.ChatMessage{Role: "assistant", Content: request.Model}}},
+			Usage:   gopenrouter.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15, Cost: 0.5},
+		})
+	}))
+}
+
+func TestRunAll(t *testing.T) {
+	server := ensembleServer(t, map[string]time.Duration{"a/slow": 30 * time.Millisecond, "b/fast": 0})
+	defer server.Close()
+
+	client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+	request := gopenrouter.NewChatCompletionRequestBuilder("ignored", messages).WithModels([]string{"fallback"}).Build()
+
+	result := client.RunAll(context.Background(), *request, []string{"a/slow", "b/fast", "c/broken"})
+
+	if len(result.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(result.Results))
+	}
+	for i, model := range []string{"a/slow", "b/fast"} {
+		modelResult := result.Results[i]
+		if modelResult.Err != nil || modelResult.Model != model || modelResult.Response.Choices[0].Message.Content != model {
+			t.Errorf("Expected the response of %s, got %+v", model, modelResult)
+		}
+	}
+	if result.Results[0].Latency < 30*time.Millisecond {
+		t.Errorf("Expected the latency of the slow model, got %v", result.Results[0].Latency)
+	}
+	var apiErr *gopenrouter.APIError
+	if !errors.As(result.Results[2].Err, &apiErr) || result.Results[2].Model != "c/broken" {
+		t.Errorf("Expected an APIError for c/broken, got %+v", result.Results[2])
+	}
+	if result.Fastest != 1 {
+		t.Errorf("Expected the fast model to be the fastest, got %d", result.Fastest)
+	}
+	if len(result.Succeeded()) != 2 {
+		t.Errorf("Expected 2 successful results, got %d", len(result.Succeeded()))
+	}
+	if result.Usage.Requests != 2 || result.Usage.Cost != 1 || result.Usage.PromptTokens != 20 {
+		t.Errorf("Expected the summed usage of 2 responses, got %+v", result.Usage)
+	}
+}
+
+func TestRunFastest(t *testing.T) {
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+	request := gopenrouter.NewChatCompletionRequestBuilder("ignored", messages).Build()
+
+	t.Run("FirstSuccess", func(t *testing.T) {
+		server := ensembleServer(t, map[string]time.Duration{"a/slow": 5 * time.Second, "b/fast": 10 * time.Millisecond})
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		start := time.Now()
+		fastest, result, err := client.RunFastest(context.Background(), *request, []string{"a/slow", "b/fast", "c/broken"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if time.Since(start) > 2*time.Second {
+			t.Errorf("Expected the slow request to be cancelled, took %v", time.Since(start))
+		}
+		if fastest.Model != "b/fast" || fastest.Response.ID != "gen-b/fast" {
+			t.Errorf("Expected the fast model to win, got %+v", fastest)
+		}
+		if !errors.Is(result.Results[0].Err, context.Canceled) {
+			t.Errorf("Expected the slow request to be cancelled, got %v", result.Results[0].Err)
+		}
+		if result.Usage.Requests != 1 || result.Usage.Cost != 0.5 {
+			t.Errorf("Expected the usage of the winning response, got %+v", result.Usage)
+		}
+	})
+
+	t.Run("AllFailed", func(t *testing.T) {
+		server := ensembleServer(t, nil)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		_, result, err := client.RunFastest(context.Background(), *request, []string{"a/broken", "b/broken"})
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("Expected the joined APIErrors, got %v", err)
+		}
+		if result.Fastest != -1 {
+			t.Errorf("Expected no fastest result, got %d", result.Fastest)
+		}
+	})
+
+	t.Run("NoModels", func(t *testing.T) {
+		client := gopenrouter.New("test-api-key")
+		if _, _, err := client.RunFastest(context.Background(), *request, nil); err == nil {
+			t.Error("Expected an error without models")
+		}
+	})
+}
@@ -1,17 +1,245 @@
 package gopenrouter
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
 )
 
 var ErrCompletionStreamNotSupported = errors.New("streaming is not supported with this method. Use CompletionStream() or ChatCompletionStream() for streaming requests")
 
+// ErrNoChoices is returned by Completion and ChatCompletion when the API responds successfully
+// but without any choices, which can happen when the provider fails to generate a response.
+// The response is still returned, so its ID can be used to look up the generation.
+var ErrNoChoices = errors.New("response contains no choices")
+
+// ErrStreamStalled is returned by stream readers when no data was received within
+// the idle timeout configured with WithStreamIdleTimeout.
+var ErrStreamStalled = errors.New("stream stalled: no data received within the idle timeout")
+
+// ErrStreamTruncated is returned by stream readers when the connection ends before the
+// [DONE] sentinel was received. It wraps io.ErrUnexpectedEOF, so the partial response
+// can be told apart from a complete one and the request retried.
+var ErrStreamTruncated = fmt.Errorf("stream ended before [DONE] was received: %w", io.ErrUnexpectedEOF)
+
+// ErrStreamClosed is reported as the error of streams closed by the consumer before they
+// ended, such as in the StreamEndInfo passed to Hooks.OnStreamEnd.
+var ErrStreamClosed = errors.New("stream closed before it ended")
+
+// ErrVariablePricing is returned by cost estimators for models whose price is not fixed,
+// such as routers whose price depends on the model a request is routed to.
+var ErrVariablePricing = errors.New("model pricing is variable and cannot be estimated")
+
+// ErrModelNotFound is returned by ModelCatalog lookups for models missing from the catalog.
+var ErrModelNotFound = errors.New("model not found")
+
+// ErrNoMatchingModels is returned by ModelCatalog.Select when no model meets the criteria.
+var ErrNoMatchingModels = errors.New("no model matches the criteria")
+
+// ErrBudgetExceeded is returned for completion requests sent once the budget set with
+// WithBudget has been spent.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// ErrResponseTooLarge is returned when a response body exceeds the maximum size set with
+// WithMaxResponseSize.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// Sentinel errors for the HTTP status codes documented by OpenRouter. APIError and
+// RequestError values match them with errors.Is based on their status code, while still
+// being available through errors.As for the full details:
+//
+//	if errors.Is(err, gopenrouter.ErrRateLimited) {
+//	  // back off and retry
+//	}
+var (
+	// ErrInvalidKey indicates invalid credentials, such as a disabled or invalid API key (401)
+	ErrInvalidKey = errors.New("invalid credentials")
+	// ErrInsufficientCredits indicates the account or API key has run out of credits (402)
+	ErrInsufficientCredits = errors.New("insufficient credits")
+	// ErrModerated indicates the input was flagged by moderation (403)
+	ErrModerated = errors.New("input flagged by moderation")
+	// ErrTimeout indicates the request timed out (408)
+	ErrTimeout = errors.New("request timed out")
+	// ErrRateLimited indicates the request was rate limited (429)
+	ErrRateLimited = errors.New("rate limited")
+	// ErrProviderUnavailable indicates the chosen model is down or returned an invalid response (502, 503)
+	ErrProviderUnavailable = errors.New("provider unavailable")
+)
+
+// contextLengthPatterns are lower-case fragments of the messages providers use to report
+// prompts exceeding the context window of a model.
+var contextLengthPatterns = []string{
+	"context_length_exceeded",
+	"context length",
+	"context window",
+	"maximum context",
+	"prompt is too long",
+	"input is too long",
+	"too many tokens",
+	"exceeds the token limit",
+	"reduce the length",
+}
+
+// IsContextLengthExceeded reports whether err indicates that the prompt did not fit in the
+// context window of the model. Providers report this condition with different codes and
+// messages, which are recognized in both the OpenRouter error and the raw provider error.
+// Callers can use it to truncate the conversation and retry the request. Errors of the
+// context window checks of ModelCatalog are recognized as well.
+//
+// Messages are only matched for errors with status 400 or 413, or without a status, so
+// that rate limits on tokens per minute, reported with 429, are not mistaken for it.
+func IsContextLengthExceeded(err error) bool {
+	var fitErr *ContextLengthError
+	if errors.As(err, &fitErr) {
+		return true
+	}
+	if errors.Is(err, ErrRateLimited) {
+		return false
+	}
+
+	var texts []string
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if !isContextLengthStatus(apiErr.statusCode()) {
+			return false
+		}
+		texts = append(texts, apiErr.Message)
+		if metadata, mErr := json.Marshal(apiErr.Metadata); mErr == nil {
+			texts = append(texts, string(metadata))
+		}
+	}
+
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		if !isContextLengthStatus(reqErr.HTTPStatusCode) {
+			return false
+		}
+		texts = append(texts, string(reqErr.Body))
+	}
+
+	if apiErr == nil && reqErr == nil && err != nil {
+		texts = append(texts, err.Error())
+	}
+
+	for _, text := range texts {
+		text = strings.ToLower(text)
+		for _, pattern := range contextLengthPatterns {
+			if strings.Contains(text, pattern) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isContextLengthStatus reports whether an error with the HTTP status code may report a
+// prompt exceeding the context window. A status of zero is unknown.
+func isContextLengthStatus(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusBadRequest || statusCode == http.StatusRequestEntityTooLarge
+}
+
+// IsRetryable reports whether the request that failed with err may succeed if retried.
+// This is the case for timeouts (408), rate limiting (429), server and provider errors (5xx),
+// transport errors, and streams that were stalled or truncated. Cancellation and expiry of
+// the caller's context are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return isRetryableStatus(apiErr.statusCode())
+	}
+
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		return isRetryableStatus(reqErr.HTTPStatusCode)
+	}
+
+	if errors.Is(err, ErrStreamTruncated) || errors.Is(err, ErrStreamStalled) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	// Transport failures, including *url.Error returned by http.Client
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// isRetryableStatus reports whether a request failing with the HTTP status code may be retried.
+func isRetryableStatus(statusCode int) bool {
+	switch {
+	case statusCode == http.StatusRequestTimeout, statusCode == http.StatusTooManyRequests:
+		return true
+	case statusCode == http.StatusNotImplemented:
+		return false
+	default:
+		return statusCode >= http.StatusInternalServerError && statusCode <= 599
+	}
+}
+
+// statusError returns the sentinel error corresponding to an HTTP status code, or nil.
+func statusError(statusCode int) error {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return ErrInvalidKey
+	case http.StatusPaymentRequired:
+		return ErrInsufficientCredits
+	case http.StatusForbidden:
+		return ErrModerated
+	case http.StatusRequestTimeout:
+		return ErrTimeout
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return ErrProviderUnavailable
+	default:
+		return nil
+	}
+}
+
 // APIError provides error information returned by the OpenAI API.
 type APIError struct {
 	Code     int            `json:"code,omitempty"`
 	Message  string         `json:"message"`
 	Metadata map[string]any `json:"metadata,omitempty"`
+	// HTTPStatus is the status text of the HTTP response carrying the error
+	HTTPStatus string `json:"-"`
+	// HTTPStatusCode is the status code of the HTTP response carrying the error
+	HTTPStatusCode int `json:"-"`
+	// Header holds the headers of the HTTP response carrying the error
+	Header http.Header `json:"-"`
+	// RetryAfter is the delay requested by the Retry-After header, if present
+	RetryAfter time.Duration `json:"-"`
+	// RateLimit holds the rate limit state reported in the X-RateLimit-* headers, if present
+	RateLimit *RateLimitInfo `json:"-"`
+}
+
+// ModerationErrorMetadata describes why an input was flagged by moderation.
+type ModerationErrorMetadata struct {
+	// Reasons lists why the input was flagged
+	Reasons []string `json:"reasons"`
+	// FlaggedInput is the text segment that was flagged, limited to 100 characters
+	FlaggedInput string `json:"flagged_input"`
+	// ProviderName is the name of the provider that requested moderation
+	ProviderName string `json:"provider_name"`
+	// ModelSlug is the model the request was sent to
+	ModelSlug string `json:"model_slug"`
+}
+
+// ProviderErrorMetadata describes an error returned by the upstream provider.
+type ProviderErrorMetadata struct {
+	// ProviderName is the name of the provider that returned the error
+	ProviderName string `json:"provider_name"`
+	// Raw is the unmodified error returned by the provider
+	Raw json.RawMessage `json:"raw"`
 }
 
 // RequestError provides information about generic request errors.
@@ -20,6 +248,16 @@ type RequestError struct {
 	HTTPStatusCode int
 	Err            error
 	Body           []byte
+	// Method is the HTTP method of the failed request
+	Method string
+	// URL is the URL of the failed request
+	URL string
+	// Header holds the headers of the HTTP response carrying the error
+	Header http.Header
+	// RetryAfter is the delay requested by the Retry-After header, if present
+	RetryAfter time.Duration
+	// RateLimit holds the rate limit state reported in the X-RateLimit-* headers, if present
+	RateLimit *RateLimitInfo
 }
 
 type ErrorResponse struct {
@@ -33,7 +271,73 @@ func (e *APIError) Error() string {
 	return e.Message
 }
 
+// Is reports whether the error code corresponds to the target status code sentinel error.
+// The HTTP status code is used if the error body did not include a code.
+func (e *APIError) Is(target error) bool {
+	sentinel := statusError(e.statusCode())
+	return sentinel != nil && sentinel == target
+}
+
+// statusCode returns the error code, falling back to the HTTP status code.
+func (e *APIError) statusCode() int {
+	if e.Code != 0 {
+		return e.Code
+	}
+	return e.HTTPStatusCode
+}
+
+// RequestID returns the identifier of the failed request, which can be referenced when
+// reporting issues to OpenRouter support. It returns an empty string if unavailable.
+func (e *APIError) RequestID() string {
+	return requestID(e.Header)
+}
+
+// ModerationMetadata returns the metadata of a moderation error.
+// It reports false if the error does not carry moderation metadata.
+func (e *APIError) ModerationMetadata() (*ModerationErrorMetadata, bool) {
+	if _, ok := e.Metadata["reasons"]; !ok {
+		if _, ok := e.Metadata["flagged_input"]; !ok {
+			return nil, false
+		}
+	}
+
+	var metadata ModerationErrorMetadata
+	if !e.decodeMetadata(&metadata) {
+		return nil, false
+	}
+	return &metadata, true
+}
+
+// ProviderMetadata returns the metadata of an error returned by the upstream provider.
+// It reports false if the error does not carry provider metadata.
+func (e *APIError) ProviderMetadata() (*ProviderErrorMetadata, bool) {
+	if _, ok := e.Metadata["raw"]; !ok {
+		return nil, false
+	}
+
+	var metadata ProviderErrorMetadata
+	if !e.decodeMetadata(&metadata) {
+		return nil, false
+	}
+	return &metadata, true
+}
+
+// decodeMetadata decodes the untyped metadata into v, reporting whether it succeeded.
+func (e *APIError) decodeMetadata(v any) bool {
+	data, err := json.Marshal(e.Metadata)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
 func (e *RequestError) Error() string {
+	if e.Method != "" || e.URL != "" {
+		return fmt.Sprintf(
+			"error, request: %s %s, status code: %d, status: %s, message: %s, body: %s",
+			e.Method, e.URL, e.HTTPStatusCode, e.HTTPStatus, e.Err, e.Body,
+		)
+	}
 	return fmt.Sprintf(
 		"error, status code: %d, status: %s, message: %s, body: %s",
 		e.HTTPStatusCode, e.HTTPStatus, e.Err, e.Body,
@@ -43,3 +347,15 @@ func (e *RequestError) Error() string {
 func (e *RequestError) Unwrap() error {
 	return e.Err
 }
+
+// Is reports whether the HTTP status code corresponds to the target status code sentinel error.
+func (e *RequestError) Is(target error) bool {
+	sentinel := statusError(e.HTTPStatusCode)
+	return sentinel != nil && sentinel == target
+}
+
+// RequestID returns the identifier of the failed request, which can be referenced when
+// reporting issues to OpenRouter support. It returns an empty string if unavailable.
+func (e *RequestError) RequestID() string {
+	return requestID(e.Header)
+}
@@ -0,0 +1,378 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestStatusErrors(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		body       string
+		expectErr  error
+	}{
+		{
+			name:       "InvalidKey",
+			statusCode: http.StatusUnauthorized,
+			body:       `{"error":{"code":401,"message":"No auth credentials found"}}`,
+			expectErr:  gopenrouter.ErrInvalidKey,
+		},
+		{
+			name:       "InsufficientCredits",
+			statusCode: http.StatusPaymentRequired,
+			body:       `{"error":{"code":402,"message":"Insufficient credits"}}`,
+			expectErr:  gopenrouter.ErrInsufficientCredits,
+		},
+		{
+			name:       "Moderated",
+			statusCode: http.StatusForbidden,
+			body:       `{"error":{"code":403,"message":"Input was flagged"}}`,
+			expectErr:  gopenrouter.ErrModerated,
+		},
+		{
+			name:       "Timeout",
+			statusCode: http.StatusRequestTimeout,
+			body:       `{"error":{"code":408,"message":"Request timed out"}}`,
+			expectErr:  gopenrouter.ErrTimeout,
+		},
+		{
+			name:       "RateLimited",
+			statusCode: http.StatusTooManyRequests,
+			body:       `{"error":{"code":429,"message":"Rate limit exceeded"}}`,
+			expectErr:  gopenrouter.ErrRateLimited,
+		},
+		{
+			name:       "BadGateway",
+			statusCode: http.StatusBadGateway,
+			body:       `{"error":{"code":502,"message":"Provider returned error"}}`,
+			expectErr:  gopenrouter.ErrProviderUnavailable,
+		},
+		{
+			name:       "ServiceUnavailableRequestError",
+			statusCode: http.StatusServiceUnavailable,
+			body:       `Service Unavailable`,
+			expectErr:  gopenrouter.ErrProviderUnavailable,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+			_, err := client.GetCredits(context.Background())
+			if !errors.Is(err, tc.expectErr) {
+				t.Errorf("Expected error to match %v, got %v", tc.expectErr, err)
+			}
+
+			var apiErr *gopenrouter.APIError
+			var reqErr *gopenrouter.RequestError
+			if !errors.As(err, &apiErr) && !errors.As(err, &reqErr) {
+				t.Errorf("Expected APIError or RequestError, got %T", err)
+			}
+		})
+	}
+
+	t.Run("HTTPStatusWithoutCode", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"message":"Rate limit exceeded"}}`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		_, err := client.GetCredits(context.Background())
+
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got %T: %v", err, err)
+		}
+		if apiErr.HTTPStatusCode != http.StatusTooManyRequests {
+			t.Errorf("Expected HTTP status code 429, got %d", apiErr.HTTPStatusCode)
+		}
+		if apiErr.HTTPStatus != "429 Too Many Requests" {
+			t.Errorf("Expected HTTP status '429 Too Many Requests', got '%s'", apiErr.HTTPStatus)
+		}
+		if !errors.Is(err, gopenrouter.ErrRateLimited) {
+			t.Errorf("Expected error to match ErrRateLimited, got %v", err)
+		}
+	})
+
+	t.Run("UnmappedStatus", func(t *testing.T) {
+		err := &gopenrouter.APIError{Code: http.StatusBadRequest, Message: "Bad request"}
+		sentinels := []error{
+			gopenrouter.ErrInvalidKey,
+			gopenrouter.ErrInsufficientCredits,
+			gopenrouter.ErrModerated,
+			gopenrouter.ErrTimeout,
+			gopenrouter.ErrRateLimited,
+			gopenrouter.ErrProviderUnavailable,
+		}
+		for _, sentinel := range sentinels {
+			if errors.Is(err, sentinel) {
+				t.Errorf("Expected 400 error not to match %v", sentinel)
+			}
+		}
+	})
+}
+
+func TestErrorRateLimitHeaders(t *testing.T) {
+	t.Run("APIError", func(t *testing.T) {
+		reset := time.Now().Add(time.Minute).Truncate(time.Millisecond)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.Header().Set("X-RateLimit-Limit", "20")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.UnixMilli(), 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":429,"message":"Rate limit exceeded"}}`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		_, err := client.GetCredits(context.Background())
+
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got %T: %v", err, err)
+		}
+		if apiErr.RetryAfter != 30*time.Second {
+			t.Errorf("Expected RetryAfter of 30s, got %v", apiErr.RetryAfter)
+		}
+		if apiErr.RateLimit == nil {
+			t.Fatal("Expected rate limit information")
+		}
+		if apiErr.RateLimit.Limit != 20 || apiErr.RateLimit.Remaining != 0 {
+			t.Errorf("Expected limit 20 with 0 remaining, got %+v", apiErr.RateLimit)
+		}
+		if !apiErr.RateLimit.Reset.Equal(reset) {
+			t.Errorf("Expected reset at %v, got %v", reset, apiErr.RateLimit.Reset)
+		}
+	})
+
+	t.Run("RequestErrorWithHTTPDate", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", time.Now().Add(2*time.Minute).UTC().Format(http.TimeFormat))
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`Service Unavailable`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		_, err := client.GetCredits(context.Background())
+
+		var reqErr *gopenrouter.RequestError
+		if !errors.As(err, &reqErr) {
+			t.Fatalf("Expected RequestError, got %T: %v", err, err)
+		}
+		if reqErr.RetryAfter < time.Minute || reqErr.RetryAfter > 2*time.Minute {
+			t.Errorf("Expected RetryAfter of about 2m, got %v", reqErr.RetryAfter)
+		}
+		if reqErr.RateLimit != nil {
+			t.Errorf("Expected no rate limit information, got %+v", reqErr.RateLimit)
+		}
+	})
+}
+
+func TestRequestErrorRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`Internal Server Error`))
+	}))
+	defer server.Close()
+
+	client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+	_, err := client.GetGeneration(context.Background(), "gen-123")
+
+	var reqErr *gopenrouter.RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("Expected RequestError, got %T: %v", err, err)
+	}
+	if reqErr.Method != http.MethodGet {
+		t.Errorf("Expected method GET, got '%s'", reqErr.Method)
+	}
+	expectedURL := server.URL + "/generation?id=gen-123"
+	if reqErr.URL != expectedURL {
+		t.Errorf("Expected URL '%s', got '%s'", expectedURL, reqErr.URL)
+	}
+	if !strings.Contains(err.Error(), "GET "+expectedURL) {
+		t.Errorf("Expected error message to contain the request, got '%s'", err.Error())
+	}
+}
+
+func TestAPIErrorMetadata(t *testing.T) {
+	decode := func(t *testing.T, body string) *gopenrouter.APIError {
+		t.Helper()
+
+		var response gopenrouter.ErrorResponse
+		if err := json.Unmarshal([]byte(body), &response); err != nil {
+			t.Fatalf("Failed to decode error response: %v", err)
+		}
+		return response.Error
+	}
+
+	t.Run("Moderation", func(t *testing.T) {
+		apiErr := decode(t, `{"error":{"code":403,"message":"Input was flagged","metadata":{"reasons":["harassment","violence"],"flagged_input":"some text","provider_name":"OpenAI","model_slug":"openai/gpt-4o"}}}`)
+
+		metadata, ok := apiErr.ModerationMetadata()
+		if !ok {
+			t.Fatal("Expected moderation metadata")
+		}
+		if len(metadata.Reasons) != 2 || metadata.Reasons[0] != "harassment" {
+			t.Errorf("Expected reasons [harassment violence], got %v", metadata.Reasons)
+		}
+		if metadata.FlaggedInput != "some text" {
+			t.Errorf("Expected flagged input 'some text', got '%s'", metadata.FlaggedInput)
+		}
+		if metadata.ProviderName != "OpenAI" || metadata.ModelSlug != "openai/gpt-4o" {
+			t.Errorf("Expected provider 'OpenAI' and model 'openai/gpt-4o', got '%s' and '%s'", metadata.ProviderName, metadata.ModelSlug)
+		}
+		if _, ok := apiErr.ProviderMetadata(); ok {
+			t.Error("Expected no provider metadata on a moderation error")
+		}
+	})
+
+	t.Run("Provider", func(t *testing.T) {
+		apiErr := decode(t, `{"error":{"code":502,"message":"Provider returned error","metadata":{"provider_name":"Anthropic","raw":{"type":"overloaded_error","message":"Overloaded"}}}}`)
+
+		metadata, ok := apiErr.ProviderMetadata()
+		if !ok {
+			t.Fatal("Expected provider metadata")
+		}
+		if metadata.ProviderName != "Anthropic" {
+			t.Errorf("Expected provider 'Anthropic', got '%s'", metadata.ProviderName)
+		}
+
+		var raw struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(metadata.Raw, &raw); err != nil || raw.Type != "overloaded_error" {
+			t.Errorf("Expected raw error of type 'overloaded_error', got %s", metadata.Raw)
+		}
+		if _, ok := apiErr.ModerationMetadata(); ok {
+			t.Error("Expected no moderation metadata on a provider error")
+		}
+	})
+
+	t.Run("NoMetadata", func(t *testing.T) {
+		apiErr := decode(t, `{"error":{"code":400,"message":"Bad request"}}`)
+
+		if _, ok := apiErr.ModerationMetadata(); ok {
+			t.Error("Expected no moderation metadata")
+		}
+		if _, ok := apiErr.ProviderMetadata(); ok {
+			t.Error("Expected no provider metadata")
+		}
+	})
+}
+
+func TestIsContextLengthExceeded(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		expect bool
+	}{
+		{
+			name:   "OpenAICode",
+			err:    &gopenrouter.APIError{Code: 400, Message: "Provider returned error", Metadata: map[string]any{"raw": `{"error":{"code":"context_length_exceeded"}}`}},
+			expect: true,
+		},
+		{
+			name:   "OpenRouterMessage",
+			err:    &gopenrouter.APIError{Code: 400, Message: "This endpoint's maximum context length is 8192 tokens. However, you requested about 9000 tokens."},
+			expect: true,
+		},
+		{
+			name:   "AnthropicMessage",
+			err:    &gopenrouter.APIError{Code: 400, Message: "prompt is too long: 210000 tokens > 200000 maximum"},
+			expect: true,
+		},
+		{
+			name:   "RequestErrorBody",
+			err:    &gopenrouter.RequestError{HTTPStatusCode: 413, Body: []byte("Input is too long for requested model.")},
+			expect: true,
+		},
+		{
+			name:   "WrappedError",
+			err:    fmt.Errorf("chat failed: %w", &gopenrouter.APIError{Code: 400, Message: "Please reduce the length of the messages."}),
+			expect: true,
+		},
+		{
+			name:   "OtherError",
+			err:    &gopenrouter.APIError{Code: 400, Message: "Invalid model"},
+			expect: false,
+		},
+		{
+			name:   "TokensPerMinuteRateLimit",
+			err:    &gopenrouter.APIError{Code: 429, Message: "Rate limit reached: too many tokens per minute. Please try again later."},
+			expect: false,
+		},
+		{
+			name:   "TokensPerMinuteRequestError",
+			err:    &gopenrouter.RequestError{HTTPStatusCode: 429, Body: []byte("Too many tokens per minute")},
+			expect: false,
+		},
+		{
+			name:   "Nil",
+			err:    nil,
+			expect: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := gopenrouter.IsContextLengthExceeded(tc.err); got != tc.expect {
+				t.Errorf("Expected %v, got %v", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		expect bool
+	}{
+		{name: "Nil", err: nil, expect: false},
+		{name: "RequestTimeout", err: &gopenrouter.APIError{Code: 408, Message: "Timeout"}, expect: true},
+		{name: "RateLimited", err: &gopenrouter.APIError{Code: 429, Message: "Rate limited"}, expect: true},
+		{name: "BadGateway", err: &gopenrouter.APIError{Code: 502, Message: "Provider error"}, expect: true},
+		{name: "HTTPStatusOnly", err: &gopenrouter.APIError{HTTPStatusCode: 503, Message: "Unavailable"}, expect: true},
+		{name: "InternalServerErrorRequestError", err: &gopenrouter.RequestError{HTTPStatusCode: 500}, expect: true},
+		{name: "NotImplemented", err: &gopenrouter.RequestError{HTTPStatusCode: 501}, expect: false},
+		{name: "BadRequest", err: &gopenrouter.APIError{Code: 400, Message: "Bad request"}, expect: false},
+		{name: "InvalidKey", err: &gopenrouter.APIError{Code: 401, Message: "Invalid key"}, expect: false},
+		{name: "InsufficientCredits", err: &gopenrouter.APIError{Code: 402, Message: "No credits"}, expect: false},
+		{name: "TruncatedStream", err: gopenrouter.ErrStreamTruncated, expect: true},
+		{name: "StalledStream", err: gopenrouter.ErrStreamStalled, expect: true},
+		{name: "TransportError", err: &url.Error{Op: "Post", URL: "https://openrouter.ai", Err: errors.New("connection reset by peer")}, expect: true},
+		{name: "Canceled", err: &url.Error{Op: "Post", URL: "https://openrouter.ai", Err: context.Canceled}, expect: false},
+		{name: "DeadlineExceeded", err: fmt.Errorf("request failed: %w", context.DeadlineExceeded), expect: false},
+		{name: "OtherError", err: errors.New("something went wrong"), expect: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := gopenrouter.IsRetryable(tc.err); got != tc.expect {
+				t.Errorf("Expected %v, got %v", tc.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package gopenrouter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// apiKeyContextKey is the context key under which the API key resolved for a shared call
+// is stored, so the key provider is not asked again when the request is built.
+type apiKeyContextKey struct{}
+
+// flightKey extends the key of a call shared between concurrent callers with the request
+// state that ctx may vary, so that only callers sending identical requests share a call:
+// the API key supplied by WithAPIKeyProvider, and the headers set with ContextWithHeader.
+// The returned context carries the resolved API key for the shared call to use.
+func (c *Client) flightKey(ctx context.Context, key string) (context.Context, string, error) {
+	var b strings.Builder
+	b.WriteString(key)
+	if c.apiKeyProvider != nil {
+		apiKey, err := c.apiKeyProvider(ctx)
+		if err != nil {
+			return nil, "", fmt.Errorf("error getting API key: %w", err)
+		}
+		ctx = context.WithValue(ctx, apiKeyContextKey{}, apiKey)
+		b.WriteString("\x00")
+		b.WriteString(apiKey)
+	}
+	if headers, ok := ctx.Value(headersContextKey{}).(http.Header); ok {
+		b.WriteString("\x00")
+		// Header.Write sorts the headers by name, making the key deterministic
+		_ = headers.Write(&b)
+	}
+	return ctx, b.String(), nil
+}
+
+// flightGroup deduplicates concurrent calls with the same key: the first call runs, and
+// calls made while it is in flight wait for and share its result. The zero value is ready
+// to use.
+type flightGroup[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[T]
+}
+
+// flightCall is a call in flight and the number of callers waiting for it.
+type flightCall[T any] struct {
+	done    chan struct{}
+	val     T
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do calls fn, or waits for the call in flight with the same key. fn runs with a context
+// carrying the values of ctx that is only cancelled once every waiting caller has given
+// up, so that one caller cancelling its context does not fail the others.
+func (g *flightGroup[T]) do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall[T])
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall[T]{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = call
+		go func() {
+			defer cancel()
+			call.val, call.err = fn(callCtx)
+			g.forget(key, call)
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		abandoned := call.waiters == 0
+		g.mu.Unlock()
+		if abandoned {
+			// Later calls start afresh instead of joining the cancelled call
+			g.forget(key, call)
+			call.cancel()
+		}
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// forget removes call from the calls in flight, unless it has been replaced already.
+func (g *flightGroup[T]) forget(key string, call *flightCall[T]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
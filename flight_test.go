@@ -0,0 +1,270 @@
+package gopenrouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters blocks until n callers wait for the call with the given key.
+func waitForWaiters[T any](t *testing.T, g *flightGroup[T], key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		call := g.calls[key]
+		waiters := 0
+		if call != nil {
+			waiters = call.waiters
+		}
+		g.mu.Unlock()
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Expected %d waiting callers", n)
+}
+
+func TestFlightGroup(t *testing.T) {
+	t.Run("SharesResult", func(t *testing.T) {
+		var g flightGroup[int]
+		var calls atomic.Int32
+		release := make(chan struct{})
+		fn := func(ctx context.Context) (int, error) {
+			calls.Add(1)
+			<-release
+			return 42, nil
+		}
+
+		var wg sync.WaitGroup
+		results := make([]int, 10)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = g.do(context.Background(), "key", fn)
+			}()
+		}
+		waitForWaiters(t, &g, "key", len(results))
+		close(release)
+		wg.Wait()
+
+		if calls.Load() != 1 {
+			t.Errorf("Expected 1 call, got %d", calls.Load())
+		}
+		for i, result := range results {
+			if result != 42 {
+				t.Errorf("Expected result 42 for caller %d, got %d", i, result)
+			}
+		}
+
+		// Calls made after the shared call finished run again
+		release = make(chan struct{})
+		close(release)
+		if _, err := g.do(context.Background(), "key", fn); err != nil || calls.Load() != 2 {
+			t.Errorf("Expected a new call, got %d calls and %v", calls.Load(), err)
+		}
+	})
+
+	t.Run("CancelledCallerDoesNotFailOthers", func(t *testing.T) {
+		var g flightGroup[int]
+		release := make(chan struct{})
+		var callErr atomic.Value
+		fn := func(ctx context.Context) (int, error) {
+			<-release
+			if err := ctx.Err(); err != nil {
+				callErr.Store(err)
+			}
+			return 1, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		first := make(chan error, 1)
+		go func() {
+			_, err := g.do(ctx, "key", fn)
+			first <- err
+		}()
+		waitForWaiters(t, &g, "key", 1)
+
+		second := make(chan int, 1)
+		go func() {
+			result, _ := g.do(context.Background(), "key", fn)
+			second <- result
+		}()
+		waitForWaiters(t, &g, "key", 2)
+
+		cancel()
+		if err := <-first; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled for the cancelled caller, got %v", err)
+		}
+		close(release)
+		if result := <-second; result != 1 {
+			t.Errorf("Expected result 1 for the other caller, got %d", result)
+		}
+		if err := callErr.Load(); err != nil {
+			t.Errorf("Expected the shared call not to be cancelled, got %v", err)
+		}
+	})
+
+	t.Run("AbandonedCallIsCancelled", func(t *testing.T) {
+		var g flightGroup[int]
+		cancelled := make(chan struct{})
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			_, _ = g.do(ctx, "key", func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				close(cancelled)
+				return 0, ctx.Err()
+			})
+		}()
+		waitForWaiters(t, &g, "key", 1)
+		cancel()
+
+		select {
+		case <-cancelled:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the abandoned call to be cancelled")
+		}
+	})
+}
+
+func TestFlightKey(t *testing.T) {
+	t.Run("ContextHeaders", func(t *testing.T) {
+		c := New("test-key")
+		ctx := context.Background()
+
+		_, plain, _ := c.flightKey(ctx, "/models")
+		_, first, _ := c.flightKey(ContextWithHeader(ctx, "X-Tenant", "a"), "/models")
+		_, second, _ := c.flightKey(ContextWithHeader(ctx, "X-Tenant", "b"), "/models")
+		_, again, _ := c.flightKey(ContextWithHeader(ctx, "X-Tenant", "a"), "/models")
+		if plain == first || first == second {
+			t.Errorf("Expected calls with different headers not to be shared, got %q, %q, and %q", plain, first, second)
+		}
+		if first != again {
+			t.Errorf("Expected calls with the same headers to be shared, got %q and %q", first, again)
+		}
+	})
+
+	t.Run("APIKeyProvider", func(t *testing.T) {
+		type tenantKey struct{}
+		var calls atomic.Int32
+		var authorization atomic.Value
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization.Store(r.Header.Get("Authorization"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}))
+		defer server.Close()
+
+		c := New("", WithBaseURL(server.URL), WithAPIKeyProvider(func(ctx context.Context) (string, error) {
+			calls.Add(1)
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			return "key-" + tenant, nil
+		}))
+		ctxA := context.WithValue(context.Background(), tenantKey{}, "a")
+		ctxB := context.WithValue(context.Background(), tenantKey{}, "b")
+
+		_, keyA, _ := c.flightKey(ctxA, "/models")
+		_, keyB, _ := c.flightKey(ctxB, "/models")
+		if keyA == keyB {
+			t.Errorf("Expected calls with different API keys not to be shared, got %q", keyA)
+		}
+
+		calls.Store(0)
+		if _, err := c.ListModels(ctxB); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := authorization.Load(); got != "Bearer key-b" {
+			t.Errorf("Expected the API key of the caller, got %v", got)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("Expected the API key to be resolved once, got %d calls", calls.Load())
+		}
+	})
+}
+
+func TestSharedResponsesAreCopied(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/models" {
+			_, _ = w.Write([]byte(`{"data":[{"id":"test/model","architecture":{"input_modalities":["text"]},"supported_parameters":["tools"]}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"test/model","architecture":{"input_modalities":["text"]},"endpoints":[{"name":"test","supported_parameters":["tools"]}]}}`))
+	}))
+	defer server.Close()
+	c := New("test-key", WithBaseURL(server.URL))
+
+	// share runs two concurrent calls of fn sharing a single request
+	share := func(waitForCalls func(), fn func()) {
+		var wg sync.WaitGroup
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fn()
+			}()
+		}
+		waitForCalls()
+		release <- struct{}{}
+		wg.Wait()
+	}
+
+	t.Run("Models", func(t *testing.T) {
+		_, key, _ := c.flightKey(context.Background(), "\x00")
+		var mu sync.Mutex
+		var results [][]ModelData
+		share(func() { waitForWaiters(t, &c.modelsFlight, key, 2) }, func() {
+			models, err := c.ListModels(context.Background())
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			mu.Lock()
+			results = append(results, models)
+			mu.Unlock()
+		})
+		if len(results) != 2 {
+			t.Fatalf("Expected 2 results, got %d", len(results))
+		}
+
+		results[0][0].SupportedParameters[0] = "changed"
+		results[0][0].Architecture.InputModalities[0] = "changed"
+		if results[1][0].SupportedParameters[0] != "tools" || results[1][0].Architecture.InputModalities[0] != "text" {
+			t.Errorf("Expected callers not to share nested data, got %+v", results[1][0])
+		}
+	})
+
+	t.Run("Endpoints", func(t *testing.T) {
+		_, key, _ := c.flightKey(context.Background(), "/models/test/model/endpoints")
+		var mu sync.Mutex
+		var results []EndpointData
+		share(func() { waitForWaiters(t, &c.endpointsFlight, key, 2) }, func() {
+			data, err := c.ListEndpoints(context.Background(), "test", "model")
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			mu.Lock()
+			results = append(results, data)
+			mu.Unlock()
+		})
+		if len(results) != 2 {
+			t.Fatalf("Expected 2 results, got %d", len(results))
+		}
+
+		results[0].Endpoints[0].SupportedParameters[0] = "changed"
+		results[0].Architecture.InputModalities[0] = "changed"
+		if results[1].Endpoints[0].SupportedParameters[0] != "tools" || results[1].Architecture.InputModalities[0] != "text" {
+			t.Errorf("Expected callers not to share nested data, got %+v", results[1])
+		}
+	})
+}
@@ -8,6 +8,8 @@ import (
 // generationResponse represents the internal API response when retrieving a single generation's data.
 // It wraps the generation data in a standard response structure.
 type generationResponse struct {
+	httpHeader
+
 	Data GenerationData `json:"data"`
 }
 
@@ -15,6 +17,8 @@ type generationResponse struct {
 // This includes metadata about the request, the model used, performance metrics,
 // token usage statistics, and other details about the generation process.
 type GenerationData struct {
+	httpHeader
+
 	// ID is the unique identifier for this generation
 	ID string `json:"id"`
 	// TotalCost represents the total cost of the generation in credits
@@ -102,5 +106,53 @@ func (c *Client) GetGeneration(ctx context.Context, id string) (data GenerationD
 	}
 
 	data = response.Data
+	data.httpHeader = response.httpHeader
 	return
 }
+
+// GenerationResult holds the outcome of looking up one generation with GetGenerations.
+type GenerationResult struct {
+	// Data is the generation metadata, valid if Err is nil
+	Data GenerationData
+	// Err is the error that occurred while retrieving the generation, if any
+	Err error
+}
+
+// GetGenerations retrieves the metadata of many generations concurrently, for example to
+// reconcile the costs of a batch of responses.
+//
+// The number of requests sent at the same time is limited, by default to 8, which can be
+// changed with WithBulkConcurrency. Each ID is looked up once, even if it appears multiple
+// times. Failed lookups do not affect the others and are reported in the result of their
+// ID. If ctx is done before all lookups were started, the remaining IDs report the context
+// error.
+//
+// Parameters:
+//   - ctx: The context for the requests, which can be used for cancellation and timeouts
+//   - ids: The unique identifiers of the generations to retrieve
+//
+// Returns:
+//   - map[string]GenerationResult: The metadata or error of each generation, keyed by ID
+func (c *Client) GetGenerations(ctx context.Context, ids []string) map[string]GenerationResult {
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	results := make([]GenerationResult, len(unique))
+	runConcurrently(ctx, len(unique), c.bulkConcurrency, func(ctx context.Context, i int) {
+		results[i].Data, results[i].Err = c.GetGeneration(ctx, unique[i])
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+
+	byID := make(map[string]GenerationResult, len(unique))
+	for i, id := range unique {
+		byID[id] = results[i]
+	}
+	return byID
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/bkovacki/gopenrouter"
 )
@@ -112,3 +114,96 @@ func TestClientGetGeneration(t *testing.T) {
 		})
 	}
 }
+
+func TestClientGetGenerations(t *testing.T) {
+	t.Run("PerIDResults", func(t *testing.T) {
+		var requests atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			id := r.URL.Query().Get("id")
+			w.Header().Set("Content-Type", "application/json")
+			if id == "gen-missing" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = fmt.Fprint(w, `{"error": {"code": 404, "message": "Generation not found"}}`)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"data": {"id": %q, "total_cost": 0.5}}`, id)
+		}))
+		defer ts.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL))
+		results := client.GetGenerations(context.Background(), []string{"gen-1", "gen-missing", "gen-2", "gen-1"})
+
+		if len(results) != 3 {
+			t.Fatalf("Expected 3 results, got %d", len(results))
+		}
+		if requests.Load() != 3 {
+			t.Errorf("Expected duplicate IDs to be looked up once, got %d requests", requests.Load())
+		}
+		for _, id := range []string{"gen-1", "gen-2"} {
+			result := results[id]
+			if result.Err != nil {
+				t.Errorf("Expected no error for %s, got %v", id, result.Err)
+			}
+			if result.Data.ID != id || result.Data.TotalCost != 0.5 {
+				t.Errorf("Expected data of %s, got %+v", id, result.Data)
+			}
+		}
+		var apiErr *gopenrouter.APIError
+		if !errors.As(results["gen-missing"].Err, &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound {
+			t.Errorf("Expected 404 APIError for gen-missing, got %v", results["gen-missing"].Err)
+		}
+	})
+
+	t.Run("BoundedConcurrency", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"data": {"id": %q}}`, r.URL.Query().Get("id"))
+		}))
+		defer ts.Close()
+
+		ids := make([]string, 20)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("gen-%d", i)
+		}
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL), gopenrouter.WithBulkConcurrency(3))
+		results := client.GetGenerations(context.Background(), ids)
+
+		if len(results) != len(ids) {
+			t.Fatalf("Expected %d results, got %d", len(ids), len(results))
+		}
+		if maxInFlight.Load() > 3 {
+			t.Errorf("Expected at most 3 requests in flight, got %d", maxInFlight.Load())
+		}
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("Expected no request to be sent")
+		}))
+		defer ts.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL), gopenrouter.WithBulkConcurrency(1))
+		results := client.GetGenerations(ctx, []string{"gen-1", "gen-2"})
+
+		for _, id := range []string{"gen-1", "gen-2"} {
+			if !errors.Is(results[id].Err, context.Canceled) {
+				t.Errorf("Expected context.Canceled for %s, got %v", id, results[id].Err)
+			}
+		}
+	})
+}
@@ -0,0 +1,194 @@
+package gopenroutertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// Fault injects a failure into the response to a request. It either writes the response
+// itself or serves the request with next, altering what is written.
+type Fault func(w http.ResponseWriter, r *http.Request, next http.Handler)
+
+// FaultInjector is an http.Handler that injects failures into the responses of another
+// handler, to exercise retry, backoff, and stream resumption logic. Each request consumes
+// the next fault in order; once the faults are exhausted, requests are served by the
+// wrapped handler unchanged. A nil fault also serves its request unchanged.
+//
+// For example, a server rejecting the first request with a rate limit and cutting the
+// second stream after two events:
+//
+//	injector := gopenroutertest.NewFaultInjector(stream,
+//		gopenroutertest.RateLimited(time.Second),
+//		gopenroutertest.Disconnect(2),
+//	)
+//	server := httptest.NewServer(injector)
+type FaultInjector struct {
+	next http.Handler
+
+	mu       sync.Mutex
+	faults   []Fault
+	requests int
+}
+
+// NewFaultInjector returns a handler injecting the given faults into the responses of next.
+func NewFaultInjector(next http.Handler, faults ...Fault) *FaultInjector {
+	return &FaultInjector{next: next, faults: faults}
+}
+
+// ServeHTTP serves the request, injecting the next fault if any.
+func (f *FaultInjector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	var fault Fault
+	if f.requests < len(f.faults) {
+		fault = f.faults[f.requests]
+	}
+	f.requests++
+	f.mu.Unlock()
+
+	if fault == nil {
+		f.next.ServeHTTP(w, r)
+		return
+	}
+	fault(w, r, f.next)
+}
+
+// Requests returns the number of requests served so far, including failed ones.
+func (f *FaultInjector) Requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+// StatusError returns a fault responding with the given status code and an OpenRouter
+// error body carrying the message.
+func StatusError(code int, message string) Fault {
+	return func(w http.ResponseWriter, _ *http.Request, _ http.Handler) {
+		writeError(w, code, message)
+	}
+}
+
+// RateLimited returns a fault responding with 429 Too Many Requests and a Retry-After
+// header requesting the given delay. A zero delay omits the header.
+func RateLimited(retryAfter time.Duration) Fault {
+	return func(w http.ResponseWriter, _ *http.Request, _ http.Handler) {
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.FormatFloat(retryAfter.Seconds(), 'f', -1, 64))
+		}
+		writeError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+	}
+}
+
+// SlowFirstByte returns a fault delaying the response by the given duration before
+// serving the request unchanged. The delay ends early if the client gives up.
+func SlowFirstByte(delay time.Duration) Fault {
+	return func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// Disconnect returns a fault serving the request, but dropping the connection after the
+// given number of events of the response have been sent, without ending the stream.
+func Disconnect(afterEvents int) Fault {
+	return func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		ew := &eventWriter{ResponseWriter: w}
+		ew.onEvent = func(event []byte) {
+			if ew.events == afterEvents {
+				ew.flush()
+				panic(http.ErrAbortHandler)
+			}
+			ew.write(event)
+		}
+		next.ServeHTTP(ew, r)
+		ew.finish()
+	}
+}
+
+// MalformedChunk returns a fault serving the request, but inserting a data event that is
+// not valid JSON after the given number of events of the response.
+func MalformedChunk(afterEvents int) Fault {
+	return func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		ew := &eventWriter{ResponseWriter: w}
+		ew.onEvent = func(event []byte) {
+			if ew.events == afterEvents {
+				ew.write([]byte("data: {\"id\":\"malformed\",\"choices\":[\n\n"))
+			}
+			ew.write(event)
+		}
+		next.ServeHTTP(ew, r)
+		if ew.events <= afterEvents {
+			ew.write([]byte("data: {\"id\":\"malformed\",\"choices\":[\n\n"))
+		}
+		ew.finish()
+	}
+}
+
+// writeError writes an OpenRouter error response.
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(gopenrouter.ErrorResponse{Error: &gopenrouter.APIError{Code: code, Message: message}})
+}
+
+// eventWriter splits the body written by a handler into server-sent events, passing each
+// complete event to onEvent. Bytes following the last complete event are written as is
+// by finish.
+type eventWriter struct {
+	http.ResponseWriter
+
+	onEvent func(event []byte)
+	pending []byte
+	events  int
+}
+
+func (w *eventWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.Index(w.pending, []byte("\n\n"))
+		if i < 0 {
+			break
+		}
+		event := w.pending[:i+2]
+		w.pending = w.pending[i+2:]
+		w.onEvent(event)
+		w.events++
+	}
+	return len(p), nil
+}
+
+// Flush flushes the events written so far, so that streams are delivered incrementally.
+func (w *eventWriter) Flush() {
+	w.flush()
+}
+
+// write writes bytes to the underlying response.
+func (w *eventWriter) write(p []byte) {
+	_, _ = w.ResponseWriter.Write(p)
+}
+
+// flush flushes the underlying response, if it supports flushing.
+func (w *eventWriter) flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// finish writes the bytes following the last complete event.
+func (w *eventWriter) finish() {
+	if len(w.pending) > 0 {
+		w.write(w.pending)
+		w.pending = nil
+	}
+}
@@ -0,0 +1,188 @@
+package gopenroutertest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+func TestFaultInjector(t *testing.T) {
+	ctx := context.Background()
+	stream := gopenroutertest.NewStream().
+		ChunkWithID("1", gopenroutertest.ChatChunk("gen-1", "openai/gpt-4o", "Hello")).
+		ChunkWithID("2", gopenroutertest.ChatChunk("gen-1", "openai/gpt-4o", " world")).
+		Done()
+	request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", []gopenrouter.ChatMessage{{Role: "user", Content: "Hi"}}).Build()
+
+	// readAll returns the text streamed before the first error.
+	readAll := func(reader *gopenrouter.ChatCompletionStreamReader) (string, error) {
+		defer reader.Close()
+		var text strings.Builder
+		for {
+			chunk, err := reader.Recv()
+			if err == io.EOF {
+				return text.String(), nil
+			}
+			if err != nil {
+				return text.String(), err
+			}
+			text.WriteString(chunk.Text())
+		}
+	}
+
+	t.Run("RateLimited", func(t *testing.T) {
+		injector := gopenroutertest.NewFaultInjector(stream, gopenroutertest.RateLimited(10*time.Millisecond))
+		server := httptest.NewServer(injector)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		_, err := client.ChatCompletionStream(ctx, *request)
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected rate limit error, got %v", err)
+		}
+		if apiErr.RetryAfter != 10*time.Millisecond {
+			t.Errorf("Expected Retry-After of 10ms, got %v", apiErr.RetryAfter)
+		}
+
+		reader, err := client.ChatCompletionStream(ctx, *request)
+		if err != nil {
+			t.Fatalf("Expected the faults to be exhausted, got %v", err)
+		}
+		if text, err := readAll(reader); err != nil || text != "Hello world" {
+			t.Errorf("Expected text 'Hello world', got %q (%v)", text, err)
+		}
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		injector := gopenroutertest.NewFaultInjector(stream,
+			gopenroutertest.RateLimited(10*time.Millisecond),
+			gopenroutertest.StatusError(http.StatusBadGateway, "Provider returned error"),
+		)
+		server := httptest.NewServer(injector)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithRetry(3, gopenrouter.RetryPolicy{InitialBackoff: time.Millisecond}),
+		)
+		reader, err := client.ChatCompletionStream(ctx, *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if text, err := readAll(reader); err != nil || text != "Hello world" {
+			t.Errorf("Expected text 'Hello world', got %q (%v)", text, err)
+		}
+		if injector.Requests() != 3 {
+			t.Errorf("Expected 3 requests, got %d", injector.Requests())
+		}
+	})
+
+	t.Run("Disconnect", func(t *testing.T) {
+		server := httptest.NewServer(gopenroutertest.NewFaultInjector(stream, gopenroutertest.Disconnect(1)))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		reader, err := client.ChatCompletionStream(ctx, *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		text, err := readAll(reader)
+		if err == nil {
+			t.Error("Expected an error for the interrupted stream")
+		}
+		if text != "Hello" {
+			t.Errorf("Expected text 'Hello' before the disconnect, got %q", text)
+		}
+	})
+
+	t.Run("DisconnectReconnect", func(t *testing.T) {
+		var lastEventID string
+		resumed := gopenroutertest.NewStream().
+			ChunkWithID("2", gopenroutertest.ChatChunk("gen-1", "openai/gpt-4o", " world")).
+			Done()
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lastEventID = r.Header.Get("Last-Event-ID")
+			if lastEventID == "1" {
+				resumed.ServeHTTP(w, r)
+				return
+			}
+			stream.ServeHTTP(w, r)
+		})
+		injector := gopenroutertest.NewFaultInjector(handler, gopenroutertest.Disconnect(1))
+		server := httptest.NewServer(injector)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithStreamReconnect(1))
+		reader, err := client.ChatCompletionStream(ctx, *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if text, err := readAll(reader); err != nil || text != "Hello world" {
+			t.Errorf("Expected resumed text 'Hello world', got %q (%v)", text, err)
+		}
+		if injector.Requests() != 2 || lastEventID != "1" {
+			t.Errorf("Expected 1 reconnect from event 1, got %d requests from event %q", injector.Requests(), lastEventID)
+		}
+	})
+
+	t.Run("MalformedChunk", func(t *testing.T) {
+		server := httptest.NewServer(gopenroutertest.NewFaultInjector(stream, gopenroutertest.MalformedChunk(1)))
+		defer server.Close()
+
+		var malformed []string
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithOnDecodeError(func(raw []byte, err error) {
+				malformed = append(malformed, string(raw))
+			}),
+		)
+		reader, err := client.ChatCompletionStream(ctx, *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if text, err := readAll(reader); err != nil || text != "Hello world" {
+			t.Errorf("Expected the malformed chunk to be skipped, got %q (%v)", text, err)
+		}
+		if len(malformed) != 1 {
+			t.Errorf("Expected 1 malformed chunk, got %d", len(malformed))
+		}
+	})
+
+	t.Run("SlowFirstByte", func(t *testing.T) {
+		server := httptest.NewServer(gopenroutertest.NewFaultInjector(stream, gopenroutertest.SlowFirstByte(time.Second)))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		_, err := client.ChatCompletionStream(timeoutCtx, *request)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("NilFault", func(t *testing.T) {
+		injector := gopenroutertest.NewFaultInjector(stream, nil, gopenroutertest.StatusError(http.StatusInternalServerError, "Internal error"))
+		server := httptest.NewServer(injector)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		reader, err := client.ChatCompletionStream(ctx, *request)
+		if err != nil {
+			t.Fatalf("Expected the first request to succeed, got %v", err)
+		}
+		reader.Close()
+		if _, err := client.ChatCompletionStream(ctx, *request); err == nil {
+			t.Error("Expected the second request to fail")
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package gopenroutertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// DecodeChatCompletionRequest decodes the body of a chat completion request captured by
+// a test server. The body is restored, so it can be read again. Errors are reported with
+// t.Errorf, so it can be called from the goroutine of a handler.
+func DecodeChatCompletionRequest(t testing.TB, r *http.Request) (request gopenrouter.ChatCompletionRequest) {
+	t.Helper()
+	decodeBody(t, r, &request)
+	return
+}
+
+// DecodeCompletionRequest decodes the body of a completion request captured by a test
+// server. The body is restored, so it can be read again. Errors are reported with
+// t.Errorf, so it can be called from the goroutine of a handler.
+func DecodeCompletionRequest(t testing.TB, r *http.Request) (request gopenrouter.CompletionRequest) {
+	t.Helper()
+	decodeBody(t, r, &request)
+	return
+}
+
+// AssertRequestBody reports with t.Errorf the differences between the body of a request
+// captured by a test server and the JSON encoding of want, such as a request built with a
+// builder. It reports whether the body matched. The body is restored, so it can be read
+// again.
+//
+// Example usage:
+//
+//	want := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+//	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//		gopenroutertest.AssertRequestBody(t, r, want)
+//		// ...
+//	}))
+func AssertRequestBody(t testing.TB, r *http.Request, want any) bool {
+	t.Helper()
+	body, ok := readBody(t, r)
+	if !ok {
+		return false
+	}
+	if diff := Diff(want, json.RawMessage(body)); diff != "" {
+		t.Errorf("Unexpected request body:\n%s", diff)
+		return false
+	}
+	return true
+}
+
+// Diff compares the JSON encodings of want and got, and returns one line per difference,
+// naming the path of the differing field, or an empty string if they are equal. Byte
+// slices and json.RawMessage values are taken as already encoded JSON. Comparing the
+// encodings ignores unset optional fields and the order of object keys.
+func Diff(want, got any) string {
+	wantValue, err := jsonValue(want)
+	if err != nil {
+		return fmt.Sprintf("encoding want: %v", err)
+	}
+	gotValue, err := jsonValue(got)
+	if err != nil {
+		return fmt.Sprintf("encoding got: %v", err)
+	}
+
+	var lines []string
+	diffValues(&lines, "", wantValue, gotValue)
+	return strings.Join(lines, "\n")
+}
+
+// decodeBody decodes the JSON body of r into v.
+func decodeBody(t testing.TB, r *http.Request, v any) {
+	t.Helper()
+	body, ok := readBody(t, r)
+	if !ok {
+		return
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		t.Errorf("Failed to decode request body: %v", err)
+	}
+}
+
+// readBody reads the body of r and restores it.
+func readBody(t testing.TB, r *http.Request) ([]byte, bool) {
+	t.Helper()
+	body, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		t.Errorf("Failed to read request body: %v", err)
+		return nil, false
+	}
+	return body, true
+}
+
+// jsonValue returns the generic JSON representation of v.
+func jsonValue(v any) (value any, err error) {
+	var data []byte
+	switch v := v.(type) {
+	case json.RawMessage:
+		data = v
+	case []byte:
+		data = v
+	default:
+		data, err = json.Marshal(v)
+		if err != nil {
+			return
+		}
+	}
+	err = json.Unmarshal(data, &value)
+	return
+}
+
+// diffValues appends the differences between two generic JSON values to lines.
+func diffValues(lines *[]string, path string, want, got any) {
+	wantObject, wantIsObject := want.(map[string]any)
+	gotObject, gotIsObject := got.(map[string]any)
+	if wantIsObject && gotIsObject {
+		keys := slices.Collect(maps.Keys(wantObject))
+		for key := range gotObject {
+			if _, ok := wantObject[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			wantField, inWant := wantObject[key]
+			gotField, inGot := gotObject[key]
+			switch {
+			case !inGot:
+				*lines = append(*lines, fmt.Sprintf("%s: want %s, got nothing", fieldPath, encode(wantField)))
+			case !inWant:
+				*lines = append(*lines, fmt.Sprintf("%s: want nothing, got %s", fieldPath, encode(gotField)))
+			default:
+				diffValues(lines, fieldPath, wantField, gotField)
+			}
+		}
+		return
+	}
+
+	wantArray, wantIsArray := want.([]any)
+	gotArray, gotIsArray := got.([]any)
+	if wantIsArray && gotIsArray {
+		for i := range max(len(wantArray), len(gotArray)) {
+			elementPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(gotArray):
+				*lines = append(*lines, fmt.Sprintf("%s: want %s, got nothing", elementPath, encode(wantArray[i])))
+			case i >= len(wantArray):
+				*lines = append(*lines, fmt.Sprintf("%s: want nothing, got %s", elementPath, encode(gotArray[i])))
+			default:
+				diffValues(lines, elementPath, wantArray[i], gotArray[i])
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		if path == "" {
+			path = "body"
+		}
+		*lines = append(*lines, fmt.Sprintf("%s: want %s, got %s", path, encode(want), encode(got)))
+	}
+}
+
+// encode returns the compact JSON encoding of a generic JSON value.
+func encode(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,120 @@
+package gopenroutertest_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+// recordingT records the errors reported by assertion helpers.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("Equal", func(t *testing.T) {
+		want := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", []gopenrouter.ChatMessage{{Role: "user", Content: "Hi"}}).
+			WithTemperature(0.5).
+			Build()
+		got := `{"temperature":0.5,"messages":[{"content":"Hi","role":"user"}],"model":"openai/gpt-4o"}`
+		if diff := gopenroutertest.Diff(want, []byte(got)); diff != "" {
+			t.Errorf("Expected no differences, got:\n%s", diff)
+		}
+	})
+
+	t.Run("Differences", func(t *testing.T) {
+		want := map[string]any{
+			"model":    "openai/gpt-4o",
+			"messages": []map[string]string{{"role": "user", "content": "Hi"}},
+			"seed":     1,
+		}
+		got := `{"model":"openai/gpt-4o-mini","messages":[{"role":"user","content":"Hi"},{"role":"user","content":"Again"}],"top_k":3}`
+
+		expected := "messages[1]: want nothing, got {\"content\":\"Again\",\"role\":\"user\"}\n" +
+			"model: want \"openai/gpt-4o\", got \"openai/gpt-4o-mini\"\n" +
+			"seed: want 1, got nothing\n" +
+			"top_k: want nothing, got 3"
+		if diff := gopenroutertest.Diff(want, []byte(got)); diff != expected {
+			t.Errorf("Expected differences:\n%s\ngot:\n%s", expected, diff)
+		}
+	})
+
+	t.Run("Scalars", func(t *testing.T) {
+		if diff := gopenroutertest.Diff("a", "b"); diff != `body: want "a", got "b"` {
+			t.Errorf("Expected scalar difference, got %q", diff)
+		}
+	})
+}
+
+func TestRequestAssertions(t *testing.T) {
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "What is the capital of France?"}}
+	want := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).WithMaxTokens(10).Build()
+
+	t.Run("ChatCompletion", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gopenroutertest.AssertRequestBody(t, r, want)
+
+			request := gopenroutertest.DecodeChatCompletionRequest(t, r)
+			if request.Model != "openai/gpt-4o" || len(request.Messages) != 1 {
+				t.Errorf("Expected decoded request for openai/gpt-4o with 1 message, got %+v", request)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"gen-1","choices":[{"message":{"role":"assistant","content":"Paris"}}]}`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		if _, err := client.ChatCompletion(context.Background(), *want); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		recorder := &recordingT{TB: t}
+		r := httptest.NewRequest(http.MethodPost, "/chat/completions", strings.NewReader(`{"model":"openai/gpt-4o","messages":[{"role":"user","content":"What is the capital of France?"}],"max_tokens":20}`))
+
+		if gopenroutertest.AssertRequestBody(recorder, r, want) {
+			t.Error("Expected the assertion to fail")
+		}
+		if len(recorder.errors) != 1 || !strings.Contains(recorder.errors[0], "max_tokens: want 10, got 20") {
+			t.Errorf("Expected a max_tokens difference, got %v", recorder.errors)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"max_tokens":20`) {
+			t.Errorf("Expected the body to be restored, got %s", body)
+		}
+	})
+
+	t.Run("Completion", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/completions", strings.NewReader(`{"model":"openai/gpt-4o","prompt":"Hello"}`))
+		request := gopenroutertest.DecodeCompletionRequest(t, r)
+		if request.Model != "openai/gpt-4o" || request.Prompt != "Hello" {
+			t.Errorf("Expected decoded completion request, got %+v", request)
+		}
+	})
+
+	t.Run("InvalidBody", func(t *testing.T) {
+		recorder := &recordingT{TB: t}
+		r := httptest.NewRequest(http.MethodPost, "/completions", strings.NewReader(`{"model":`))
+		gopenroutertest.DecodeCompletionRequest(recorder, r)
+		if len(recorder.errors) != 1 {
+			t.Errorf("Expected 1 decoding error, got %v", recorder.errors)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+// Package gopenroutertest provides helpers for testing code that uses gopenrouter,
+// such as builders of the server-sent event streams returned by OpenRouter and handlers
+// injecting failures into them.
+//
+// A stream of chat completion chunks can be served by an httptest server:
+//
+//	stream := gopenroutertest.NewStream().
+//		Comment("OPENROUTER PROCESSING").
+//		Chunk(gopenroutertest.ChatChunk("gen-1", "openai/gpt-4o", "Hello")).
+//		Chunk(gopenroutertest.ChatChunk("gen-1", "openai/gpt-4o", " world")).
+//		Usage(gopenrouter.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}).
+//		Done()
+//	server := httptest.NewServer(stream)
+//	client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+package gopenroutertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// Stream builds the body of a server-sent event stream. Its methods append events and
+// return the stream, so that calls can be chained. A Stream is also an http.Handler
+// serving its body as an event stream.
+type Stream struct {
+	buf bytes.Buffer
+}
+
+// NewStream returns an empty stream.
+func NewStream() *Stream {
+	return &Stream{}
+}
+
+// Chunk appends a data event carrying the JSON encoding of v. It panics if v cannot be
+// encoded, as fixtures are expected to be valid.
+func (s *Stream) Chunk(v any) *Stream {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("gopenroutertest: encoding chunk: %v", err))
+	}
+	return s.Data(string(data))
+}
+
+// ChunkWithID appends a data event carrying the JSON encoding of v, with the given SSE
+// event ID used by clients to resume the stream.
+func (s *Stream) ChunkWithID(id string, v any) *Stream {
+	fmt.Fprintf(&s.buf, "id: %s\n", id)
+	return s.Chunk(v)
+}
+
+// Data appends a data event carrying the given payload as is, such as malformed JSON.
+// Payloads spanning multiple lines are split into multiple data fields.
+func (s *Stream) Data(payload string) *Stream {
+	for line := range strings.SplitSeq(payload, "\n") {
+		fmt.Fprintf(&s.buf, "data: %s\n", line)
+	}
+	s.buf.WriteString("\n")
+	return s
+}
+
+// Comment appends a comment line, such as the keep-alive comments sent by OpenRouter
+// while a request is processed.
+func (s *Stream) Comment(text string) *Stream {
+	fmt.Fprintf(&s.buf, ": %s\n\n", text)
+	return s
+}
+
+// Usage appends the final chunk reporting the token usage of the stream, which has no
+// choices. Use ChatUsageChunk for a chunk identifying the generation and model.
+func (s *Stream) Usage(usage gopenrouter.Usage) *Stream {
+	return s.Chunk(struct {
+		Choices []any             `json:"choices"`
+		Usage   gopenrouter.Usage `json:"usage"`
+	}{Choices: []any{}, Usage: usage})
+}
+
+// Error appends a data event carrying an error, as sent by OpenRouter when a stream
+// fails after it has started.
+func (s *Stream) Error(code int, message string) *Stream {
+	return s.Chunk(gopenrouter.ErrorResponse{Error: &gopenrouter.APIError{Code: code, Message: message}})
+}
+
+// Done appends the [DONE] sentinel ending the stream.
+func (s *Stream) Done() *Stream {
+	return s.Data("[DONE]")
+}
+
+// Bytes returns the body of the stream.
+func (s *Stream) Bytes() []byte {
+	return bytes.Clone(s.buf.Bytes())
+}
+
+// String returns the body of the stream.
+func (s *Stream) String() string {
+	return s.buf.String()
+}
+
+// ServeHTTP writes the body of the stream as a text/event-stream response.
+func (s *Stream) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write(s.buf.Bytes())
+}
+
+// ChatChunk returns a chat completion chunk carrying a content delta for the first choice.
+func ChatChunk(id, model, content string) gopenrouter.ChatCompletionStreamResponse {
+	return gopenrouter.ChatCompletionStreamResponse{
+		ID:     id,
+		Object: "chat.completion.chunk",
+		Model:  model,
+		Choices: []gopenrouter.ChatStreamingChoice{
+			{Delta: gopenrouter.ChatDelta{Content: &content}},
+		},
+	}
+}
+
+// ChatFinishChunk returns a chat completion chunk ending the first choice with the
+// given finish reason, such as "stop" or "length".
+func ChatFinishChunk(id, model, finishReason string) gopenrouter.ChatCompletionStreamResponse {
+	return gopenrouter.ChatCompletionStreamResponse{
+		ID:     id,
+		Object: "chat.completion.chunk",
+		Model:  model,
+		Choices: []gopenrouter.ChatStreamingChoice{
+			{FinishReason: &finishReason},
+		},
+	}
+}
+
+// ChatUsageChunk returns the final chat completion chunk reporting the token usage of
+// the stream, which has no choices.
+func ChatUsageChunk(id, model string, usage gopenrouter.Usage) gopenrouter.ChatCompletionStreamResponse {
+	return gopenrouter.ChatCompletionStreamResponse{
+		ID:      id,
+		Object:  "chat.completion.chunk",
+		Model:   model,
+		Choices: []gopenrouter.ChatStreamingChoice{},
+		Usage:   &usage,
+	}
+}
+
+// CompletionChunk returns a text completion chunk carrying text for the first choice.
+func CompletionChunk(id, model, text string) gopenrouter.CompletionStreamResponse {
+	return gopenrouter.CompletionStreamResponse{
+		ID:      id,
+		Object:  "text_completion",
+		Model:   model,
+		Choices: []gopenrouter.StreamingChoice{{Text: text}},
+	}
+}
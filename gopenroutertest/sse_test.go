@@ -0,0 +1,108 @@
+package gopenroutertest_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+func TestStream(t *testing.T) {
+	t.Run("Body", func(t *testing.T) {
+		stream := gopenroutertest.NewStream().
+			Comment("OPENROUTER PROCESSING").
+			ChunkWithID("1", map[string]string{"id": "gen-1"}).
+			Data("{\"broken\":\n}").
+			Error(502, "Provider returned error").
+			Done()
+
+		expected := ": OPENROUTER PROCESSING\n\n" +
+			"id: 1\ndata: {\"id\":\"gen-1\"}\n\n" +
+			"data: {\"broken\":\ndata: }\n\n" +
+			"data: {\"error\":{\"code\":502,\"message\":\"Provider returned error\"}}\n\n" +
+			"data: [DONE]\n\n"
+		if stream.String() != expected {
+			t.Errorf("Expected body %q, got %q", expected, stream.String())
+		}
+	})
+
+	t.Run("ChatCompletionStream", func(t *testing.T) {
+		stream := gopenroutertest.NewStream().
+			Comment("OPENROUTER PROCESSING").
+			Chunk(gopenroutertest.ChatChunk("gen-1", "openai/gpt-4o", "Hello")).
+			Chunk(gopenroutertest.ChatChunk("gen-1", "openai/gpt-4o", " world")).
+			Chunk(gopenroutertest.ChatFinishChunk("gen-1", "openai/gpt-4o", "stop")).
+			Usage(gopenrouter.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}).
+			Done()
+		server := httptest.NewServer(stream)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", []gopenrouter.ChatMessage{{Role: "user", Content: "Hi"}}).Build()
+		reader, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer reader.Close()
+
+		var text strings.Builder
+		var usage *gopenrouter.Usage
+		var finishReason string
+		for {
+			chunk, err := reader.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			text.WriteString(chunk.Text())
+			if len(chunk.Choices) > 0 && chunk.Choices[0].FinishReason != nil {
+				finishReason = *chunk.Choices[0].FinishReason
+			}
+			if chunk.Usage != nil {
+				usage = chunk.Usage
+			}
+		}
+
+		if text.String() != "Hello world" {
+			t.Errorf("Expected text %q, got %q", "Hello world", text.String())
+		}
+		if finishReason != "stop" {
+			t.Errorf("Expected finish reason stop, got %q", finishReason)
+		}
+		if usage == nil || usage.TotalTokens != 5 {
+			t.Errorf("Expected usage with 5 total tokens, got %+v", usage)
+		}
+	})
+
+	t.Run("CompletionStream", func(t *testing.T) {
+		server := httptest.NewServer(gopenroutertest.NewStream().
+			Chunk(gopenroutertest.CompletionChunk("gen-1", "openai/gpt-4o", "Once upon")).
+			Done())
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		request := gopenrouter.NewCompletionRequestBuilder("openai/gpt-4o", "Tell a story").Build()
+		reader, err := client.CompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer reader.Close()
+
+		chunk, err := reader.Recv()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(chunk.Choices) != 1 || chunk.Choices[0].Text != "Once upon" {
+			t.Errorf("Expected text %q, got %+v", "Once upon", chunk.Choices)
+		}
+		if _, err := reader.Recv(); err != io.EOF {
+			t.Errorf("Expected io.EOF, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package gopenrouter
+
+import (
+	"context"
+	"net/http"
+)
+
+// headersContextKey is the context key under which per-call headers are stored.
+type headersContextKey struct{}
+
+// WithHeader adds a header sent with every request, such as a feature flag or the
+// credentials of an API gateway. Custom headers take precedence over the headers
+// set by the client, except for Accept and Content-Type.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Add(key, value)
+	}
+}
+
+// ContextWithHeader returns a context that makes requests sent with it carry the given
+// header in addition to the client's headers. It can be called repeatedly to add several
+// headers, and takes precedence over headers configured with WithHeader. Headers set
+// with WithCallHeader take precedence over context headers of the same name.
+//
+// Example usage:
+//
+//	ctx = gopenrouter.ContextWithHeader(ctx, "X-Experiment", "new-prompt")
+//	response, err := client.ChatCompletion(ctx, *request)
+func ContextWithHeader(ctx context.Context, key, value string) context.Context {
+	headers := make(http.Header)
+	if existing, ok := ctx.Value(headersContextKey{}).(http.Header); ok {
+		headers = existing.Clone()
+	}
+	headers.Add(key, value)
+	return context.WithValue(ctx, headersContextKey{}, headers)
+}
+
+// setCustomHeaders sets the client's custom headers followed by the per-call
+// headers stored in the request context.
+func (c *Client) setCustomHeaders(req *http.Request) {
+	for key, values := range c.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	if headers, ok := req.Context().Value(headersContextKey{}).(http.Header); ok {
+		for key, values := range headers {
+			req.Header[key] = append([]string(nil), values...)
+		}
+	}
+}
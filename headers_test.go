@@ -0,0 +1,149 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestCustomHeaders(t *testing.T) {
+	// newServer returns a server recording the headers of the last request
+	newServer := func(header *http.Header) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*header = r.Header.Clone()
+			_, _ = w.Write([]byte(`{"data":{"total_credits":10,"total_usage":1}}`))
+		}))
+	}
+
+	t.Run("ClientHeaders", func(t *testing.T) {
+		var header http.Header
+		server := newServer(&header)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithHeader("X-Feature-Flag", "beta"),
+			gopenrouter.WithHeader("X-Feature-Flag", "preview"),
+		)
+		if _, err := client.GetCredits(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		values := header.Values("X-Feature-Flag")
+		if len(values) != 2 || values[0] != "beta" || values[1] != "preview" {
+			t.Errorf("Expected X-Feature-Flag [beta preview], got %v", values)
+		}
+		if got := header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Expected Authorization to be 'Bearer test-key', got '%s'", got)
+		}
+	})
+
+	t.Run("OverrideCommonHeaders", func(t *testing.T) {
+		var header http.Header
+		server := newServer(&header)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithSiteTitle("My App"),
+			gopenrouter.WithHeader("X-Title", "Gateway App"),
+		)
+		if _, err := client.GetCredits(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := header.Values("X-Title"); len(got) != 1 || got[0] != "Gateway App" {
+			t.Errorf("Expected X-Title [Gateway App], got %v", got)
+		}
+	})
+
+	t.Run("PerCallHeaders", func(t *testing.T) {
+		var header http.Header
+		server := newServer(&header)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithHeader("X-Gateway", "client"),
+		)
+
+		ctx := gopenrouter.ContextWithHeader(context.Background(), "X-Gateway", "call")
+		ctx = gopenrouter.ContextWithHeader(ctx, "X-Experiment", "new-prompt")
+		if _, err := client.GetCredits(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if got := header.Values("X-Gateway"); len(got) != 1 || got[0] != "call" {
+			t.Errorf("Expected X-Gateway [call], got %v", got)
+		}
+		if got := header.Get("X-Experiment"); got != "new-prompt" {
+			t.Errorf("Expected X-Experiment to be 'new-prompt', got '%s'", got)
+		}
+
+		// headers added to a derived context must not leak into the parent
+		if _, err := client.GetCredits(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := header.Get("X-Gateway"); got != "client" {
+			t.Errorf("Expected X-Gateway to be 'client', got '%s'", got)
+		}
+		if got := header.Get("X-Experiment"); got != "" {
+			t.Errorf("Expected no X-Experiment header, got '%s'", got)
+		}
+	})
+}
+
+func TestUserAgent(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []gopenrouter.Option
+		expected string
+	}{
+		{
+			name:     "Default",
+			expected: "gopenrouter/" + gopenrouter.Version,
+		},
+		{
+			name:     "Suffix",
+			options:  []gopenrouter.Option{gopenrouter.WithUserAgentSuffix("myapp/1.2.0")},
+			expected: "gopenrouter/" + gopenrouter.Version + " myapp/1.2.0",
+		},
+		{
+			name:     "Replaced",
+			options:  []gopenrouter.Option{gopenrouter.WithUserAgent("custom/1.0")},
+			expected: "custom/1.0",
+		},
+		{
+			name: "ReplacedWithSuffix",
+			options: []gopenrouter.Option{
+				gopenrouter.WithUserAgentSuffix("myapp/1.2.0"),
+				gopenrouter.WithUserAgent("custom/1.0"),
+			},
+			expected: "custom/1.0 myapp/1.2.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userAgent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userAgent = r.Header.Get("User-Agent")
+				_, _ = w.Write([]byte(`{"data":{"total_credits":10,"total_usage":1}}`))
+			}))
+			defer server.Close()
+
+			options := append([]gopenrouter.Option{gopenrouter.WithBaseURL(server.URL)}, tt.options...)
+			client := gopenrouter.New("test-key", options...)
+			if _, err := client.GetCredits(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if userAgent != tt.expected {
+				t.Errorf("Expected User-Agent to be '%s', got '%s'", tt.expected, userAgent)
+			}
+		})
+	}
+}
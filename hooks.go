@@ -0,0 +1,158 @@
+package gopenrouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Hooks are callbacks invoked at well-defined points of the request lifecycle, allowing
+// applications to integrate logging, metrics, or auditing uniformly. Any hook may be nil.
+// Hooks are called synchronously and should return quickly.
+type Hooks struct {
+	// OnRequest is called before each attempt of a request is sent
+	OnRequest func(ctx context.Context, info RequestInfo)
+	// OnResponse is called after each attempt, once the response headers have been
+	// received or the attempt has failed
+	OnResponse func(ctx context.Context, info ResponseInfo)
+	// OnRetry is called before waiting to retry a failed attempt
+	OnRetry func(ctx context.Context, info RetryInfo)
+	// OnStreamChunk is called for each data chunk read by the consumer of a stream,
+	// before the chunk is decoded
+	OnStreamChunk func(ctx context.Context, info StreamChunkInfo)
+	// OnStreamEnd is called once the consumer of a stream has read it to its end, or has
+	// closed it before, with the time to first token and the duration of the stream
+	OnStreamEnd func(ctx context.Context, info StreamEndInfo)
+}
+
+// RequestInfo describes an attempt of an HTTP request.
+type RequestInfo struct {
+	// Method is the HTTP method of the request
+	Method string
+	// URL is the URL of the request
+	URL string
+	// Attempt is the number of the attempt, starting at 1
+	Attempt int
+}
+
+// ResponseInfo describes the outcome of an attempt of an HTTP request.
+type ResponseInfo struct {
+	RequestInfo
+	// StatusCode is the HTTP status code of the response, or zero if no response was received
+	StatusCode int
+	// Latency is the time between sending the request and receiving the response headers
+	Latency time.Duration
+	// Header holds the response headers, or nil if no response was received
+	Header http.Header
+	// Err is the error of the attempt, if any, including errors for non-2xx statuses
+	Err error
+}
+
+// RetryInfo describes a retry about to be made after a failed attempt.
+type RetryInfo struct {
+	RequestInfo
+	// Delay is the time waited before the next attempt
+	Delay time.Duration
+	// Err is the error of the failed attempt
+	Err error
+}
+
+// StreamChunkInfo describes a data chunk read from a stream.
+type StreamChunkInfo struct {
+	// Chunk is the number of the chunk, starting at 1
+	Chunk int
+	// Elapsed is the time since the streaming request was sent
+	Elapsed time.Duration
+	// Raw is the data payload of the chunk. It must not be modified.
+	Raw []byte
+}
+
+// StreamEndInfo describes a stream that has been read to its end, whether it finished
+// cleanly or failed.
+type StreamEndInfo struct {
+	// Endpoint is the path of the streaming endpoint, such as "/chat/completions"
+	Endpoint string
+	// Model is the model that generated the stream, or the requested model if no chunk
+	// reported one
+	Model string
+	// Provider is the provider that served the stream, if reported
+	Provider string
+	// Metrics holds the measurements of the stream, such as the time to first token
+	Metrics StreamMetrics
+	// Err is the error that ended the stream, or nil if it finished cleanly
+	Err error
+}
+
+// WithHooks sets callbacks invoked at well-defined points of the request lifecycle.
+//
+// Example usage:
+//
+//	client := gopenrouter.New("your-api-key", gopenrouter.WithHooks(gopenrouter.Hooks{
+//	  OnResponse: func(ctx context.Context, info gopenrouter.ResponseInfo) {
+//	    log.Printf("%s %s: %d in %v", info.Method, info.URL, info.StatusCode, info.Latency)
+//	  },
+//	}))
+func WithHooks(hooks Hooks) Option {
+	return func(c *Client) {
+		c.hooks = hooks
+	}
+}
+
+// onRequest invokes the OnRequest hook, if set.
+func (h *Hooks) onRequest(req *http.Request, attempt int) {
+	if h.OnRequest != nil {
+		h.OnRequest(req.Context(), newRequestInfo(req, attempt))
+	}
+}
+
+// onResponse invokes the OnResponse hook, if set.
+func (h *Hooks) onResponse(req *http.Request, attempt int, latency time.Duration, resp *http.Response, err error) {
+	if h.OnResponse == nil {
+		return
+	}
+
+	info := ResponseInfo{
+		RequestInfo: newRequestInfo(req, attempt),
+		Latency:     latency,
+		Err:         err,
+	}
+	info.StatusCode, info.Header = responseStatus(resp, err)
+	h.OnResponse(req.Context(), info)
+}
+
+// onRetry invokes the OnRetry hook, if set.
+func (h *Hooks) onRetry(req *http.Request, attempt int, delay time.Duration, err error) {
+	if h.OnRetry != nil {
+		h.OnRetry(req.Context(), RetryInfo{
+			RequestInfo: newRequestInfo(req, attempt),
+			Delay:       delay,
+			Err:         err,
+		})
+	}
+}
+
+// responseStatus returns the status code and headers of the response of an attempt,
+// taken from the error for non-2xx responses. Both are zero if no response was received.
+func responseStatus(resp *http.Response, err error) (int, http.Header) {
+	var apiErr *APIError
+	var reqErr *RequestError
+	switch {
+	case resp != nil:
+		return resp.StatusCode, resp.Header
+	case errors.As(err, &apiErr):
+		return apiErr.HTTPStatusCode, apiErr.Header
+	case errors.As(err, &reqErr):
+		return reqErr.HTTPStatusCode, reqErr.Header
+	}
+	return 0, nil
+}
+
+// newRequestInfo describes the given attempt of req.
+func newRequestInfo(req *http.Request, attempt int) RequestInfo {
+	return RequestInfo{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Attempt: attempt,
+	}
+}
@@ -0,0 +1,242 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+func TestHooks(t *testing.T) {
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	t.Run("RequestResponseAndRetry", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"error":{"code":503,"message":"unavailable"}}`))
+				return
+			}
+			w.Header().Set("X-Request-Id", "req-123")
+			_, _ = w.Write([]byte(`{"data":{"total_credits":10,"total_usage":1}}`))
+		}))
+		defer server.Close()
+
+		var requestInfos []gopenrouter.RequestInfo
+		var responseInfos []gopenrouter.ResponseInfo
+		var retryInfos []gopenrouter.RetryInfo
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithRetry(2, gopenrouter.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+			gopenrouter.WithHooks(gopenrouter.Hooks{
+				OnRequest: func(ctx context.Context, info gopenrouter.RequestInfo) {
+					requestInfos = append(requestInfos, info)
+				},
+				OnResponse: func(ctx context.Context, info gopenrouter.ResponseInfo) {
+					responseInfos = append(responseInfos, info)
+				},
+				OnRetry: func(ctx context.Context, info gopenrouter.RetryInfo) {
+					retryInfos = append(retryInfos, info)
+				},
+			}),
+		)
+
+		if _, err := client.GetCredits(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(requestInfos) != 2 {
+			t.Fatalf("Expected 2 OnRequest calls, got %d", len(requestInfos))
+		}
+		for i, info := range requestInfos {
+			if info.Method != http.MethodGet || info.URL != server.URL+"/credits" || info.Attempt != i+1 {
+				t.Errorf("Unexpected request info for attempt %d: %+v", i+1, info)
+			}
+		}
+
+		if len(responseInfos) != 2 {
+			t.Fatalf("Expected 2 OnResponse calls, got %d", len(responseInfos))
+		}
+		if responseInfos[0].StatusCode != http.StatusServiceUnavailable || !errors.Is(responseInfos[0].Err, gopenrouter.ErrProviderUnavailable) {
+			t.Errorf("Expected first response to fail with 503, got %+v", responseInfos[0])
+		}
+		if responseInfos[1].StatusCode != http.StatusOK || responseInfos[1].Err != nil {
+			t.Errorf("Expected second response to succeed, got %+v", responseInfos[1])
+		}
+		if got := responseInfos[1].Header.Get("X-Request-Id"); got != "req-123" {
+			t.Errorf("Expected X-Request-Id 'req-123', got '%s'", got)
+		}
+		if responseInfos[1].Latency <= 0 {
+			t.Errorf("Expected positive latency, got %v", responseInfos[1].Latency)
+		}
+
+		if len(retryInfos) != 1 {
+			t.Fatalf("Expected 1 OnRetry call, got %d", len(retryInfos))
+		}
+		if retryInfos[0].Attempt != 1 || retryInfos[0].Delay <= 0 || retryInfos[0].Err == nil {
+			t.Errorf("Unexpected retry info: %+v", retryInfos[0])
+		}
+	})
+
+	t.Run("TransportError", func(t *testing.T) {
+		var info gopenrouter.ResponseInfo
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL("http://127.0.0.1:0"),
+			gopenrouter.WithHooks(gopenrouter.Hooks{
+				OnResponse: func(ctx context.Context, i gopenrouter.ResponseInfo) {
+					info = i
+				},
+			}),
+		)
+
+		if _, err := client.GetCredits(context.Background()); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if info.StatusCode != 0 || info.Header != nil || info.Err == nil {
+			t.Errorf("Expected transport error without status, got %+v", info)
+		}
+	})
+
+	t.Run("StreamChunks", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte(
+				"data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hello\"}}]}\n\n" +
+					"data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\" world\"}}]}\n\n" +
+					"data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		var chunks []gopenrouter.StreamChunkInfo
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithHooks(gopenrouter.Hooks{
+				OnStreamChunk: func(ctx context.Context, info gopenrouter.StreamChunkInfo) {
+					chunks = append(chunks, info)
+				},
+			}),
+		)
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer stream.Close()
+
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		if len(chunks) != 2 {
+			t.Fatalf("Expected 2 OnStreamChunk calls, got %d", len(chunks))
+		}
+		for i, chunk := range chunks {
+			if chunk.Chunk != i+1 || chunk.Elapsed <= 0 || len(chunk.Raw) == 0 {
+				t.Errorf("Unexpected chunk info for chunk %d: %+v", i+1, chunk)
+			}
+		}
+	})
+
+	t.Run("StreamEnd", func(t *testing.T) {
+		stream := gopenroutertest.NewStream().
+			Chunk(gopenroutertest.ChatChunk("chatcmpl-1", "openai/gpt-4o-2024-08-06", "Hello")).
+			Error(502, "Provider returned error")
+		server := httptest.NewServer(stream)
+		defer server.Close()
+
+		var ends []gopenrouter.StreamEndInfo
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithHooks(gopenrouter.Hooks{
+				OnStreamEnd: func(ctx context.Context, info gopenrouter.StreamEndInfo) {
+					ends = append(ends, info)
+				},
+			}),
+		)
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		reader, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer reader.Close()
+
+		for {
+			if _, err := reader.Recv(); err != nil {
+				break
+			}
+		}
+		_, _ = reader.Recv()
+
+		if len(ends) != 1 {
+			t.Fatalf("Expected 1 OnStreamEnd call, got %d", len(ends))
+		}
+		end := ends[0]
+		if end.Endpoint != "/chat/completions" || end.Model != "openai/gpt-4o-2024-08-06" {
+			t.Errorf("Expected the chat endpoint and responding model, got %s and %s", end.Endpoint, end.Model)
+		}
+		if end.Err == nil {
+			t.Error("Expected the stream error")
+		}
+		if end.Metrics.TimeToFirstToken <= 0 || end.Metrics.TotalDuration < end.Metrics.TimeToFirstToken {
+			t.Errorf("Expected time to first token within the duration, got %+v", end.Metrics)
+		}
+	})
+
+	t.Run("StreamClosedEarly", func(t *testing.T) {
+		stream := gopenroutertest.NewStream().
+			Chunk(gopenroutertest.ChatChunk("chatcmpl-1", "openai/gpt-4o-2024-08-06", "Hello")).
+			Chunk(gopenroutertest.ChatChunk("chatcmpl-1", "openai/gpt-4o-2024-08-06", " world")).
+			Done()
+		server := httptest.NewServer(stream)
+		defer server.Close()
+
+		var ends []gopenrouter.StreamEndInfo
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithHooks(gopenrouter.Hooks{
+				OnStreamEnd: func(ctx context.Context, info gopenrouter.StreamEndInfo) {
+					ends = append(ends, info)
+				},
+			}),
+		)
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		reader, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := reader.Recv(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		_ = reader.Close()
+		_ = reader.Close()
+
+		if len(ends) != 1 {
+			t.Fatalf("Expected 1 OnStreamEnd call, got %d", len(ends))
+		}
+		end := ends[0]
+		if end.Endpoint != "/chat/completions" || end.Model != "openai/gpt-4o-2024-08-06" {
+			t.Errorf("Expected the chat endpoint and responding model, got %s and %s", end.Endpoint, end.Model)
+		}
+		if !errors.Is(end.Err, gopenrouter.ErrStreamClosed) {
+			t.Errorf("Expected ErrStreamClosed, got %v", end.Err)
+		}
+		if end.Metrics.TimeToFirstToken <= 0 || end.Metrics.TotalDuration < end.Metrics.TimeToFirstToken {
+			t.Errorf("Expected time to first token within the duration, got %+v", end.Metrics)
+		}
+	})
+}
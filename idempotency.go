@@ -0,0 +1,79 @@
+package gopenrouter
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// idempotencyKeyHeader is the header carrying the idempotency key of a request.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKeyContextKey is the context key under which an explicit idempotency key is stored.
+type idempotencyKeyContextKey struct{}
+
+// ContextWithIdempotencyKey returns a context that makes requests sent with it carry the
+// given Idempotency-Key header, allowing callers to deduplicate requests across their own
+// retries or process restarts. When retries are enabled with WithRetry, POST requests are
+// given a random key automatically, shared by all attempts of the same request and by the
+// reconnections of a stream enabled with WithStreamReconnect.
+//
+// Example usage:
+//
+//	ctx = gopenrouter.ContextWithIdempotencyKey(ctx, order.ID)
+//	response, err := client.ChatCompletion(ctx, *request)
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// setIdempotencyKey sets the Idempotency-Key header of the request, using the key stored
+// in the request context or, if retries are enabled, a newly generated one for POST requests.
+// An existing header is left untouched.
+func (c *Client) setIdempotencyKey(req *http.Request) error {
+	if req.Header.Get(idempotencyKeyHeader) != "" {
+		return nil
+	}
+
+	if key, ok := req.Context().Value(idempotencyKeyContextKey{}).(string); ok && key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+		return nil
+	}
+
+	if c.retryAttempts > 1 && req.Method == http.MethodPost {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return err
+		}
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+	return nil
+}
+
+// contextWithStreamKey returns ctx carrying the idempotency key a stream request would be
+// given, so the reconnection attempts of the stream reuse the key of the initial request
+// instead of generating their own.
+func (c *Client) contextWithStreamKey(ctx context.Context) (context.Context, error) {
+	if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok && key != "" {
+		return ctx, nil
+	}
+	if c.retryAttempts <= 1 {
+		return ctx, nil
+	}
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+	return ContextWithIdempotencyKey(ctx, key), nil
+}
+
+// newIdempotencyKey returns a random version 4 UUID.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("error generating idempotency key: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,157 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	policy := gopenrouter.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	// newServer returns a server failing the first attempt of every request and
+	// recording the idempotency keys it received
+	newServer := func(keys *[]string) *httptest.Server {
+		var mu sync.Mutex
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			*keys = append(*keys, r.Header.Get("Idempotency-Key"))
+			attempt := len(*keys)
+			mu.Unlock()
+
+			if attempt%2 == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`))
+		}))
+	}
+
+	t.Run("GeneratedAndReusedAcrossAttempts", func(t *testing.T) {
+		var keys []string
+		server := newServer(&keys)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithRetry(2, policy))
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		for range 2 {
+			if _, err := client.ChatCompletion(context.Background(), *request); err != nil {
+				t.Fatalf("ChatCompletion failed: %v", err)
+			}
+		}
+
+		if len(keys) != 4 {
+			t.Fatalf("Expected 4 attempts, got %d", len(keys))
+		}
+		if !uuidPattern.MatchString(keys[0]) {
+			t.Errorf("Expected a UUID idempotency key, got '%s'", keys[0])
+		}
+		if keys[0] != keys[1] || keys[2] != keys[3] {
+			t.Errorf("Expected attempts of the same request to share a key, got %v", keys)
+		}
+		if keys[0] == keys[2] {
+			t.Errorf("Expected separate requests to use different keys, got %v", keys)
+		}
+	})
+
+	t.Run("ExplicitKey", func(t *testing.T) {
+		var keys []string
+		server := newServer(&keys)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithRetry(2, policy))
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		ctx := gopenrouter.ContextWithIdempotencyKey(context.Background(), "order-42")
+		if _, err := client.ChatCompletion(ctx, *request); err != nil {
+			t.Fatalf("ChatCompletion failed: %v", err)
+		}
+		for _, key := range keys {
+			if key != "order-42" {
+				t.Errorf("Expected idempotency key 'order-42', got '%s'", key)
+			}
+		}
+	})
+
+	t.Run("ReusedAcrossStreamReconnects", func(t *testing.T) {
+		var mu sync.Mutex
+		var keys []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			attempt := len(keys)
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			if attempt == 1 {
+				// Drop the connection before the stream has finished
+				_, _ = w.Write([]byte("id: 1\n" + `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hello"}}]}` + "\n\n"))
+				return
+			}
+			_, _ = w.Write([]byte("id: 2\n" + `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":" world"}}]}` + "\n\n"))
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithRetry(2, policy),
+			gopenrouter.WithStreamReconnect(2))
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("ChatCompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+		for {
+			if _, err := stream.Recv(); err != nil {
+				if err != io.EOF {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				break
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(keys) != 2 {
+			t.Fatalf("Expected 2 connection attempts, got %d", len(keys))
+		}
+		if !uuidPattern.MatchString(keys[0]) {
+			t.Errorf("Expected a UUID idempotency key, got '%s'", keys[0])
+		}
+		if keys[0] != keys[1] {
+			t.Errorf("Expected the reconnection to reuse the key of the initial request, got %v", keys)
+		}
+	})
+
+	t.Run("NotSetWithoutRetries", func(t *testing.T) {
+		var key string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key = r.Header.Get("Idempotency-Key")
+			_, _ = w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+		if _, err := client.ChatCompletion(context.Background(), *request); err != nil {
+			t.Fatalf("ChatCompletion failed: %v", err)
+		}
+		if key != "" {
+			t.Errorf("Expected no idempotency key, got '%s'", key)
+		}
+	})
+}
@@ -0,0 +1,245 @@
+//go:build integration
+
+package gopenrouter_test
+
+// The tests in this file exercise the live OpenRouter API, to catch changes of the API
+// before users do. They only run with the integration build tag and an API key:
+//
+//	OPENROUTER_API_KEY=... go test -tags integration -run Live ./...
+//
+// Completions use a free model, which can be chosen with OPENROUTER_TEST_MODEL.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// liveClient returns a client for the live API, skipping the test without an API key.
+func liveClient(t *testing.T) *gopenrouter.Client {
+	t.Helper()
+	apiKey := os.Getenv("OPENROUTER_API_KEY")
+	if apiKey == "" {
+		t.Skip("OPENROUTER_API_KEY is not set")
+	}
+	return gopenrouter.New(apiKey,
+		gopenrouter.WithSiteTitle("gopenrouter integration tests"),
+		gopenrouter.WithRetry(3, gopenrouter.RetryPolicy{}),
+		gopenrouter.WithRequestTimeout(time.Minute),
+	)
+}
+
+// liveModel returns the model used for completions: OPENROUTER_TEST_MODEL if set, or
+// the first free text model listed.
+func liveModel(t *testing.T, client *gopenrouter.Client) string {
+	t.Helper()
+	if model := os.Getenv("OPENROUTER_TEST_MODEL"); model != "" {
+		return model
+	}
+
+	models, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list models: %v", err)
+	}
+	for _, model := range models {
+		if strings.HasSuffix(model.ID, ":free") && slices.Contains(model.Architecture.OutputModalities, "text") {
+			return model.ID
+		}
+	}
+	t.Skip("No free text model is available; set OPENROUTER_TEST_MODEL")
+	return ""
+}
+
+// assertKnownFields decodes data into a value of the type of v, reporting the fields
+// that the type does not model. Unknown fields are logged rather than failing the test,
+// as OpenRouter adds fields without notice; they hint at features worth supporting.
+func assertKnownFields(t *testing.T, data []byte, v any) {
+	t.Helper()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		t.Logf("Response has fields not modeled by %T: %v", v, err)
+	}
+}
+
+func TestLiveModels(t *testing.T) {
+	client := liveClient(t)
+	ctx := context.Background()
+
+	models, err := client.ListModels(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list models: %v", err)
+	}
+	if len(models) == 0 {
+		t.Fatal("Expected at least one model")
+	}
+	for _, model := range models {
+		if model.ID == "" || model.Name == "" || model.ContextLength == nil {
+			t.Errorf("Expected model with ID, name, and context length, got %+v", model)
+			break
+		}
+		if _, err := gopenrouter.EstimateCost(model.Pricing, 1, 1); err != nil && !errors.Is(err, gopenrouter.ErrVariablePricing) {
+			t.Errorf("Expected parseable pricing for %s, got %v", model.ID, err)
+			break
+		}
+	}
+
+	var raw struct {
+		Data []json.RawMessage `json:"data"`
+	}
+	if err := client.Do(ctx, http.MethodGet, "/models", nil, &raw); err != nil {
+		t.Fatalf("Failed to list raw models: %v", err)
+	}
+	if len(raw.Data) > 0 {
+		assertKnownFields(t, raw.Data[0], &gopenrouter.ModelData{})
+	}
+
+	author, slug, _ := strings.Cut(liveModel(t, client), "/")
+	endpoints, err := client.ListEndpoints(ctx, author, strings.TrimSuffix(slug, ":free"))
+	if err != nil {
+		t.Fatalf("Failed to list endpoints of %s/%s: %v", author, slug, err)
+	}
+	if len(endpoints.Endpoints) == 0 {
+		t.Errorf("Expected endpoints for %s/%s", author, slug)
+	}
+}
+
+func TestLiveChatCompletion(t *testing.T) {
+	client := liveClient(t)
+	model := liveModel(t, client)
+	ctx := context.Background()
+
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Reply with the word pong."}}
+	request := gopenrouter.NewChatCompletionRequestBuilder(model, messages).
+		WithMaxTokens(16).
+		WithUsage(true).
+		Build()
+
+	response, err := client.ChatCompletion(ctx, *request)
+	if err != nil {
+		t.Fatalf("Chat completion failed: %v", err)
+	}
+	if response.ID == "" || response.Model == "" || response.Provider == "" {
+		t.Errorf("Expected ID, model, and provider, got %q, %q, %q", response.ID, response.Model, response.Provider)
+	}
+	if len(response.Choices) == 0 || response.Choices[0].FinishReason == "" {
+		t.Errorf("Expected a finished choice, got %+v", response.Choices)
+	}
+	if response.Usage.PromptTokens == 0 || response.Usage.TotalTokens == 0 {
+		t.Errorf("Expected token usage, got %+v", response.Usage)
+	}
+
+	var raw json.RawMessage
+	if err := client.Do(ctx, http.MethodPost, "/chat/completions", request, &raw); err != nil {
+		t.Fatalf("Raw chat completion failed: %v", err)
+	}
+	assertKnownFields(t, raw, &gopenrouter.ChatCompletionResponse{})
+
+	t.Run("Generation", func(t *testing.T) {
+		var generation gopenrouter.GenerationData
+		for attempt := range 5 {
+			generation, err = client.GetGeneration(ctx, response.ID)
+			var apiErr *gopenrouter.APIError
+			if !errors.As(err, &apiErr) || apiErr.Code != http.StatusNotFound {
+				break
+			}
+			// Generations are recorded shortly after they complete
+			time.Sleep(time.Duration(attempt+1) * time.Second)
+		}
+		if err != nil {
+			t.Fatalf("Failed to get generation %s: %v", response.ID, err)
+		}
+		if generation.ID != response.ID || generation.Model == "" {
+			t.Errorf("Expected generation %s with a model, got %+v", response.ID, generation)
+		}
+	})
+}
+
+func TestLiveChatCompletionStream(t *testing.T) {
+	client := liveClient(t)
+	model := liveModel(t, client)
+
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Count from 1 to 5."}}
+	request := gopenrouter.NewChatCompletionRequestBuilder(model, messages).
+		WithMaxTokens(32).
+		WithUsage(true).
+		Build()
+
+	stream, err := client.ChatCompletionStream(context.Background(), *request)
+	if err != nil {
+		t.Fatalf("Chat completion stream failed: %v", err)
+	}
+	defer stream.Close()
+
+	var chunks int
+	var usage *gopenrouter.Usage
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to receive chunk %d: %v", chunks+1, err)
+		}
+		chunks++
+		if chunk.ID == "" {
+			t.Errorf("Expected chunk %d to have an ID", chunks)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+	}
+
+	if chunks == 0 {
+		t.Error("Expected at least one chunk")
+	}
+	if stream.SkippedChunks() > 0 {
+		t.Errorf("Expected all chunks to decode, %d were skipped", stream.SkippedChunks())
+	}
+	if usage == nil || usage.TotalTokens == 0 {
+		t.Errorf("Expected a usage chunk, got %+v", usage)
+	}
+}
+
+func TestLiveCompletion(t *testing.T) {
+	client := liveClient(t)
+	model := liveModel(t, client)
+
+	request := gopenrouter.NewCompletionRequestBuilder(model, "The capital of France is").
+		WithMaxTokens(8).
+		Build()
+
+	response, err := client.Completion(context.Background(), *request)
+	if err != nil {
+		t.Fatalf("Completion failed: %v", err)
+	}
+	if response.ID == "" || len(response.Choices) == 0 {
+		t.Errorf("Expected an ID and a choice, got %+v", response)
+	}
+}
+
+func TestLiveCredits(t *testing.T) {
+	client := liveClient(t)
+
+	credits, err := client.GetCredits(context.Background())
+	var apiErr *gopenrouter.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden {
+		t.Skip("Credits require a provisioning key")
+	}
+	if err != nil {
+		t.Fatalf("Failed to get credits: %v", err)
+	}
+	if credits.TotalCredits < 0 || credits.TotalUsage < 0 {
+		t.Errorf("Expected non-negative credits, got %+v", credits)
+	}
+}
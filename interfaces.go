@@ -0,0 +1,52 @@
+package gopenrouter
+
+import "context"
+
+// The interfaces below describe the capabilities of the client, so that applications can
+// depend on the smallest interface they need and inject fakes in tests. *Client
+// implements all of them.
+//
+// Streams of fakes can be created with NewChatCompletionStreamReader and
+// NewCompletionStreamReader from HTTP responses, such as those recorded by
+// httptest.NewRecorder serving a gopenroutertest.Stream.
+
+// Completer generates text completions.
+type Completer interface {
+	Completion(ctx context.Context, request CompletionRequest, opts ...CallOption) (CompletionResponse, error)
+	CompletionStream(ctx context.Context, request CompletionRequest, opts ...CallOption) (*CompletionStreamReader, error)
+}
+
+// ChatCompleter generates chat completions.
+type ChatCompleter interface {
+	ChatCompletion(ctx context.Context, request ChatCompletionRequest, opts ...CallOption) (ChatCompletionResponse, error)
+	ChatCompletionStream(ctx context.Context, request ChatCompletionRequest, opts ...CallOption) (*ChatCompletionStreamReader, error)
+}
+
+// ModelLister retrieves the available models and their provider endpoints.
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]ModelData, error)
+	ListModelsWithOptions(ctx context.Context, options ListModelsOptions) ([]ModelData, error)
+	GetModel(ctx context.Context, author, slug string) (ModelData, error)
+	ListEndpoints(ctx context.Context, author, slug string) (EndpointData, error)
+}
+
+// GenerationGetter retrieves the metadata of generations.
+type GenerationGetter interface {
+	GetGeneration(ctx context.Context, id string) (GenerationData, error)
+}
+
+// CreditsGetter retrieves the credits of the account.
+type CreditsGetter interface {
+	GetCredits(ctx context.Context) (CreditsData, error)
+}
+
+// API combines the capabilities of the client used by most applications.
+type API interface {
+	Completer
+	ChatCompleter
+	ModelLister
+	GenerationGetter
+	CreditsGetter
+}
+
+var _ API = (*Client)(nil)
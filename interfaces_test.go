@@ -0,0 +1,104 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+// fakeChatCompleter answers chat completions with a fixed reply.
+type fakeChatCompleter struct {
+	reply    string
+	requests []gopenrouter.ChatCompletionRequest
+}
+
+func (f *fakeChatCompleter) ChatCompletion(ctx context.Context, request gopenrouter.ChatCompletionRequest, opts ...gopenrouter.CallOption) (gopenrouter.ChatCompletionResponse, error) {
+	f.requests = append(f.requests, request)
+	return gopenrouter.ChatCompletionResponse{
+		ID:      "gen-fake",
+		Choices: []gopenrouter.ChatChoice{{Message: gopenrouter.ChatMessage{Role: "assistant", Content: f.reply}}},
+	}, nil
+}
+
+func (f *fakeChatCompleter) ChatCompletionStream(ctx context.Context, request gopenrouter.ChatCompletionRequest, opts ...gopenrouter.CallOption) (*gopenrouter.ChatCompletionStreamReader, error) {
+	f.requests = append(f.requests, request)
+	recorder := httptest.NewRecorder()
+	gopenroutertest.NewStream().
+		Chunk(gopenroutertest.ChatChunk("gen-fake", request.Model, f.reply)).
+		Done().
+		ServeHTTP(recorder, nil)
+	return gopenrouter.NewChatCompletionStreamReader(recorder.Result()), nil
+}
+
+// ask sends a question through any chat completer, as application code would.
+func ask(ctx context.Context, completer gopenrouter.ChatCompleter, question string) (string, error) {
+	request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", []gopenrouter.ChatMessage{{Role: "user", Content: question}}).Build()
+	response, err := completer.ChatCompletion(ctx, *request)
+	if err != nil {
+		return "", err
+	}
+	return response.Choices[0].Message.Content, nil
+}
+
+func TestInterfaces(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Client", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"gen-1","choices":[{"message":{"role":"assistant","content":"Paris"}}]}`))
+		}))
+		defer server.Close()
+
+		var completer gopenrouter.ChatCompleter = gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		answer, err := ask(ctx, completer, "What is the capital of France?")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if answer != "Paris" {
+			t.Errorf("Expected answer Paris, got %s", answer)
+		}
+	})
+
+	t.Run("Fake", func(t *testing.T) {
+		fake := &fakeChatCompleter{reply: "Paris"}
+		answer, err := ask(ctx, fake, "What is the capital of France?")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if answer != "Paris" || len(fake.requests) != 1 {
+			t.Errorf("Expected answer Paris from 1 request, got %s from %d", answer, len(fake.requests))
+		}
+	})
+
+	t.Run("FakeStream", func(t *testing.T) {
+		var completer gopenrouter.ChatCompleter = &fakeChatCompleter{reply: "Paris"}
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", nil).Build()
+		stream, err := completer.ChatCompletionStream(ctx, *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer stream.Close()
+
+		var text strings.Builder
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			text.WriteString(chunk.Text())
+		}
+		if text.String() != "Paris" {
+			t.Errorf("Expected streamed text Paris, got %s", text.String())
+		}
+	})
+}
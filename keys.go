@@ -0,0 +1,305 @@
+package gopenrouter
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// keysResponse represents the internal API response structure when listing API keys.
+type keysResponse struct {
+	Data []APIKey `json:"data"`
+}
+
+// keyResponse represents the internal API response structure for a single API key.
+type keyResponse struct {
+	httpHeader
+
+	Data APIKey `json:"data"`
+	// Key is only returned when the key is created
+	Key string `json:"key,omitempty"`
+}
+
+// APIKey contains information about an API key managed with a provisioning key.
+// The secret value of the key is never included; keys are identified by their hash.
+type APIKey struct {
+	httpHeader
+
+	// Hash is the unique identifier of the key, used to retrieve, update, or delete it
+	Hash string `json:"hash"`
+	// Name is the name of the key
+	Name string `json:"name"`
+	// Label is a human-readable label derived from the key value
+	Label string `json:"label"`
+	// Disabled indicates if the key has been disabled
+	Disabled bool `json:"disabled"`
+	// Limit is the credit limit of the key, or nil if the key is unlimited
+	Limit *float64 `json:"limit,omitempty"`
+	// LimitRemaining is the remaining credit limit of the key, or nil if the key is unlimited
+	LimitRemaining *float64 `json:"limit_remaining,omitempty"`
+	// LimitReset is the interval at which the credit limit resets (e.g., "daily", "weekly", "monthly"), if any
+	LimitReset *string `json:"limit_reset,omitempty"`
+	// IncludeBYOKInLimit indicates if "Bring Your Own Key" usage counts towards the limit
+	IncludeBYOKInLimit bool `json:"include_byok_in_limit"`
+	// Usage is the total amount of credits consumed with the key
+	Usage float64 `json:"usage"`
+	// UsageDaily is the amount of credits consumed with the key in the current UTC day
+	UsageDaily float64 `json:"usage_daily"`
+	// UsageWeekly is the amount of credits consumed with the key in the current UTC week
+	UsageWeekly float64 `json:"usage_weekly"`
+	// UsageMonthly is the amount of credits consumed with the key in the current UTC month
+	UsageMonthly float64 `json:"usage_monthly"`
+	// CreatedAt is the timestamp when the key was created
+	CreatedAt string `json:"created_at"`
+	// UpdatedAt is the timestamp when the key was last updated, if ever
+	UpdatedAt *string `json:"updated_at,omitempty"`
+}
+
+// CreatedKey contains information about a newly created API key, including its secret value.
+type CreatedKey struct {
+	APIKey
+
+	// Key is the secret value of the key, used to authenticate requests.
+	// It is only returned when the key is created and cannot be retrieved later.
+	Key string `json:"key"`
+}
+
+// ListKeysOptions configures the API keys returned by ListKeys.
+type ListKeysOptions struct {
+	// Offset is the number of keys to skip, for paginating through the keys
+	Offset int
+	// IncludeDisabled includes disabled keys in the list
+	IncludeDisabled bool
+}
+
+// CreateKeyRequest represents a request to create an API key.
+type CreateKeyRequest struct {
+	// Name is the name of the key (required)
+	Name string `json:"name"`
+	// Limit is the credit limit of the key; the key is unlimited if nil
+	Limit *float64 `json:"limit,omitempty"`
+	// LimitReset is the interval at which the credit limit resets (e.g., "daily", "weekly", "monthly")
+	LimitReset string `json:"limit_reset,omitempty"`
+	// IncludeBYOKInLimit makes "Bring Your Own Key" usage count towards the limit
+	IncludeBYOKInLimit bool `json:"include_byok_in_limit,omitempty"`
+}
+
+// UpdateKeyRequest represents a request to update an API key.
+// Only the fields that are set are changed.
+type UpdateKeyRequest struct {
+	// Name is the new name of the key
+	Name *string `json:"name,omitempty"`
+	// Disabled enables or disables the key
+	Disabled *bool `json:"disabled,omitempty"`
+	// Limit is the new credit limit of the key
+	Limit *float64 `json:"limit,omitempty"`
+	// LimitReset is the new interval at which the credit limit resets
+	LimitReset *string `json:"limit_reset,omitempty"`
+	// IncludeBYOKInLimit sets whether "Bring Your Own Key" usage counts towards the limit
+	IncludeBYOKInLimit *bool `json:"include_byok_in_limit,omitempty"`
+}
+
+// ListKeys retrieves the API keys created with the provisioning key of the client.
+//
+// Key management endpoints must be authenticated with a provisioning key, which can be
+// created in the OpenRouter settings. Regular API keys are rejected.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - options: Pagination and filtering of the returned keys
+//
+// Returns:
+//   - []APIKey: A list of API keys
+//   - error: Any error that occurred during the request
+func (c *Client) ListKeys(ctx context.Context, options ListKeysOptions) (keys []APIKey, err error) {
+	urlSuffix := "/keys"
+	var response keysResponse
+
+	var setters []requestOption
+	if options.Offset > 0 {
+		setters = append(setters, withQueryParam("offset", strconv.Itoa(options.Offset)))
+	}
+	if options.IncludeDisabled {
+		setters = append(setters, withQueryParam("include_disabled", "true"))
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), setters...)
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	if err != nil {
+		return
+	}
+
+	keys = response.Data
+	return
+}
+
+// keysPageSize is the number of keys OpenRouter returns per page of the key listing.
+const keysPageSize = 100
+
+// ListKeysPage retrieves one page of the API keys created with the provisioning key of
+// the client. The cursor of the first page is empty; the cursor of the following page is
+// given by the NextCursor of the returned page. The offset of the options is ignored
+// unless the cursor is empty. A page with fewer keys than the page size of the listing
+// is the last one, and has no NextCursor.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - cursor: The cursor identifying the page to retrieve
+//   - options: Filtering of the returned keys
+//
+// Returns:
+//   - Page[APIKey]: The keys on the page and the cursor of the next page
+//   - error: Any error that occurred during the request
+func (c *Client) ListKeysPage(ctx context.Context, cursor string, options ListKeysOptions) (page Page[APIKey], err error) {
+	if cursor != "" {
+		options.Offset, err = strconv.Atoi(cursor)
+		if err != nil || options.Offset < 0 {
+			return page, fmt.Errorf("invalid cursor %q", cursor)
+		}
+	}
+
+	page.Items, err = c.ListKeys(ctx, options)
+	if err != nil {
+		return
+	}
+
+	if len(page.Items) >= keysPageSize {
+		page.NextCursor = strconv.Itoa(options.Offset + len(page.Items))
+	}
+	return
+}
+
+// AllKeys returns an iterator over all API keys created with the provisioning key of the
+// client, fetching further pages as needed.
+//
+// Example usage:
+//
+//	for key, err := range client.AllKeys(ctx, gopenrouter.ListKeysOptions{}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(key.Name)
+//	}
+func (c *Client) AllKeys(ctx context.Context, options ListKeysOptions) iter.Seq2[APIKey, error] {
+	return Paginate(ctx, func(ctx context.Context, cursor string) (Page[APIKey], error) {
+		return c.ListKeysPage(ctx, cursor, options)
+	})
+}
+
+// CreateKey creates an API key, allowing platforms to mint keys for their tenants.
+//
+// The secret value of the key is only returned by this method and should be stored
+// securely. The client must be authenticated with a provisioning key.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - request: The name and credit limit of the key
+//
+// Returns:
+//   - CreatedKey: The created key, including its secret value
+//   - error: Any error that occurred during the request
+func (c *Client) CreateKey(ctx context.Context, request CreateKeyRequest) (key CreatedKey, err error) {
+	urlSuffix := "/keys"
+	var response keyResponse
+
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	if err != nil {
+		return
+	}
+
+	key.APIKey = response.Data
+	key.httpHeader = response.httpHeader
+	key.Key = response.Key
+	return
+}
+
+// GetKeyByHash retrieves an API key by its hash.
+// The client must be authenticated with a provisioning key.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - hash: The hash identifying the key
+//
+// Returns:
+//   - APIKey: Information about the key
+//   - error: Any error that occurred during the request
+func (c *Client) GetKeyByHash(ctx context.Context, hash string) (key APIKey, err error) {
+	urlSuffix := "/keys/" + url.PathEscape(hash)
+	var response keyResponse
+
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	if err != nil {
+		return
+	}
+
+	key = response.Data
+	key.httpHeader = response.httpHeader
+	return
+}
+
+// UpdateKey updates the name, status, or credit limit of an API key.
+// The client must be authenticated with a provisioning key.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - hash: The hash identifying the key
+//   - request: The fields of the key to change
+//
+// Returns:
+//   - APIKey: The updated key
+//   - error: Any error that occurred during the request
+func (c *Client) UpdateKey(ctx context.Context, hash string, request UpdateKeyRequest) (key APIKey, err error) {
+	urlSuffix := "/keys/" + url.PathEscape(hash)
+	var response keyResponse
+
+	req, err := c.newRequest(ctx, http.MethodPatch, c.fullURL(urlSuffix), withBody(request))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	if err != nil {
+		return
+	}
+
+	key = response.Data
+	key.httpHeader = response.httpHeader
+	return
+}
+
+// DeleteKey deletes an API key, immediately revoking access for requests using it.
+// The client must be authenticated with a provisioning key.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - hash: The hash identifying the key
+//
+// Returns:
+//   - error: Any error that occurred during the request
+func (c *Client) DeleteKey(ctx context.Context, hash string) error {
+	urlSuffix := "/keys/" + url.PathEscape(hash)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL(urlSuffix))
+	if err != nil {
+		return err
+	}
+
+	return c.sendRequest(req, nil)
+}
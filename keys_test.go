@@ -0,0 +1,318 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+const testKeyJSON = `{
+	"hash": "abc123",
+	"name": "tenant-1",
+	"label": "sk-or-v1-abc...123",
+	"disabled": false,
+	"limit": 10,
+	"limit_remaining": 7.5,
+	"limit_reset": "monthly",
+	"include_byok_in_limit": true,
+	"usage": 2.5,
+	"usage_daily": 0.5,
+	"usage_weekly": 1,
+	"usage_monthly": 2.5,
+	"created_at": "2025-01-01T00:00:00Z",
+	"updated_at": null
+}`
+
+func TestClientListKeys(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var query string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.URL.Path != "/keys" {
+				t.Errorf("Expected GET /keys, got %s %s", r.Method, r.URL.Path)
+			}
+			if auth := r.Header.Get("Authorization"); auth != "Bearer provisioning-key" {
+				t.Errorf("Expected provisioning key authorization, got %q", auth)
+			}
+			query = r.URL.RawQuery
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"data": [%s]}`, testKeyJSON)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("provisioning-key", gopenrouter.WithBaseURL(server.URL))
+		keys, err := client.ListKeys(context.Background(), gopenrouter.ListKeysOptions{Offset: 100, IncludeDisabled: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if query != "include_disabled=true&offset=100" {
+			t.Errorf("Expected offset and include_disabled query, got %q", query)
+		}
+		if len(keys) != 1 {
+			t.Fatalf("Expected 1 key, got %d", len(keys))
+		}
+		key := keys[0]
+		if key.Hash != "abc123" || key.Name != "tenant-1" {
+			t.Errorf("Expected key abc123 named tenant-1, got %s named %s", key.Hash, key.Name)
+		}
+		if key.Limit == nil || *key.Limit != 10 {
+			t.Errorf("Expected limit 10, got %v", key.Limit)
+		}
+		if key.LimitRemaining == nil || *key.LimitRemaining != 7.5 {
+			t.Errorf("Expected remaining limit 7.5, got %v", key.LimitRemaining)
+		}
+		if key.LimitReset == nil || *key.LimitReset != "monthly" {
+			t.Errorf("Expected monthly limit reset, got %v", key.LimitReset)
+		}
+		if key.UsageMonthly != 2.5 {
+			t.Errorf("Expected monthly usage 2.5, got %f", key.UsageMonthly)
+		}
+		if key.UpdatedAt != nil {
+			t.Errorf("Expected no update time, got %v", *key.UpdatedAt)
+		}
+	})
+
+	t.Run("DefaultOptions", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.RawQuery != "" {
+				t.Errorf("Expected no query, got %q", r.URL.RawQuery)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"data": []}`)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("provisioning-key", gopenrouter.WithBaseURL(server.URL))
+		keys, err := client.ListKeys(context.Background(), gopenrouter.ListKeysOptions{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(keys) != 0 {
+			t.Errorf("Expected no keys, got %d", len(keys))
+		}
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 401, "message": "Only provisioning keys can manage keys"}}`)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("regular-key", gopenrouter.WithBaseURL(server.URL))
+		_, err := client.ListKeys(context.Background(), gopenrouter.ListKeysOptions{})
+
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got %T: %v", err, err)
+		}
+		if apiErr.HTTPStatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", apiErr.HTTPStatusCode)
+		}
+	})
+}
+
+func TestClientCreateKey(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/keys" {
+			t.Errorf("Expected POST /keys, got %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Expected JSON body, got error %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"data": %s, "key": "sk-or-v1-secret"}`, testKeyJSON)
+	}))
+	defer server.Close()
+
+	client := gopenrouter.New("provisioning-key", gopenrouter.WithBaseURL(server.URL))
+	limit := 10.0
+	key, err := client.CreateKey(context.Background(), gopenrouter.CreateKeyRequest{
+		Name:       "tenant-1",
+		Limit:      &limit,
+		LimitReset: "monthly",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if body["name"] != "tenant-1" || body["limit"] != 10.0 || body["limit_reset"] != "monthly" {
+		t.Errorf("Expected name, limit, and limit_reset in body, got %v", body)
+	}
+	if _, ok := body["include_byok_in_limit"]; ok {
+		t.Errorf("Expected include_byok_in_limit to be omitted, got %v", body)
+	}
+	if key.Key != "sk-or-v1-secret" {
+		t.Errorf("Expected secret key value, got %q", key.Key)
+	}
+	if key.Hash != "abc123" {
+		t.Errorf("Expected hash abc123, got %s", key.Hash)
+	}
+	if key.RequestID() != "req-1" {
+		t.Errorf("Expected request ID req-1, got %q", key.RequestID())
+	}
+}
+
+func TestClientGetKeyByHash(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.EscapedPath() != "/keys/abc%2F123" {
+			t.Errorf("Expected GET /keys/abc%%2F123, got %s %s", r.Method, r.URL.EscapedPath())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"data": %s}`, testKeyJSON)
+	}))
+	defer server.Close()
+
+	client := gopenrouter.New("provisioning-key", gopenrouter.WithBaseURL(server.URL))
+	key, err := client.GetKeyByHash(context.Background(), "abc/123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if key.Name != "tenant-1" {
+		t.Errorf("Expected name tenant-1, got %s", key.Name)
+	}
+}
+
+func TestClientUpdateKey(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/keys/abc123" {
+			t.Errorf("Expected PATCH /keys/abc123, got %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Expected JSON body, got error %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"data": %s}`, testKeyJSON)
+	}))
+	defer server.Close()
+
+	client := gopenrouter.New("provisioning-key", gopenrouter.WithBaseURL(server.URL))
+	disabled := true
+	_, err := client.UpdateKey(context.Background(), "abc123", gopenrouter.UpdateKeyRequest{Disabled: &disabled})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(body) != 1 || body["disabled"] != true {
+		t.Errorf("Expected only disabled in body, got %v", body)
+	}
+}
+
+func TestClientDeleteKey(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var deleted bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodDelete || r.URL.Path != "/keys/abc123" {
+				t.Errorf("Expected DELETE /keys/abc123, got %s %s", r.Method, r.URL.Path)
+			}
+			deleted = true
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"deleted": true}`)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("provisioning-key", gopenrouter.WithBaseURL(server.URL))
+		if err := client.DeleteKey(context.Background(), "abc123"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !deleted {
+			t.Error("Expected delete request to be sent")
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 404, "message": "Key not found"}}`)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("provisioning-key", gopenrouter.WithBaseURL(server.URL))
+		err := client.DeleteKey(context.Background(), "missing")
+
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected APIError, got %T: %v", err, err)
+		}
+		if apiErr.HTTPStatusCode != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", apiErr.HTTPStatusCode)
+		}
+	})
+}
+
+func TestClientAllKeys(t *testing.T) {
+	// pageOf returns a key listing with count keys, named by their position from start
+	pageOf := func(start, count int) string {
+		data := make([]string, count)
+		for i := range data {
+			data[i] = fmt.Sprintf(`{"hash": "%d"}`, start+i)
+		}
+		return `{"data": [` + strings.Join(data, ",") + `]}`
+	}
+
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset := r.URL.Query().Get("offset")
+		offsets = append(offsets, offset)
+		if r.URL.Query().Get("include_disabled") != "true" {
+			t.Errorf("Expected include_disabled on every page, got %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch offset {
+		case "":
+			_, _ = fmt.Fprint(w, pageOf(0, 100))
+		case "100":
+			_, _ = fmt.Fprint(w, pageOf(100, 1))
+		default:
+			_, _ = fmt.Fprint(w, `{"data": []}`)
+		}
+	}))
+	defer server.Close()
+
+	client := gopenrouter.New("provisioning-key", gopenrouter.WithBaseURL(server.URL))
+
+	var hashes []string
+	for key, err := range client.AllKeys(context.Background(), gopenrouter.ListKeysOptions{IncludeDisabled: true}) {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		hashes = append(hashes, key.Hash)
+	}
+
+	if len(hashes) != 101 || hashes[0] != "0" || hashes[100] != "100" {
+		t.Errorf("Expected keys 0 to 100, got %v", hashes)
+	}
+	// The second page is shorter than the page size, so no empty page is requested after it
+	if len(offsets) != 2 || offsets[1] != "100" {
+		t.Errorf("Expected offsets \"\" and 100, got %q", offsets)
+	}
+
+	t.Run("ShortPage", func(t *testing.T) {
+		page, err := client.ListKeysPage(context.Background(), "100", gopenrouter.ListKeysOptions{IncludeDisabled: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(page.Items) != 1 || page.HasNext() {
+			t.Errorf("Expected a last page with 1 key, got %d keys and cursor %q", len(page.Items), page.NextCursor)
+		}
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		if _, err := client.ListKeysPage(context.Background(), "not-a-number", gopenrouter.ListKeysOptions{}); err == nil {
+			t.Error("Expected error for invalid cursor, got nil")
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package gopenrouter
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// LogLevels configures the levels at which the client logs its events.
+// Fields left unset log at slog.LevelInfo.
+type LogLevels struct {
+	// Request is the level of events logged before each attempt of a request
+	Request slog.Level
+	// Response is the level of events logged for successful responses, completions, and streams
+	Response slog.Level
+	// Retry is the level of events logged before retrying a failed attempt
+	Retry slog.Level
+	// Error is the level of events logged for failed attempts and streams
+	Error slog.Level
+}
+
+// defaultLogLevels are the levels used unless configured with WithLogLevels.
+var defaultLogLevels = LogLevels{
+	Request:  slog.LevelDebug,
+	Response: slog.LevelDebug,
+	Retry:    slog.LevelWarn,
+	Error:    slog.LevelError,
+}
+
+// clientLogger emits structured events about the requests sent by the client.
+type clientLogger struct {
+	logger *slog.Logger
+	levels LogLevels
+}
+
+// WithLogger sets a logger receiving structured events about requests, responses,
+// retries, token usage, and streams. By default, requests and responses are logged
+// at debug level, retries at warn level, and failures at error level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.log.logger = logger
+	}
+}
+
+// WithLogLevels sets the levels at which the events of the logger set with WithLogger
+// are emitted.
+func WithLogLevels(levels LogLevels) Option {
+	return func(c *Client) {
+		c.log.levels = levels
+	}
+}
+
+// enabled reports whether events at level are logged.
+func (l *clientLogger) enabled(ctx context.Context, level slog.Level) bool {
+	return l.logger != nil && l.logger.Enabled(ctx, level)
+}
+
+// request logs an attempt of req about to be sent.
+func (l *clientLogger) request(req *http.Request, attempt int) {
+	if !l.enabled(req.Context(), l.levels.Request) {
+		return
+	}
+	l.logger.LogAttrs(req.Context(), l.levels.Request, "openrouter request",
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Int("attempt", attempt),
+	)
+}
+
+// response logs the outcome of an attempt of req.
+func (l *clientLogger) response(req *http.Request, attempt int, latency time.Duration, resp *http.Response, err error) {
+	level := l.levels.Response
+	if err != nil {
+		level = l.levels.Error
+	}
+	if !l.enabled(req.Context(), level) {
+		return
+	}
+
+	status, header := responseStatus(resp, err)
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Int("attempt", attempt),
+		slog.Int("status", status),
+		slog.Duration("latency", latency),
+	}
+	if id := requestID(header); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	l.logger.LogAttrs(req.Context(), level, "openrouter response", attrs...)
+}
+
+// retry logs a retry of req about to be made after a failed attempt.
+func (l *clientLogger) retry(req *http.Request, attempt int, delay time.Duration, err error) {
+	if !l.enabled(req.Context(), l.levels.Retry) {
+		return
+	}
+	l.logger.LogAttrs(req.Context(), l.levels.Retry, "openrouter retry",
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+		slog.Any("error", err),
+	)
+}
+
+// completion logs the model, provider, and token usage of a completed request.
+func (l *clientLogger) completion(ctx context.Context, model, provider string, usage Usage) {
+	if !l.enabled(ctx, l.levels.Response) {
+		return
+	}
+
+	attrs := []slog.Attr{slog.String("model", model)}
+	if provider != "" {
+		attrs = append(attrs, slog.String("provider", provider))
+	}
+	attrs = append(attrs,
+		slog.Int("prompt_tokens", usage.PromptTokens),
+		slog.Int("completion_tokens", usage.CompletionTokens),
+		slog.Int("total_tokens", usage.TotalTokens),
+	)
+	if usage.Cost > 0 {
+		attrs = append(attrs, slog.Float64("cost", usage.Cost))
+	}
+	l.logger.LogAttrs(ctx, l.levels.Response, "openrouter completion", attrs...)
+}
+
+// streamEnd logs the metrics of a stream that has been read to its end.
+func (l *clientLogger) streamEnd(ctx context.Context, info StreamEndInfo) {
+	failed := info.Err != nil
+	level := l.levels.Response
+	if failed && !errors.Is(info.Err, ErrStreamClosed) {
+		level = l.levels.Error
+	}
+	if !l.enabled(ctx, level) {
+		return
+	}
+
+	metrics := info.Metrics
+	attrs := []slog.Attr{slog.String("model", info.Model)}
+	if info.Provider != "" {
+		attrs = append(attrs, slog.String("provider", info.Provider))
+	}
+	attrs = append(attrs,
+		slog.Int("chunks", metrics.Chunks),
+		slog.Duration("time_to_first_token", metrics.TimeToFirstToken),
+		slog.Duration("duration", metrics.TotalDuration),
+		slog.Int("completion_tokens", metrics.CompletionTokens),
+	)
+	if failed {
+		attrs = append(attrs, slog.Any("error", info.Err))
+	}
+	l.logger.LogAttrs(ctx, level, "openrouter stream finished", attrs...)
+}
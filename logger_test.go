@@ -0,0 +1,182 @@
+package gopenrouter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// parseLogRecords decodes the JSON log records written to buf.
+func parseLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Failed to decode log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogger(t *testing.T) {
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	t.Run("RequestRetryAndCompletion", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"error":{"code":429,"message":"rate limited"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"cmpl-1","provider":"OpenAI","model":"test-model","choices":[{"text":"Hi","index":0}],"usage":{"prompt_tokens":3,"completion_tokens":5,"total_tokens":8}}`))
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithLogger(logger),
+			gopenrouter.WithRetry(2, gopenrouter.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+		)
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "Hello").Build()
+
+		if _, err := client.Completion(context.Background(), *request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		records := parseLogRecords(t, &buf)
+		expected := []struct {
+			msg   string
+			level string
+		}{
+			{"openrouter request", "DEBUG"},
+			{"openrouter response", "ERROR"},
+			{"openrouter retry", "WARN"},
+			{"openrouter request", "DEBUG"},
+			{"openrouter response", "DEBUG"},
+			{"openrouter completion", "DEBUG"},
+		}
+		if len(records) != len(expected) {
+			t.Fatalf("Expected %d log records, got %d: %v", len(expected), len(records), records)
+		}
+		for i, e := range expected {
+			if records[i]["msg"] != e.msg || records[i]["level"] != e.level {
+				t.Errorf("Expected record %d to be %s at %s, got %v", i, e.msg, e.level, records[i])
+			}
+		}
+
+		if records[1]["status"] != float64(http.StatusTooManyRequests) || records[1]["error"] == nil {
+			t.Errorf("Expected failed response with status 429 and error, got %v", records[1])
+		}
+		if records[4]["status"] != float64(http.StatusOK) || records[4]["attempt"] != float64(2) {
+			t.Errorf("Expected second attempt to succeed, got %v", records[4])
+		}
+		completion := records[5]
+		if completion["model"] != "test-model" || completion["provider"] != "OpenAI" || completion["total_tokens"] != float64(8) {
+			t.Errorf("Unexpected completion record: %v", completion)
+		}
+	})
+
+	t.Run("LogLevels", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`))
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithLogger(logger),
+			gopenrouter.WithLogLevels(gopenrouter.LogLevels{
+				Request:  slog.LevelDebug,
+				Response: slog.LevelInfo,
+				Retry:    slog.LevelWarn,
+				Error:    slog.LevelError,
+			}),
+		)
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		if _, err := client.ChatCompletion(context.Background(), *request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		records := parseLogRecords(t, &buf)
+		if len(records) != 2 {
+			t.Fatalf("Expected 2 log records, got %d: %v", len(records), records)
+		}
+		if records[0]["msg"] != "openrouter response" || records[1]["msg"] != "openrouter completion" {
+			t.Errorf("Expected response and completion records, got %v", records)
+		}
+		if records[1]["model"] != "test-model" {
+			t.Errorf("Expected model 'test-model', got %v", records[1]["model"])
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte(
+				"data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n" +
+					"data: {\"id\":\"chatcmpl-1\",\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":1,\"total_tokens\":4}}\n\n" +
+					"data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithLogger(logger),
+			gopenrouter.WithLogLevels(gopenrouter.LogLevels{Request: slog.LevelDebug, Error: slog.LevelError}),
+		)
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer stream.Close()
+
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		records := parseLogRecords(t, &buf)
+		if len(records) != 2 {
+			t.Fatalf("Expected 2 log records, got %d: %v", len(records), records)
+		}
+		finished := records[1]
+		if finished["msg"] != "openrouter stream finished" || finished["level"] != "INFO" {
+			t.Errorf("Expected stream finished record at INFO, got %v", finished)
+		}
+		if finished["chunks"] != float64(2) || finished["completion_tokens"] != float64(1) {
+			t.Errorf("Unexpected stream finished record: %v", finished)
+		}
+		if _, ok := finished["error"]; ok {
+			t.Errorf("Expected no error in stream finished record, got %v", finished["error"])
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package gopenrouter
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Metrics receives measurements of the requests sent by the client, so they can be
+// exported to a monitoring system. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest is called after each attempt of an HTTP request
+	ObserveRequest(ctx context.Context, m RequestMetrics)
+	// ObserveUsage is called with the token usage reported by completions and streams
+	ObserveUsage(ctx context.Context, m UsageMetrics)
+}
+
+// StreamMetricsObserver is implemented by Metrics receivers that also record the
+// latency of streams, such as the time to first token. It is a separate interface so
+// that existing receivers keep satisfying Metrics.
+type StreamMetricsObserver interface {
+	// ObserveStream is called once the consumer of a stream has read it to its end, or
+	// has closed it before
+	ObserveStream(ctx context.Context, info StreamEndInfo)
+}
+
+// RequestMetrics describes a completed attempt of an HTTP request.
+type RequestMetrics struct {
+	// Method is the HTTP method of the request
+	Method string
+	// Endpoint is the URL path of the request relative to the base URL, such as "/chat/completions"
+	Endpoint string
+	// StatusCode is the HTTP status code of the response, or zero if no response was received
+	StatusCode int
+	// Latency is the time between sending the request and receiving the response headers
+	Latency time.Duration
+	// Err is the error of the attempt, if any, including errors for non-2xx statuses
+	Err error
+}
+
+// UsageMetrics describes the token usage of a completion or stream.
+type UsageMetrics struct {
+	// Model is the model that generated the completion
+	Model string
+	// Provider is the provider that served the request, if reported
+	Provider string
+	// PromptTokens is the number of tokens in the prompt
+	PromptTokens int
+	// CompletionTokens is the number of tokens in the generated completion
+	CompletionTokens int
+	// Cost is the cost of the request in credits, if reported
+	Cost float64
+}
+
+// WithMetrics sets a receiver of request and token usage measurements.
+// See the promgopenrouter module for a ready-made Prometheus adapter.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// observeRequest reports a completed attempt of req to the metrics receiver, if set.
+func (c *Client) observeRequest(req *http.Request, latency time.Duration, resp *http.Response, err error) {
+	if c.metrics == nil {
+		return
+	}
+
+	status, _ := responseStatus(resp, err)
+	c.metrics.ObserveRequest(req.Context(), RequestMetrics{
+		Method:     req.Method,
+		Endpoint:   c.endpoint(req.URL),
+		StatusCode: status,
+		Latency:    latency,
+		Err:        err,
+	})
+}
+
+// observeUsage reports the token usage of the completion with the given generation ID
+// to the logger, and to the budget, the usage tracker, and the metrics receiver, if set.
+func (c *Client) observeUsage(ctx context.Context, id, model, provider string, usage Usage) {
+	c.log.completion(ctx, model, provider, usage)
+	if usage == (Usage{}) {
+		// The response reported no usage
+		c.chargeBudget(ctx, id, nil)
+	} else {
+		c.chargeBudget(ctx, id, &usage)
+	}
+	if c.usageTracker != nil {
+		c.trackUsage(ctx, model, provider, usage)
+	}
+	if c.metrics != nil {
+		c.metrics.ObserveUsage(ctx, newUsageMetrics(model, provider, usage))
+	}
+}
+
+// observeStreamUsage reports the token usage of the stream with the given generation ID
+// to the budget, the usage tracker, and the metrics receiver, if set. It is called once
+// per stream, with a nil usage if the stream reported none.
+func (c *Client) observeStreamUsage(ctx context.Context, id, model, provider string, usage *Usage) {
+	c.chargeBudget(ctx, id, usage)
+	if usage == nil {
+		return
+	}
+	if c.usageTracker != nil {
+		c.trackUsage(ctx, model, provider, *usage)
+	}
+	if c.metrics != nil {
+		c.metrics.ObserveUsage(ctx, newUsageMetrics(model, provider, *usage))
+	}
+}
+
+// observesStreamEnd reports whether the end of streams is reported to the logger, the
+// hooks, or the metrics receiver.
+func (c *Client) observesStreamEnd() bool {
+	_, observer := c.metrics.(StreamMetricsObserver)
+	return c.log.logger != nil || c.hooks.OnStreamEnd != nil || observer
+}
+
+// observeStreamEnd reports a stream that has been read to its end to the logger, the
+// hooks, and the metrics receiver, if set.
+func (c *Client) observeStreamEnd(ctx context.Context, info StreamEndInfo) {
+	if c.log.logger != nil {
+		c.log.streamEnd(ctx, info)
+	}
+	if c.hooks.OnStreamEnd != nil {
+		c.hooks.OnStreamEnd(ctx, info)
+	}
+	if observer, ok := c.metrics.(StreamMetricsObserver); ok {
+		observer.ObserveStream(ctx, info)
+	}
+}
+
+// newUsageMetrics describes the token usage of a completion generated by model.
+func newUsageMetrics(model, provider string, usage Usage) UsageMetrics {
+	return UsageMetrics{
+		Model:            model,
+		Provider:         provider,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		Cost:             usage.Cost,
+	}
+}
+
+// endpoint returns the path of u relative to the path of the client's base URL.
+func (c *Client) endpoint(u *url.URL) string {
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return u.Path
+	}
+	return "/" + strings.TrimPrefix(strings.TrimPrefix(u.Path, strings.TrimSuffix(base.Path, "/")), "/")
+}
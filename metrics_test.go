@@ -0,0 +1,142 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+// recordingMetrics records the measurements it receives.
+type recordingMetrics struct {
+	mu       sync.Mutex
+	requests []gopenrouter.RequestMetrics
+	usage    []gopenrouter.UsageMetrics
+	streams  []gopenrouter.StreamEndInfo
+}
+
+func (m *recordingMetrics) ObserveRequest(ctx context.Context, r gopenrouter.RequestMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, r)
+}
+
+func (m *recordingMetrics) ObserveUsage(ctx context.Context, u gopenrouter.UsageMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage = append(m.usage, u)
+}
+
+func (m *recordingMetrics) ObserveStream(ctx context.Context, info gopenrouter.StreamEndInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.streams = append(m.streams, info)
+}
+
+func TestMetrics(t *testing.T) {
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	t.Run("Completion", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id":"cmpl-1","provider":"OpenAI","model":"openai/gpt-4o","choices":[{"text":"Hi","index":0}],"usage":{"prompt_tokens":3,"completion_tokens":5,"total_tokens":8,"cost":0.0015}}`))
+		}))
+		defer server.Close()
+
+		metrics := &recordingMetrics{}
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL+"/api/v1"), gopenrouter.WithMetrics(metrics))
+		request := gopenrouter.NewCompletionRequestBuilder("openai/gpt-4o", "Hello").Build()
+
+		if _, err := client.Completion(context.Background(), *request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(metrics.requests) != 1 {
+			t.Fatalf("Expected 1 request measurement, got %d", len(metrics.requests))
+		}
+		r := metrics.requests[0]
+		if r.Method != http.MethodPost || r.Endpoint != "/completions" || r.StatusCode != http.StatusOK || r.Err != nil || r.Latency <= 0 {
+			t.Errorf("Unexpected request measurement: %+v", r)
+		}
+
+		expected := gopenrouter.UsageMetrics{Model: "openai/gpt-4o", Provider: "OpenAI", PromptTokens: 3, CompletionTokens: 5, Cost: 0.0015}
+		if len(metrics.usage) != 1 || metrics.usage[0] != expected {
+			t.Errorf("Expected usage %+v, got %+v", expected, metrics.usage)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"error":{"code":402,"message":"insufficient credits"}}`))
+		}))
+		defer server.Close()
+
+		metrics := &recordingMetrics{}
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithMetrics(metrics))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		if _, err := client.ChatCompletion(context.Background(), *request); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		if len(metrics.requests) != 1 {
+			t.Fatalf("Expected 1 request measurement, got %d", len(metrics.requests))
+		}
+		r := metrics.requests[0]
+		if r.Endpoint != "/chat/completions" || r.StatusCode != http.StatusPaymentRequired || !errors.Is(r.Err, gopenrouter.ErrInsufficientCredits) {
+			t.Errorf("Unexpected request measurement: %+v", r)
+		}
+		if len(metrics.usage) != 0 {
+			t.Errorf("Expected no usage measurements, got %+v", metrics.usage)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		usage := gopenrouter.Usage{PromptTokens: 3, CompletionTokens: 1, TotalTokens: 4, Cost: 0.0004}
+		server := httptest.NewServer(gopenroutertest.NewStream().
+			Chunk(gopenroutertest.ChatChunk("chatcmpl-1", "openai/gpt-4o", "Hi")).
+			Chunk(gopenroutertest.ChatUsageChunk("chatcmpl-1", "openai/gpt-4o", usage)).
+			Done())
+		defer server.Close()
+
+		metrics := &recordingMetrics{}
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithMetrics(metrics))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer stream.Close()
+
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		expected := gopenrouter.UsageMetrics{Model: "openai/gpt-4o", PromptTokens: 3, CompletionTokens: 1, Cost: 0.0004}
+		if len(metrics.usage) != 1 || metrics.usage[0] != expected {
+			t.Errorf("Expected usage %+v, got %+v", expected, metrics.usage)
+		}
+
+		if len(metrics.streams) != 1 {
+			t.Fatalf("Expected 1 stream observation, got %d", len(metrics.streams))
+		}
+		observed := metrics.streams[0]
+		if observed.Model != "openai/gpt-4o" || observed.Err != nil || observed.Metrics.Chunks != 2 {
+			t.Errorf("Expected a clean stream of 2 chunks from openai/gpt-4o, got %+v", observed)
+		}
+		if observed.Metrics.TimeToFirstToken <= 0 {
+			t.Errorf("Expected a time to first token, got %v", observed.Metrics.TimeToFirstToken)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package gopenrouter
+
+import "net/http"
+
+// RoundTripFunc sends an HTTP request and returns its response. It is the unit
+// wrapped by middleware.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTripFunc with additional behavior, such as injecting
+// headers, serving cached responses, injecting faults, or recording telemetry.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware adds middleware executed around every HTTP request sent by the client,
+// including streaming requests and each retry attempt. Middleware run in the order they
+// are added, the first being the outermost. Responses returned by middleware are
+// processed like responses from the API, so non-2xx statuses are converted to errors.
+//
+// Example usage:
+//
+//	logging := func(next gopenrouter.RoundTripFunc) gopenrouter.RoundTripFunc {
+//	  return func(req *http.Request) (*http.Response, error) {
+//	    start := time.Now()
+//	    resp, err := next(req)
+//	    log.Printf("%s %s took %v", req.Method, req.URL, time.Since(start))
+//	    return resp, err
+//	  }
+//	}
+//	client := gopenrouter.New("your-api-key", gopenrouter.WithMiddleware(logging))
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithCallMiddleware adds middleware executed once around every API call sent by the
+// client, including streaming requests. Unlike WithMiddleware, call middleware wrap all
+// attempts of a retried request: they receive the request before its first attempt, and
+// the response of the successful attempt or the error of the last one. Responses with a
+// non-2xx status code are reported as errors, such as *APIError. Call middleware run in
+// the order they are added, the first being the outermost.
+//
+// Call middleware suit telemetry describing the API call as a whole, such as a span
+// parenting the spans of its attempts.
+func WithCallMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.callMiddleware = append(c.callMiddleware, middleware...)
+	}
+}
+
+// buildRoundTrip chains the middleware around the HTTP client, and the call middleware
+// around the retry loop. The debug output is
+// produced innermost, so it shows requests as they are sent over the wire and
+// responses once they have been decompressed.
+func (c *Client) buildRoundTrip() {
+	c.roundTrip = func(req *http.Request) (*http.Response, error) {
+		c.debug.dumpRequest(req)
+		resp, err := c.httpClient.Do(req)
+		if err == nil {
+			err = decompressBody(resp)
+		}
+		if err != nil {
+			c.debug.dumpError(req, err)
+			return nil, err
+		}
+		c.debug.dumpResponse(req, resp)
+		return resp, nil
+	}
+
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		c.roundTrip = c.middleware[i](c.roundTrip)
+	}
+
+	c.call = c.doWithRetries
+	for i := len(c.callMiddleware) - 1; i >= 0; i-- {
+		c.call = c.callMiddleware[i](c.call)
+	}
+}
@@ -0,0 +1,199 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestMiddleware(t *testing.T) {
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	t.Run("Order", func(t *testing.T) {
+		var header string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = strings.Join(r.Header.Values("X-Trace"), ",")
+			_, _ = w.Write([]byte(`{"data":{"total_credits":10,"total_usage":1}}`))
+		}))
+		defer server.Close()
+
+		var calls []string
+		trace := func(name string) gopenrouter.Middleware {
+			return func(next gopenrouter.RoundTripFunc) gopenrouter.RoundTripFunc {
+				return func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, name)
+					req.Header.Add("X-Trace", name)
+					return next(req)
+				}
+			}
+		}
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithMiddleware(trace("first"), trace("second")),
+			gopenrouter.WithMiddleware(trace("third")),
+			gopenrouter.WithBaseURL(server.URL),
+		)
+		if _, err := client.GetCredits(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if strings.Join(calls, ",") != "first,second,third" {
+			t.Errorf("Expected middleware order first,second,third, got %v", calls)
+		}
+		if header != "first,second,third" {
+			t.Errorf("Expected X-Trace 'first,second,third', got '%s'", header)
+		}
+	})
+
+	t.Run("ShortCircuit", func(t *testing.T) {
+		cached := func(next gopenrouter.RoundTripFunc) gopenrouter.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"id":"cached","choices":[{"index":0,"message":{"role":"assistant","content":"Cached"}}]}`)),
+					Request:    req,
+				}, nil
+			}
+		}
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL("http://127.0.0.1:0"),
+			gopenrouter.WithMiddleware(cached),
+		)
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		response, err := client.ChatCompletion(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.ID != "cached" {
+			t.Errorf("Expected cached response, got ID '%s'", response.ID)
+		}
+	})
+
+	t.Run("ErrorStatus", func(t *testing.T) {
+		chaos := func(next gopenrouter.RoundTripFunc) gopenrouter.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Status:     "503 Service Unavailable",
+					Header:     make(http.Header),
+					Body:       io.NopCloser(strings.NewReader(`{"error":{"code":503,"message":"injected"}}`)),
+					Request:    req,
+				}, nil
+			}
+		}
+
+		client := gopenrouter.New("test-key", gopenrouter.WithMiddleware(chaos))
+		_, err := client.GetCredits(context.Background())
+		if !errors.Is(err, gopenrouter.ErrProviderUnavailable) {
+			t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+		}
+	})
+
+	t.Run("StreamAndRetries", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\ndata: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		var attempts atomic.Int32
+		counting := func(next gopenrouter.RoundTripFunc) gopenrouter.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				attempts.Add(1)
+				return next(req)
+			}
+		}
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithMiddleware(counting),
+			gopenrouter.WithRetry(2, gopenrouter.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+		)
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer stream.Close()
+
+		chunk, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if chunk.ID != "chatcmpl-1" {
+			t.Errorf("Expected chunk ID 'chatcmpl-1', got '%s'", chunk.ID)
+		}
+		if got := attempts.Load(); got != 2 {
+			t.Errorf("Expected middleware to see 2 attempts, got %d", got)
+		}
+	})
+
+	t.Run("CallMiddleware", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`))
+		}))
+		defer server.Close()
+
+		var order []string
+		var calls, attempts atomic.Int32
+		call := func(next gopenrouter.RoundTripFunc) gopenrouter.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls.Add(1)
+				order = append(order, "call")
+				resp, err := next(req)
+				if err != nil {
+					t.Errorf("Expected the call to succeed after retrying, got %v", err)
+				}
+				return resp, err
+			}
+		}
+		attempt := func(next gopenrouter.RoundTripFunc) gopenrouter.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				attempts.Add(1)
+				order = append(order, "attempt")
+				return next(req)
+			}
+		}
+
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithMiddleware(attempt),
+			gopenrouter.WithCallMiddleware(call),
+			gopenrouter.WithRetry(2, gopenrouter.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+		)
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		if _, err := client.ChatCompletion(context.Background(), *request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if calls.Load() != 1 || attempts.Load() != 2 {
+			t.Errorf("Expected 1 call and 2 attempts, got %d and %d", calls.Load(), attempts.Load())
+		}
+		if strings.Join(order, ",") != "call,attempt,attempt" {
+			t.Errorf("Expected call middleware to wrap the attempts, got %v", order)
+		}
+	})
+}
@@ -2,7 +2,12 @@ package gopenrouter
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 )
 
 // modelsResponse represents the internal API response structure when listing models.
@@ -11,11 +16,18 @@ type modelsResponse struct {
 	Data []ModelData `json:"data"`
 }
 
+// modelResponse represents the internal API response structure when retrieving a single model.
+type modelResponse struct {
+	Data ModelData `json:"data"`
+}
+
 // ModelData represents information about an AI model available through OpenRouter.
 // It contains details about the model's capabilities, pricing, and technical specifications.
 type ModelData struct {
 	// ID is the unique identifier for the model
 	ID string `json:"id"`
+	// CanonicalSlug is the permanent identifier of the model version the ID currently refers to
+	CanonicalSlug string `json:"canonical_slug,omitempty"`
 	// Name is the human-readable name of the model
 	Name string `json:"name"`
 	// Created is the Unix timestamp when the model was added to OpenRouter
@@ -33,10 +45,63 @@ type ModelData struct {
 	// HuggingFaceID is the identifier for the model on Hugging Face (if available)
 	HuggingFaceID *string `json:"hugging_face_id,omitempty"`
 	// PerRequestLimits contains any limitations on requests to this model
-	PerRequestLimits map[string]any `json:"per_request_limits,omitempty"`
+	PerRequestLimits *PerRequestLimits `json:"per_request_limits,omitempty"`
 	// SupportedParameters lists all parameters that can be used with this model
 	// Note: This is a union of parameters from all providers; no single provider may support all parameters
 	SupportedParameters []string `json:"supported_parameters,omitempty"`
+	// DefaultParameters contains the sampling parameters applied when a request leaves them unset
+	DefaultParameters *ModelDefaultParameters `json:"default_parameters,omitempty"`
+}
+
+// PerRequestLimits contains the token limits applied to each request to a model.
+type PerRequestLimits struct {
+	// PromptTokens is the maximum number of prompt tokens per request, if limited
+	PromptTokens *float64 `json:"prompt_tokens,omitempty"`
+	// CompletionTokens is the maximum number of completion tokens per request, if limited
+	CompletionTokens *float64 `json:"completion_tokens,omitempty"`
+}
+
+// UnmarshalJSON decodes the limits, which OpenRouter may send as numbers or numeric strings.
+func (l *PerRequestLimits) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		PromptTokens     json.Number `json:"prompt_tokens"`
+		CompletionTokens json.Number `json:"completion_tokens"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var err error
+	if l.PromptTokens, err = parseLimit(raw.PromptTokens); err != nil {
+		return fmt.Errorf("invalid prompt_tokens limit: %w", err)
+	}
+	if l.CompletionTokens, err = parseLimit(raw.CompletionTokens); err != nil {
+		return fmt.Errorf("invalid completion_tokens limit: %w", err)
+	}
+	return nil
+}
+
+// parseLimit returns the value of a numeric limit, or nil if it is absent.
+func parseLimit(n json.Number) (*float64, error) {
+	if n == "" {
+		return nil, nil
+	}
+	v, err := n.Float64()
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ModelDefaultParameters contains the sampling parameters applied to requests to a model
+// that leave them unset. Parameters without a model-specific default are nil.
+type ModelDefaultParameters struct {
+	// Temperature is the default sampling temperature
+	Temperature *float64 `json:"temperature,omitempty"`
+	// TopP is the default nucleus sampling probability
+	TopP *float64 `json:"top_p,omitempty"`
+	// FrequencyPenalty is the default frequency penalty
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
 }
 
 // ModelArchitecture contains information about the model's input and output capabilities.
@@ -84,6 +149,15 @@ type ModelPricing struct {
 	InternalReasoning string `json:"internal_reasoning"`
 }
 
+// ListModelsOptions filters the models returned by ListModelsWithOptions.
+type ListModelsOptions struct {
+	// Category limits the models to those suited for a use case (e.g., "programming", "roleplay")
+	Category string
+	// SupportedParameters limits the models to those supporting all of the given request
+	// parameters (e.g., "tools", "response_format", "logprobs")
+	SupportedParameters []string
+}
+
 // ListModels retrieves information about all models available through the OpenRouter API.
 //
 // The returned list includes details about each model's capabilities, pricing,
@@ -97,8 +171,172 @@ type ModelPricing struct {
 //   - []ModelData: A list of available models with their details
 //   - error: Any error that occurred during the request
 func (c *Client) ListModels(ctx context.Context) (models []ModelData, err error) {
-	var response modelsResponse
+	return c.ListModelsWithOptions(ctx, ListModelsOptions{})
+}
+
+// ListModelsWithOptions retrieves information about the models available through the
+// OpenRouter API that match the given filters.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - options: Filters applied to the returned models; the zero value returns all models
+//
+// Returns:
+//   - []ModelData: A list of matching models with their details
+//   - error: Any error that occurred during the request
+func (c *Client) ListModelsWithOptions(ctx context.Context, options ListModelsOptions) (models []ModelData, err error) {
 	urlSuffix := "/models"
+	supportedParameters := strings.Join(options.SupportedParameters, ",")
+
+	var setters []requestOption
+	if options.Category != "" {
+		setters = append(setters, withQueryParam("category", options.Category))
+	}
+	if supportedParameters != "" {
+		setters = append(setters, withQueryParam("supported_parameters", supportedParameters))
+	}
+
+	// Concurrent identical requests, such as those of goroutines starting up together,
+	// share a single response
+	ctx, key, err := c.flightKey(ctx, options.Category+"\x00"+supportedParameters)
+	if err != nil {
+		return
+	}
+	response, err := c.modelsFlight.do(ctx, key, func(ctx context.Context) (response modelsResponse, err error) {
+		req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), setters...)
+		if err != nil {
+			return
+		}
+		err = c.sendRequest(req, &response)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	// Copy the shared list and its nested data, so that callers can modify their own
+	models = deepClone(response.Data)
+	if len(options.SupportedParameters) > 0 {
+		// Filter locally as well, in case the API ignored the filter
+		models = slices.DeleteFunc(models, func(model ModelData) bool {
+			return !model.SupportsParameters(options.SupportedParameters...)
+		})
+	}
+	return
+}
+
+// ListModelsFunc retrieves information about all models available through the OpenRouter
+// API like ListModels, but decodes the list incrementally and calls fn with each model
+// instead of returning them all at once. As the list spans several megabytes, this reduces
+// the peak memory of processing it, for example when only a few models are kept.
+//
+// Iteration stops early, without error, once fn returns false.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - fn: The function called with each model, returning whether to continue
+//
+// Returns:
+//   - error: Any error that occurred during the request or while decoding the list
+func (c *Client) ListModelsFunc(ctx context.Context, fn func(model ModelData) bool) (err error) {
+	urlSuffix := "/models"
+
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	if err != nil {
+		return
+	}
+
+	return c.sendRequestFunc(req, func(_ http.Header, decoder *json.Decoder) error {
+		return decodeModels(decoder, fn)
+	})
+}
+
+// decodeModels decodes the data array of a models response one model at a time, calling
+// fn with each until it returns false. Other fields of the response are skipped.
+func decodeModels(decoder *json.Decoder, fn func(ModelData) bool) error {
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if key != "data" {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return err
+			}
+			continue
+		}
+
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			// A null list has no models, as when decoded by ListModels
+			continue
+		}
+		if token != json.Delim('[') {
+			return fmt.Errorf("unexpected %v, expected [", token)
+		}
+		for decoder.More() {
+			var model ModelData
+			if err := decoder.Decode(&model); err != nil {
+				return err
+			}
+			if !fn(model) {
+				return nil
+			}
+		}
+		if err := expectDelim(decoder, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(decoder, '}')
+}
+
+// expectDelim reads the next token of decoder, returning an error unless it is delim.
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected %v, expected %v", token, delim)
+	}
+	return nil
+}
+
+// SupportsParameters reports whether the model supports all of the given request parameters.
+// Support is determined from the union of the parameters of all providers of the model,
+// so a request may still need to be routed to a provider supporting them, for example with
+// ProviderOptions.RequireParameters.
+func (m ModelData) SupportsParameters(params ...string) bool {
+	for _, param := range params {
+		if !slices.Contains(m.SupportedParameters, param) {
+			return false
+		}
+	}
+	return true
+}
+
+// ListUserModels retrieves the models available to the authenticated user.
+//
+// Unlike ListModels, the returned list honors the provider preferences and ignore lists
+// configured in the account settings, so it only includes models that requests made
+// with the API key can actually be routed to.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//
+// Returns:
+//   - []ModelData: A list of models available to the user with their details
+//   - error: Any error that occurred during the request
+func (c *Client) ListUserModels(ctx context.Context) (models []ModelData, err error) {
+	var response modelsResponse
+	urlSuffix := "/models/user"
 
 	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
 	if err != nil {
@@ -113,3 +351,31 @@ func (c *Client) ListModels(ctx context.Context) (models []ModelData, err error)
 	models = response.Data
 	return
 }
+
+// GetModel retrieves information about a single model, without listing all models.
+//
+// Parameters:
+//   - ctx: The context for the request, which can be used for cancellation and timeouts
+//   - author: The author/owner of the model
+//   - slug: The model identifier/slug
+//
+// Returns:
+//   - ModelData: The model's capabilities, pricing, and technical specifications
+//   - error: Any error that occurred during the request
+func (c *Client) GetModel(ctx context.Context, author string, slug string) (model ModelData, err error) {
+	urlSuffix := fmt.Sprintf("/models/%s/%s", url.PathEscape(author), url.PathEscape(slug))
+	var response modelResponse
+
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	if err != nil {
+		return
+	}
+
+	model = response.Data
+	return
+}
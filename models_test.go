@@ -2,8 +2,10 @@ package gopenrouter_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -88,3 +90,345 @@ func TestClient_ListModels(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ListModelsWithOptions(t *testing.T) {
+	cases := []struct {
+		name        string
+		options     gopenrouter.ListModelsOptions
+		expectQuery string
+	}{
+		{
+			name:        "NoFilters",
+			options:     gopenrouter.ListModelsOptions{},
+			expectQuery: "",
+		},
+		{
+			name:        "Category",
+			options:     gopenrouter.ListModelsOptions{Category: "programming"},
+			expectQuery: "category=programming",
+		},
+		{
+			name:        "SupportedParameters",
+			options:     gopenrouter.ListModelsOptions{SupportedParameters: []string{"tools", "response_format"}},
+			expectQuery: "supported_parameters=tools%2Cresponse_format",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var query string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/models" {
+					t.Errorf("unexpected path: got %s, want /models", r.URL.Path)
+				}
+				query = r.URL.RawQuery
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprint(w, `{"data":[{"id":"openai/gpt-4o","supported_parameters":["tools","response_format"]}]}`)
+			}))
+			defer ts.Close()
+
+			client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL))
+			data, err := client.ListModelsWithOptions(context.Background(), tc.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query != tc.expectQuery {
+				t.Errorf("unexpected query: got %q, want %q", query, tc.expectQuery)
+			}
+			if len(data) != 1 || data[0].ID != "openai/gpt-4o" {
+				t.Errorf("unexpected models: got %+v", data)
+			}
+		})
+	}
+}
+
+func TestClient_ListModelsWithOptions_FiltersLocally(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Ignore the filter, as older API versions do
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"data":[
+			{"id":"tools-only","supported_parameters":["tools"]},
+			{"id":"tools-and-logprobs","supported_parameters":["tools","logprobs","temperature"]},
+			{"id":"unknown"}
+		]}`)
+	}))
+	defer ts.Close()
+
+	client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL))
+	data, err := client.ListModelsWithOptions(context.Background(), gopenrouter.ListModelsOptions{
+		SupportedParameters: []string{"tools", "logprobs"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) != 1 || data[0].ID != "tools-and-logprobs" {
+		t.Errorf("unexpected models: got %+v, want only tools-and-logprobs", data)
+	}
+}
+
+func TestModelData_SupportsParameters(t *testing.T) {
+	model := gopenrouter.ModelData{SupportedParameters: []string{"tools", "tool_choice", "response_format"}}
+
+	cases := []struct {
+		name   string
+		params []string
+		expect bool
+	}{
+		{name: "None", params: nil, expect: true},
+		{name: "Single", params: []string{"tools"}, expect: true},
+		{name: "All", params: []string{"tools", "response_format"}, expect: true},
+		{name: "Missing", params: []string{"tools", "logprobs"}, expect: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := model.SupportsParameters(tc.params...); got != tc.expect {
+				t.Errorf("unexpected result for %v: got %t, want %t", tc.params, got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestClient_ListUserModels(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.URL.Path != "/models/user" {
+				t.Errorf("unexpected request: got %s %s, want GET /models/user", r.Method, r.URL.Path)
+			}
+			if auth := r.Header.Get("Authorization"); auth != "Bearer test-key" {
+				t.Errorf("unexpected authorization: got %q", auth)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"data":[{"id":"anthropic/claude-3.5-sonnet"},{"id":"openai/gpt-4o"}]}`)
+		}))
+		defer ts.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL))
+		data, err := client.ListUserModels(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(data) != 2 || data[0].ID != "anthropic/claude-3.5-sonnet" {
+			t.Errorf("unexpected models: got %+v", data)
+		}
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 401, "message": "No auth credentials found"}}`)
+		}))
+		defer ts.Close()
+
+		client := gopenrouter.New("", gopenrouter.WithBaseURL(ts.URL))
+		_, err := client.ListUserModels(context.Background())
+
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("expected APIError, got %T: %v", err, err)
+		}
+	})
+}
+
+func TestClient_GetModel(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.URL.Path != "/models/openai/gpt-4o:extended" {
+				t.Errorf("unexpected request: got %s %s", r.Method, r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"data":{"id":"openai/gpt-4o:extended","name":"GPT-4o (extended)","description":"GPT-4o with extended output","architecture":{"modality":"text+image->text","tokenizer":"GPT"},"top_provider":{"is_moderated":true,"context_length":128000,"max_completion_tokens":64000},"pricing":{"prompt":"0.000006","completion":"0.000018"},"context_length":128000}}`)
+		}))
+		defer ts.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL))
+		model, err := client.GetModel(context.Background(), "openai", "gpt-4o:extended")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if model.ID != "openai/gpt-4o:extended" {
+			t.Errorf("unexpected model ID: got %s", model.ID)
+		}
+		if model.Architecture.Modality != "text+image->text" {
+			t.Errorf("unexpected modality: got %s", model.Architecture.Modality)
+		}
+		if model.Pricing.Completion != "0.000018" {
+			t.Errorf("unexpected completion price: got %s", model.Pricing.Completion)
+		}
+		if model.TopProvider.MaxCompletionTokens == nil || *model.TopProvider.MaxCompletionTokens != 64000 {
+			t.Errorf("unexpected max completion tokens: got %v", model.TopProvider.MaxCompletionTokens)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 404, "message": "Model not found"}}`)
+		}))
+		defer ts.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL))
+		_, err := client.GetModel(context.Background(), "unknown", "model")
+
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("expected APIError, got %T: %v", err, err)
+		}
+	})
+}
+
+func TestModelData_Decode(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"data":[
+			{"id":"openai/gpt-4o","canonical_slug":"openai/gpt-4o-2024-08-06","hugging_face_id":"","per_request_limits":{"prompt_tokens":"120000","completion_tokens":16384},"default_parameters":{"temperature":0.7,"top_p":null,"frequency_penalty":0}},
+			{"id":"meta-llama/llama-3-8b","hugging_face_id":"meta-llama/Meta-Llama-3-8B","per_request_limits":null}
+		]}`)
+	}))
+	defer ts.Close()
+
+	client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL))
+	data, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	model := data[0]
+	if model.CanonicalSlug != "openai/gpt-4o-2024-08-06" {
+		t.Errorf("unexpected canonical slug: got %q", model.CanonicalSlug)
+	}
+	if model.PerRequestLimits == nil {
+		t.Fatal("expected per request limits, got nil")
+	}
+	if model.PerRequestLimits.PromptTokens == nil || *model.PerRequestLimits.PromptTokens != 120000 {
+		t.Errorf("unexpected prompt token limit: got %v", model.PerRequestLimits.PromptTokens)
+	}
+	if model.PerRequestLimits.CompletionTokens == nil || *model.PerRequestLimits.CompletionTokens != 16384 {
+		t.Errorf("unexpected completion token limit: got %v", model.PerRequestLimits.CompletionTokens)
+	}
+	if model.DefaultParameters == nil {
+		t.Fatal("expected default parameters, got nil")
+	}
+	if model.DefaultParameters.Temperature == nil || *model.DefaultParameters.Temperature != 0.7 {
+		t.Errorf("unexpected default temperature: got %v", model.DefaultParameters.Temperature)
+	}
+	if model.DefaultParameters.TopP != nil {
+		t.Errorf("unexpected default top_p: got %v", *model.DefaultParameters.TopP)
+	}
+	if model.DefaultParameters.FrequencyPenalty == nil || *model.DefaultParameters.FrequencyPenalty != 0 {
+		t.Errorf("unexpected default frequency penalty: got %v", model.DefaultParameters.FrequencyPenalty)
+	}
+
+	model = data[1]
+	if model.HuggingFaceID == nil || *model.HuggingFaceID != "meta-llama/Meta-Llama-3-8B" {
+		t.Errorf("unexpected Hugging Face ID: got %v", model.HuggingFaceID)
+	}
+	if model.PerRequestLimits != nil || model.DefaultParameters != nil {
+		t.Errorf("expected no limits or default parameters, got %+v and %+v", model.PerRequestLimits, model.DefaultParameters)
+	}
+
+	t.Run("InvalidLimit", func(t *testing.T) {
+		var limits gopenrouter.PerRequestLimits
+		if err := json.Unmarshal([]byte(`{"prompt_tokens":"unlimited"}`), &limits); err == nil {
+			t.Error("expected error for non-numeric limit, got nil")
+		}
+	})
+}
+
+func TestClient_ListModelsFunc(t *testing.T) {
+	newServer := func(body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/models" {
+				t.Errorf("Expected path /models, got %s", r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body))
+		}))
+	}
+	models := `{"object":"list","meta":{"count":3},"data":[{"id":"a/one","name":"One"},{"id":"b/two","name":"Two"},{"id":"c/three","name":"Three"}]}`
+
+	t.Run("AllModels", func(t *testing.T) {
+		server := newServer(models)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		var ids []string
+		err := client.ListModelsFunc(context.Background(), func(model gopenrouter.ModelData) bool {
+			ids = append(ids, model.ID)
+			return true
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fmt.Sprint(ids) != "[a/one b/two c/three]" {
+			t.Errorf("Expected all models in order, got %v", ids)
+		}
+	})
+
+	t.Run("StopsEarly", func(t *testing.T) {
+		server := newServer(models)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		var calls int
+		err := client.ListModelsFunc(context.Background(), func(model gopenrouter.ModelData) bool {
+			calls++
+			return model.ID != "b/two"
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("Expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("NullData", func(t *testing.T) {
+		server := newServer(`{"data":null}`)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		err := client.ListModelsFunc(context.Background(), func(gopenrouter.ModelData) bool {
+			t.Error("Expected no models")
+			return true
+		})
+		if err != nil {
+			t.Errorf("Expected a null list to have no models, got %v", err)
+		}
+	})
+
+	t.Run("TruncatedBody", func(t *testing.T) {
+		server := newServer(`{"data":[{"id":"a/one"},{"id":"b/tw`)
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		var calls int
+		err := client.ListModelsFunc(context.Background(), func(gopenrouter.ModelData) bool {
+			calls++
+			return true
+		})
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("Expected the complete model to be delivered, got %d calls", calls)
+		}
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error":{"code":500,"message":"Internal error"}}`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL))
+		err := client.ListModelsFunc(context.Background(), func(gopenrouter.ModelData) bool { return true })
+		var apiErr *gopenrouter.APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != http.StatusInternalServerError {
+			t.Errorf("Expected 500 APIError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+module github.com/bkovacki/gopenrouter/otelgopenrouter
+
+go 1.24.3
+
+require (
+	github.com/bkovacki/gopenrouter v0.5.0
+	go.opentelemetry.io/otel v1.38.0
+	go.opentelemetry.io/otel/sdk v1.38.0
+	go.opentelemetry.io/otel/trace v1.38.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	go.opentelemetry.io/otel/metric v1.38.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+)
+
+// The APIs used by this module are first released in gopenrouter v0.5.0, which must be
+// tagged before this module. The replace directive builds the module against the
+// gopenrouter source of this repository during development, and is ignored by importers.
+replace github.com/bkovacki/gopenrouter => ../
@@ -0,0 +1,500 @@
+// Package otelgopenrouter provides OpenTelemetry tracing for the gopenrouter client.
+//
+// Tracing is enabled by passing the option returned by WithTracing to the client:
+//
+//	client := gopenrouter.New("your-api-key",
+//	  otelgopenrouter.WithTracing(),
+//	)
+//
+// A span is created for every API call sent by the client, parenting a span for each
+// HTTP attempt made while retrying it. Call spans carry the requested and responding
+// model, the provider, token usage, cost, and finish reasons, and end once the response
+// body has been read or closed, so streaming spans cover the whole stream. The trace
+// context of the attempt is propagated in the request headers.
+//
+// This module requires a version of gopenrouter providing WithCallMiddleware, released
+// as v0.5.0.
+package otelgopenrouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this package as the instrumentation library of its spans.
+const instrumentationName = "github.com/bkovacki/gopenrouter/otelgopenrouter"
+
+// maxSummarySize is the size of the largest completion response recorded on its span.
+// Larger responses are passed through without being summarized.
+const maxSummarySize = 1 << 20
+
+// Attribute keys set on spans, following the OpenTelemetry semantic conventions
+// for generative AI where one exists.
+const (
+	attrSystem          = attribute.Key("gen_ai.system")
+	attrOperation       = attribute.Key("gen_ai.operation.name")
+	attrRequestModel    = attribute.Key("gen_ai.request.model")
+	attrResponseModel   = attribute.Key("gen_ai.response.model")
+	attrResponseID      = attribute.Key("gen_ai.response.id")
+	attrFinishReasons   = attribute.Key("gen_ai.response.finish_reasons")
+	attrInputTokens     = attribute.Key("gen_ai.usage.input_tokens")
+	attrOutputTokens    = attribute.Key("gen_ai.usage.output_tokens")
+	attrProvider        = attribute.Key("openrouter.provider")
+	attrCost            = attribute.Key("openrouter.cost")
+	attrRequestID       = attribute.Key("openrouter.request_id")
+	attrAttempts        = attribute.Key("openrouter.attempts")
+	attrAttempt         = attribute.Key("http.request.resend_count")
+	attrHTTPMethod      = attribute.Key("http.request.method")
+	attrHTTPStatusCode  = attribute.Key("http.response.status_code")
+	attrURLFull         = attribute.Key("url.full")
+	attrStreamingChunks = attribute.Key("openrouter.stream.chunks")
+	attrStreamingTTFT   = attribute.Key("openrouter.stream.time_to_first_token")
+)
+
+// Option configures the tracing of the client.
+type Option func(*config)
+
+// config holds the configuration of the tracing.
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the tracer provider used to create spans.
+// By default, the global tracer provider is used.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used to inject the trace context into request headers.
+// By default, the global text map propagator is used.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// attemptsKey is the context key of the number of attempts made for an API call.
+type attemptsKey struct{}
+
+// tracer creates the spans of API calls and their attempts.
+type tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// WithTracing returns a client option creating a span for every API call sent by the
+// client, with a child span for each of its HTTP attempts.
+func WithTracing(opts ...Option) gopenrouter.Option {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	t := &tracer{
+		tracer:     cfg.tracerProvider.Tracer(instrumentationName, trace.WithInstrumentationVersion(gopenrouter.Version)),
+		propagator: cfg.propagator,
+	}
+
+	return func(c *gopenrouter.Client) {
+		gopenrouter.WithCallMiddleware(t.call)(c)
+		gopenrouter.WithMiddleware(t.attempt)(c)
+	}
+}
+
+// call creates the span of an API call, which ends once its response body is consumed.
+func (t *tracer) call(next gopenrouter.RoundTripFunc) gopenrouter.RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		operation := operationName(req)
+		model := requestModel(req)
+
+		name := operation
+		if model != "" {
+			name = operation + " " + model
+		}
+		ctx, span := t.tracer.Start(req.Context(), name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attrSystem.String("openrouter"),
+				attrOperation.String(operation),
+				attrHTTPMethod.String(req.Method),
+				attrURLFull.String(req.URL.String()),
+			),
+		)
+		if model != "" {
+			span.SetAttributes(attrRequestModel.String(model))
+		}
+
+		attempts := new(atomic.Int32)
+		ctx = context.WithValue(ctx, attemptsKey{}, attempts)
+		resp, err := next(req.WithContext(ctx))
+		span.SetAttributes(attrAttempts.Int(int(attempts.Load())))
+		if err != nil {
+			if status, header := errorResponse(err); status != 0 {
+				span.SetAttributes(attrHTTPStatusCode.Int(status))
+				if id := requestID(header); id != "" {
+					span.SetAttributes(attrRequestID.String(id))
+				}
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.End()
+			return nil, err
+		}
+
+		span.SetAttributes(attrHTTPStatusCode.Int(resp.StatusCode))
+		if id := requestID(resp.Header); id != "" {
+			span.SetAttributes(attrRequestID.String(id))
+		}
+
+		if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+			resp.Body = &streamBody{ReadCloser: resp.Body, span: span, start: start}
+		} else {
+			// Only completions are summarized, so other responses, such as large model
+			// lists, are never held in memory
+			capture := operation == "chat" || operation == "text_completion"
+			resp.Body = &responseBody{ReadCloser: resp.Body, span: span, capture: capture}
+		}
+		return resp, nil
+	}
+}
+
+// attempt creates the span of an HTTP attempt of an API call, which ends once the
+// response headers are received.
+func (t *tracer) attempt(next gopenrouter.RoundTripFunc) gopenrouter.RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		attempt := 1
+		if attempts, ok := req.Context().Value(attemptsKey{}).(*atomic.Int32); ok {
+			attempt = int(attempts.Add(1))
+		}
+
+		ctx, span := t.tracer.Start(req.Context(), req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attrHTTPMethod.String(req.Method),
+				attrURLFull.String(req.URL.String()),
+			),
+		)
+		if attempt > 1 {
+			span.SetAttributes(attrAttempt.Int(attempt - 1))
+		}
+		defer span.End()
+
+		req = req.WithContext(ctx)
+		t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := next(req)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+
+		span.SetAttributes(attrHTTPStatusCode.Int(resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP status %d", resp.StatusCode))
+		}
+		return resp, nil
+	}
+}
+
+// errorResponse returns the status code and headers of the HTTP response an error was
+// received in, or zero if the error did not come from a response.
+func errorResponse(err error) (int, http.Header) {
+	var apiErr *gopenrouter.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode, apiErr.Header
+	}
+	var reqErr *gopenrouter.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.HTTPStatusCode, reqErr.Header
+	}
+	return 0, nil
+}
+
+// operationName returns the generative AI operation performed by req.
+func operationName(req *http.Request) string {
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/chat/completions"):
+		return "chat"
+	case strings.HasSuffix(req.URL.Path, "/completions"):
+		return "text_completion"
+	}
+	return req.Method + " " + req.URL.Path
+}
+
+// requestModel returns the model named in the JSON body of req, if any.
+func requestModel(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var request struct {
+		Model string `json:"model"`
+	}
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		return ""
+	}
+	return request.Model
+}
+
+// requestID returns the OpenRouter request identifier from response headers.
+func requestID(header http.Header) string {
+	if id := header.Get("X-Request-Id"); id != "" {
+		return id
+	}
+	return header.Get("Cf-Ray")
+}
+
+// responseSummary holds the fields of completion responses and stream chunks recorded on spans.
+type responseSummary struct {
+	ID       string `json:"id"`
+	Model    string `json:"model"`
+	Provider string `json:"provider"`
+	Choices  []struct {
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int      `json:"prompt_tokens"`
+		CompletionTokens int      `json:"completion_tokens"`
+		Cost             *float64 `json:"cost"`
+	} `json:"usage"`
+}
+
+// finishReasons returns the finish reasons of the choices that have one.
+func (s *responseSummary) finishReasons() []string {
+	var reasons []string
+	for _, choice := range s.Choices {
+		if choice.FinishReason != nil && *choice.FinishReason != "" {
+			reasons = append(reasons, *choice.FinishReason)
+		}
+	}
+	return reasons
+}
+
+// setAttributes records the summary on span.
+func (s *responseSummary) setAttributes(span trace.Span) {
+	if s.ID != "" {
+		span.SetAttributes(attrResponseID.String(s.ID))
+	}
+	if s.Model != "" {
+		span.SetAttributes(attrResponseModel.String(s.Model))
+	}
+	if s.Provider != "" {
+		span.SetAttributes(attrProvider.String(s.Provider))
+	}
+	if reasons := s.finishReasons(); len(reasons) > 0 {
+		span.SetAttributes(attrFinishReasons.StringSlice(reasons))
+	}
+	if s.Usage != nil {
+		span.SetAttributes(
+			attrInputTokens.Int(s.Usage.PromptTokens),
+			attrOutputTokens.Int(s.Usage.CompletionTokens),
+		)
+		if s.Usage.Cost != nil {
+			span.SetAttributes(attrCost.Float64(*s.Usage.Cost))
+		}
+	}
+}
+
+// responseBody ends the span of an API call once its response body has been read to the
+// end or closed. The bodies of completions are kept while they are read, up to
+// maxSummarySize, to record the completion on the span.
+type responseBody struct {
+	io.ReadCloser
+	span    trace.Span
+	capture bool
+
+	mu    sync.Mutex
+	data  []byte
+	ended bool
+}
+
+func (b *responseBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.capture {
+		if len(b.data)+n > maxSummarySize {
+			b.capture = false
+			b.data = nil
+		} else {
+			b.data = append(b.data, p[:n]...)
+		}
+	}
+	if err != nil {
+		if err != io.EOF {
+			b.span.RecordError(err)
+			b.span.SetStatus(codes.Error, err.Error())
+		}
+		b.end()
+	}
+	return n, err
+}
+
+func (b *responseBody) Close() error {
+	err := b.ReadCloser.Close()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.end()
+	return err
+}
+
+// end records the completion summary, if kept, and ends the span. Only the first call
+// has an effect.
+func (b *responseBody) end() {
+	if b.ended {
+		return
+	}
+	b.ended = true
+
+	if b.capture && len(b.data) > 0 {
+		var summary responseSummary
+		if json.Unmarshal(b.data, &summary) == nil {
+			summary.setAttributes(b.span)
+		}
+		b.data = nil
+	}
+	b.span.End()
+}
+
+// streamBody records the chunks of an event stream on its span as they are read,
+// and ends the span once the stream has been read to the end or closed. The time to
+// first token is recorded in seconds.
+type streamBody struct {
+	io.ReadCloser
+	span  trace.Span
+	start time.Time
+
+	mu      sync.Mutex
+	line    []byte
+	chunks  int
+	ttft    time.Duration
+	summary responseSummary
+	reasons []string
+	ended   bool
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.line = append(b.line, p[:n]...)
+	rest := b.line
+	for {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			break
+		}
+		b.processLine(bytes.TrimRight(rest[:i], "\r"))
+		rest = rest[i+1:]
+	}
+	b.line = append(b.line[:0], rest...)
+
+	if err != nil {
+		if len(b.line) > 0 {
+			b.processLine(b.line)
+			b.line = b.line[:0]
+		}
+		if err != io.EOF {
+			b.span.RecordError(err)
+			b.span.SetStatus(codes.Error, err.Error())
+		}
+		b.end()
+	}
+	return n, err
+}
+
+func (b *streamBody) Close() error {
+	err := b.ReadCloser.Close()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.end()
+	return err
+}
+
+// processLine records the data payload of an event stream line.
+func (b *streamBody) processLine(line []byte) {
+	data, ok := bytes.CutPrefix(line, []byte("data:"))
+	if !ok {
+		return
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("[DONE]")) {
+		return
+	}
+
+	var chunk responseSummary
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return
+	}
+	b.chunks++
+	if b.chunks == 1 {
+		b.ttft = time.Since(b.start)
+	}
+	if chunk.ID != "" {
+		b.summary.ID = chunk.ID
+	}
+	if chunk.Model != "" {
+		b.summary.Model = chunk.Model
+	}
+	if chunk.Provider != "" {
+		b.summary.Provider = chunk.Provider
+	}
+	if chunk.Usage != nil {
+		b.summary.Usage = chunk.Usage
+	}
+	b.reasons = append(b.reasons, chunk.finishReasons()...)
+}
+
+// end records the stream summary and ends the span. Only the first call has an effect.
+func (b *streamBody) end() {
+	if b.ended {
+		return
+	}
+	b.ended = true
+
+	b.summary.setAttributes(b.span)
+	if len(b.reasons) > 0 {
+		b.span.SetAttributes(attrFinishReasons.StringSlice(b.reasons))
+	}
+	b.span.SetAttributes(attrStreamingChunks.Int(b.chunks))
+	if b.chunks > 0 {
+		b.span.SetAttributes(attrStreamingTTFT.Float64(b.ttft.Seconds()))
+	}
+	b.span.End()
+}
@@ -0,0 +1,248 @@
+package otelgopenrouter_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/otelgopenrouter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// attributes returns the attributes of span keyed by name.
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+// newClient returns a client sending requests to url, traced with a recorder.
+func newClient(url string, opts ...gopenrouter.Option) (*gopenrouter.Client, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	opts = append(opts,
+		gopenrouter.WithBaseURL(url),
+		otelgopenrouter.WithTracing(
+			otelgopenrouter.WithTracerProvider(provider),
+			otelgopenrouter.WithPropagator(propagation.TraceContext{}),
+		),
+	)
+	return gopenrouter.New("test-key", opts...), recorder
+}
+
+// callSpan returns the span of the single API call recorded, failing if the recorded
+// spans are not that call and the given number of attempts parented by it.
+func callSpan(t *testing.T, recorder *tracetest.SpanRecorder, attempts int) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	spans := recorder.Ended()
+	if len(spans) != attempts+1 {
+		t.Fatalf("Expected %d spans, got %d", attempts+1, len(spans))
+	}
+	call := spans[len(spans)-1]
+	for _, span := range spans[:len(spans)-1] {
+		if span.Parent().SpanID() != call.SpanContext().SpanID() {
+			t.Errorf("Expected attempt span %q to be a child of the call span", span.Name())
+		}
+	}
+	return call
+}
+
+func TestWithTracing(t *testing.T) {
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	t.Run("ChatCompletion", func(t *testing.T) {
+		var traceparent string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("Traceparent")
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Request-Id", "req-123")
+			_, _ = w.Write([]byte(`{"id":"chatcmpl-1","model":"openai/gpt-4o","provider":"OpenAI","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hi"}}],"usage":{"prompt_tokens":3,"completion_tokens":5,"total_tokens":8,"cost":0.0012}}`))
+		}))
+		defer server.Close()
+
+		client, recorder := newClient(server.URL)
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		response, err := client.ChatCompletion(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Choices[0].Message.Content != "Hi" {
+			t.Errorf("Expected content 'Hi', got '%s'", response.Choices[0].Message.Content)
+		}
+
+		span := callSpan(t, recorder, 1)
+		if span.Name() != "chat openai/gpt-4o" {
+			t.Errorf("Expected span name 'chat openai/gpt-4o', got '%s'", span.Name())
+		}
+		attempt := recorder.Ended()[0]
+		if traceparent == "" || attempt.SpanContext().SpanID().String() != traceparent[36:52] {
+			t.Errorf("Expected traceparent of the attempt span, got '%s'", traceparent)
+		}
+
+		attrs := attributes(span)
+		if got := attrs["gen_ai.request.model"].AsString(); got != "openai/gpt-4o" {
+			t.Errorf("Expected request model 'openai/gpt-4o', got '%s'", got)
+		}
+		if got := attrs["openrouter.provider"].AsString(); got != "OpenAI" {
+			t.Errorf("Expected provider 'OpenAI', got '%s'", got)
+		}
+		if got := attrs["gen_ai.usage.input_tokens"].AsInt64(); got != 3 {
+			t.Errorf("Expected 3 input tokens, got %d", got)
+		}
+		if got := attrs["gen_ai.usage.output_tokens"].AsInt64(); got != 5 {
+			t.Errorf("Expected 5 output tokens, got %d", got)
+		}
+		if got := attrs["openrouter.cost"].AsFloat64(); got != 0.0012 {
+			t.Errorf("Expected cost 0.0012, got %f", got)
+		}
+		if got := attrs["gen_ai.response.finish_reasons"].AsStringSlice(); len(got) != 1 || got[0] != "stop" {
+			t.Errorf("Expected finish reasons [stop], got %v", got)
+		}
+		if got := attrs["openrouter.request_id"].AsString(); got != "req-123" {
+			t.Errorf("Expected request ID 'req-123', got '%s'", got)
+		}
+		if got := attrs["http.response.status_code"].AsInt64(); got != http.StatusOK {
+			t.Errorf("Expected status code 200, got %d", got)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte(
+				"data: {\"id\":\"chatcmpl-1\",\"model\":\"openai/gpt-4o\",\"provider\":\"OpenAI\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n" +
+					"data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n" +
+					"data: {\"id\":\"chatcmpl-1\",\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":1,\"total_tokens\":4}}\n\n" +
+					"data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client, recorder := newClient(server.URL)
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(recorder.Ended()) != 1 {
+			t.Errorf("Expected call span to be open until the stream is read")
+		}
+
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		_ = stream.Close()
+
+		attrs := attributes(callSpan(t, recorder, 1))
+		if got := attrs["openrouter.stream.chunks"].AsInt64(); got != 3 {
+			t.Errorf("Expected 3 chunks, got %d", got)
+		}
+		if got := attrs["openrouter.stream.time_to_first_token"].AsFloat64(); got <= 0 {
+			t.Errorf("Expected a time to first token, got %v", got)
+		}
+		if got := attrs["gen_ai.response.finish_reasons"].AsStringSlice(); len(got) != 1 || got[0] != "stop" {
+			t.Errorf("Expected finish reasons [stop], got %v", got)
+		}
+		if got := attrs["gen_ai.usage.output_tokens"].AsInt64(); got != 1 {
+			t.Errorf("Expected 1 output token, got %d", got)
+		}
+		if got := attrs["gen_ai.response.model"].AsString(); got != "openai/gpt-4o" {
+			t.Errorf("Expected response model 'openai/gpt-4o', got '%s'", got)
+		}
+	})
+
+	t.Run("ErrorStatus", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":429,"message":"rate limited"}}`))
+		}))
+		defer server.Close()
+
+		client, recorder := newClient(server.URL)
+		if _, err := client.GetCredits(context.Background()); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		span := callSpan(t, recorder, 1)
+		if span.Status().Code != codes.Error {
+			t.Errorf("Expected error status, got %v", span.Status())
+		}
+		if span.Name() != "GET /credits" {
+			t.Errorf("Expected span name 'GET /credits', got '%s'", span.Name())
+		}
+		if got := attributes(span)["http.response.status_code"].AsInt64(); got != http.StatusTooManyRequests {
+			t.Errorf("Expected status code 429, got %d", got)
+		}
+	})
+
+	t.Run("Retries", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`))
+		}))
+		defer server.Close()
+
+		client, recorder := newClient(server.URL,
+			gopenrouter.WithRetry(2, gopenrouter.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).Build()
+
+		if _, err := client.ChatCompletion(context.Background(), *request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		span := callSpan(t, recorder, 2)
+		if span.Status().Code == codes.Error {
+			t.Errorf("Expected the call to succeed, got %v", span.Status())
+		}
+		if got := attributes(span)["openrouter.attempts"].AsInt64(); got != 2 {
+			t.Errorf("Expected 2 attempts, got %d", got)
+		}
+		if first := recorder.Ended()[0]; first.Status().Code != codes.Error {
+			t.Errorf("Expected the failed attempt to have an error status, got %v", first.Status())
+		}
+	})
+
+	t.Run("ListModelsFunc", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"openai/gpt-4o"},{"id":"anthropic/claude-3-opus"}]}`))
+		}))
+		defer server.Close()
+
+		client, recorder := newClient(server.URL)
+		var ids []string
+		err := client.ListModelsFunc(context.Background(), func(model gopenrouter.ModelData) bool {
+			ids = append(ids, model.ID)
+			return true
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(ids) != 2 {
+			t.Errorf("Expected 2 models, got %v", ids)
+		}
+		callSpan(t, recorder, 1)
+	})
+}
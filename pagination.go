@@ -0,0 +1,68 @@
+package gopenrouter
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// Page is one page of the results of a list endpoint.
+type Page[T any] struct {
+	// Items are the results on this page
+	Items []T
+	// NextCursor identifies the next page, or is empty if this is the last page
+	NextCursor string
+}
+
+// HasNext reports whether another page follows this one.
+func (p Page[T]) HasNext() bool {
+	return p.NextCursor != ""
+}
+
+// PageFunc fetches the page of results identified by cursor, which is empty for the first page.
+type PageFunc[T any] func(ctx context.Context, cursor string) (Page[T], error)
+
+// Paginate returns an iterator over the items of all pages returned by fetch, requesting
+// each page only once the items of the previous one were consumed. Iteration stops after
+// the last page, when the loop body breaks, or after yielding an error with the zero value.
+//
+// Example usage:
+//
+//	for key, err := range gopenrouter.Paginate(ctx, fetch) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(key.Name)
+//	}
+func Paginate[T any](ctx context.Context, fetch PageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		cursor := ""
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			page, err := fetch(ctx, cursor)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if !page.HasNext() {
+				return
+			}
+			if page.NextCursor == cursor {
+				yield(zero, fmt.Errorf("pagination did not advance past cursor %q", cursor))
+				return
+			}
+			cursor = page.NextCursor
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// pagesOf returns a page function serving the given pages in order.
+func pagesOf(pages ...[]int) (gopenrouter.PageFunc[int], *[]string) {
+	var cursors []string
+	fetch := func(ctx context.Context, cursor string) (gopenrouter.Page[int], error) {
+		cursors = append(cursors, cursor)
+		i := 0
+		if cursor != "" {
+			i, _ = strconv.Atoi(cursor)
+		}
+		page := gopenrouter.Page[int]{Items: pages[i]}
+		if i+1 < len(pages) {
+			page.NextCursor = strconv.Itoa(i + 1)
+		}
+		return page, nil
+	}
+	return fetch, &cursors
+}
+
+func TestPaginate(t *testing.T) {
+	t.Run("AllPages", func(t *testing.T) {
+		fetch, cursors := pagesOf([]int{1, 2}, []int{}, []int{3})
+
+		var items []int
+		for item, err := range gopenrouter.Paginate(context.Background(), fetch) {
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			items = append(items, item)
+		}
+
+		if len(items) != 3 || items[0] != 1 || items[2] != 3 {
+			t.Errorf("Expected items [1 2 3], got %v", items)
+		}
+		if len(*cursors) != 3 || (*cursors)[0] != "" || (*cursors)[2] != "2" {
+			t.Errorf("Expected cursors [\"\" 1 2], got %q", *cursors)
+		}
+	})
+
+	t.Run("Break", func(t *testing.T) {
+		fetch, cursors := pagesOf([]int{1, 2}, []int{3})
+
+		for item := range gopenrouter.Paginate(context.Background(), fetch) {
+			if item == 1 {
+				break
+			}
+		}
+
+		if len(*cursors) != 1 {
+			t.Errorf("Expected only the first page to be fetched, got %d fetches", len(*cursors))
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		fetchErr := errors.New("fetch failed")
+		fetch := func(ctx context.Context, cursor string) (gopenrouter.Page[int], error) {
+			if cursor == "" {
+				return gopenrouter.Page[int]{Items: []int{1}, NextCursor: "next"}, nil
+			}
+			return gopenrouter.Page[int]{}, fetchErr
+		}
+
+		var items []int
+		var errs []error
+		for item, err := range gopenrouter.Paginate(context.Background(), fetch) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			items = append(items, item)
+		}
+
+		if len(items) != 1 {
+			t.Errorf("Expected 1 item before the error, got %v", items)
+		}
+		if len(errs) != 1 || !errors.Is(errs[0], fetchErr) {
+			t.Errorf("Expected a single fetch error, got %v", errs)
+		}
+	})
+
+	t.Run("StalledCursor", func(t *testing.T) {
+		fetch := func(ctx context.Context, cursor string) (gopenrouter.Page[int], error) {
+			return gopenrouter.Page[int]{Items: []int{1}, NextCursor: "same"}, nil
+		}
+
+		var err error
+		count := 0
+		for _, e := range gopenrouter.Paginate(context.Background(), fetch) {
+			if e != nil {
+				err = e
+				break
+			}
+			count++
+			if count > 10 {
+				t.Fatal("Expected pagination to stop on a repeated cursor")
+			}
+		}
+		if err == nil {
+			t.Error("Expected error for a repeated cursor, got nil")
+		}
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		fetch, cursors := pagesOf([]int{1})
+
+		for _, err := range gopenrouter.Paginate(ctx, fetch) {
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected context.Canceled, got %v", err)
+			}
+		}
+		if len(*cursors) != 0 {
+			t.Errorf("Expected no page to be fetched, got %d fetches", len(*cursors))
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package gopenrouter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultConcurrency is the number of requests bulk operations send at the same time.
+const defaultConcurrency = 8
+
+// WithBulkConcurrency sets the maximum number of requests that bulk operations, such as
+// GetGenerations, send at the same time. Non-positive values use the default of 8.
+func WithBulkConcurrency(n int) Option {
+	return func(c *Client) {
+		c.bulkConcurrency = n
+	}
+}
+
+// runConcurrently calls fn for each index in [0, n) from at most limit goroutines and waits
+// for all calls to return. Once ctx is done, fn is no longer called for the remaining
+// indexes; skip, if non-nil, is called for them instead.
+func runConcurrently(ctx context.Context, n, limit int, fn func(ctx context.Context, i int), skip func(i int, err error)) {
+	if limit <= 0 {
+		limit = defaultConcurrency
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, limit)
+	for i := range n {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			if skip != nil {
+				for ; i < n; i++ {
+					skip(i, ctx.Err())
+				}
+			}
+			wg.Wait()
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(ctx, i)
+		}()
+	}
+	wg.Wait()
+}
+
+// defaultRateLimitDelay is the delay before retrying a rate limited request whose
+// response did not indicate when to retry.
+const defaultRateLimitDelay = time.Second
+
+// waitForRateLimit blocks until the rate limit window resets if the most recent response
+// reported that no requests are remaining in it, or until ctx is done.
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	info := c.RateLimit()
+	if info == nil || info.Limit <= 0 || info.Remaining > 0 {
+		return nil
+	}
+	return sleep(ctx, time.Until(info.Reset))
+}
+
+// callRateLimited calls fn once the rate limit allows it. Unless retries are enabled with
+// WithRetry, fn is retried once after the requested delay if it fails because the request
+// was rate limited.
+func (c *Client) callRateLimited(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return err
+	}
+	err := fn(ctx)
+	if c.retryAttempts > 1 || !errors.Is(err, ErrRateLimited) {
+		return err
+	}
+
+	delay := defaultRateLimitDelay
+	var apiErr *APIError
+	var reqErr *RequestError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.RetryAfter > 0:
+		delay = apiErr.RetryAfter
+	case errors.As(err, &reqErr) && reqErr.RetryAfter > 0:
+		delay = reqErr.RetryAfter
+	case c.RateLimit() != nil && time.Until(c.RateLimit().Reset) > 0:
+		delay = time.Until(c.RateLimit().Reset)
+	}
+	if err := sleep(ctx, delay); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
+
+// sleep pauses for the given duration or until ctx is done, returning the context error
+// in the latter case.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
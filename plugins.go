@@ -0,0 +1,115 @@
+package gopenrouter
+
+import (
+	"encoding/json"
+	"maps"
+	"strings"
+)
+
+// onlineSuffix is the model suffix enabling the web search plugin.
+const onlineSuffix = ":online"
+
+// Identifiers of the plugins provided by OpenRouter.
+const (
+	// PluginWeb augments the prompt with web search results
+	PluginWeb = "web"
+	// PluginFileParser parses files, such as PDFs, attached to messages
+	PluginFileParser = "file-parser"
+)
+
+// PDFEngine represents the engine used by the file parser plugin to parse PDFs.
+type PDFEngine string
+
+const (
+	// PDFEnginePDFText extracts the text of PDFs, free of charge; best for documents
+	// with clear text content
+	PDFEnginePDFText PDFEngine = "pdf-text"
+
+	// PDFEngineMistralOCR parses PDFs with OCR, charged per page; best for scanned
+	// documents and documents with images
+	PDFEngineMistralOCR PDFEngine = "mistral-ocr"
+
+	// PDFEngineNative passes PDFs to models supporting files natively, charged as
+	// input tokens
+	PDFEngineNative PDFEngine = "native"
+)
+
+// Plugin enables an OpenRouter plugin for a request.
+// Plugin objects carry their settings alongside their identifier, so Options are sent
+// as fields of the plugin object.
+type Plugin struct {
+	// ID identifies the plugin, such as PluginWeb or PluginFileParser
+	ID string
+	// Options are the settings of the plugin, specific to each plugin
+	Options map[string]any
+}
+
+// MarshalJSON encodes the plugin as a single object containing its ID and options.
+func (p Plugin) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]any, len(p.Options)+1)
+	maps.Copy(fields, p.Options)
+	fields["id"] = p.ID
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes a plugin object, collecting the fields other than its ID
+// into Options.
+func (p *Plugin) UnmarshalJSON(data []byte) error {
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	*p = Plugin{}
+	if id, ok := fields["id"].(string); ok {
+		p.ID = id
+	}
+	delete(fields, "id")
+	if len(fields) > 0 {
+		p.Options = fields
+	}
+	return nil
+}
+
+// WebPluginOptions configures the web search plugin.
+type WebPluginOptions struct {
+	// MaxResults is the number of search results added to the prompt; OpenRouter uses 5 if zero
+	MaxResults int
+	// SearchPrompt is the text introducing the search results in the prompt; OpenRouter
+	// uses a default prompt asking the model to cite its sources if empty
+	SearchPrompt string
+}
+
+// WebPlugin returns the web search plugin configured with the given options.
+func WebPlugin(options WebPluginOptions) Plugin {
+	plugin := Plugin{ID: PluginWeb}
+	if options.MaxResults > 0 || options.SearchPrompt != "" {
+		plugin.Options = make(map[string]any)
+	}
+	if options.MaxResults > 0 {
+		plugin.Options["max_results"] = options.MaxResults
+	}
+	if options.SearchPrompt != "" {
+		plugin.Options["search_prompt"] = options.SearchPrompt
+	}
+	return plugin
+}
+
+// OnlineModel returns the model reference enabling web search with the default settings
+// of the web plugin, by appending the ":online" suffix to the model if not yet present.
+func OnlineModel(model string) string {
+	if strings.HasSuffix(model, onlineSuffix) {
+		return model
+	}
+	return model + onlineSuffix
+}
+
+// FileParserPlugin returns the file parser plugin parsing PDFs with the given engine.
+// Without the plugin, OpenRouter uses the native engine for models supporting files,
+// and the OCR engine otherwise.
+func FileParserPlugin(engine PDFEngine) Plugin {
+	return Plugin{
+		ID:      PluginFileParser,
+		Options: map[string]any{"pdf": map[string]any{"engine": engine}},
+	}
+}
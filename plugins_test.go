@@ -0,0 +1,120 @@
+package gopenrouter_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestPlugin(t *testing.T) {
+	t.Run("Marshal", func(t *testing.T) {
+		plugin := gopenrouter.Plugin{
+			ID:      gopenrouter.PluginFileParser,
+			Options: map[string]any{"pdf": map[string]any{"engine": "pdf-text"}},
+		}
+
+		data, err := json.Marshal(plugin)
+		if err != nil {
+			t.Fatalf("Failed to marshal plugin: %v", err)
+		}
+		expected := `{"id":"file-parser","pdf":{"engine":"pdf-text"}}`
+		if string(data) != expected {
+			t.Errorf("Expected JSON %s, got %s", expected, data)
+		}
+	})
+
+	t.Run("OptionsCannotOverrideID", func(t *testing.T) {
+		plugin := gopenrouter.Plugin{ID: gopenrouter.PluginWeb, Options: map[string]any{"id": "other"}}
+
+		data, err := json.Marshal(plugin)
+		if err != nil {
+			t.Fatalf("Failed to marshal plugin: %v", err)
+		}
+		if string(data) != `{"id":"web"}` {
+			t.Errorf(`Expected JSON {"id":"web"}, got %s`, data)
+		}
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		var plugin gopenrouter.Plugin
+		if err := json.Unmarshal([]byte(`{"id":"web","max_results":3}`), &plugin); err != nil {
+			t.Fatalf("Failed to unmarshal plugin: %v", err)
+		}
+		expected := gopenrouter.Plugin{ID: "web", Options: map[string]any{"max_results": float64(3)}}
+		if !reflect.DeepEqual(plugin, expected) {
+			t.Errorf("Expected plugin %+v, got %+v", expected, plugin)
+		}
+	})
+
+	t.Run("Builders", func(t *testing.T) {
+		web := gopenrouter.Plugin{ID: gopenrouter.PluginWeb}
+		parser := gopenrouter.Plugin{ID: gopenrouter.PluginFileParser}
+
+		chat := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", nil).
+			WithPlugin(web).
+			WithPlugin(parser).
+			Build()
+		if !reflect.DeepEqual(chat.Plugins, []gopenrouter.Plugin{web, parser}) {
+			t.Errorf("Expected chat plugins [web file-parser], got %+v", chat.Plugins)
+		}
+
+		completion := gopenrouter.NewCompletionRequestBuilder("openai/gpt-4o", "Hello").
+			WithPlugins([]gopenrouter.Plugin{web}).
+			Build()
+		data, err := json.Marshal(completion)
+		if err != nil {
+			t.Fatalf("Failed to marshal request: %v", err)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Fatalf("Failed to unmarshal request: %v", err)
+		}
+		expected := []any{map[string]any{"id": "web"}}
+		if !reflect.DeepEqual(body["plugins"], expected) {
+			t.Errorf("Expected plugins %v, got %v", expected, body["plugins"])
+		}
+	})
+
+	t.Run("WebPlugin", func(t *testing.T) {
+		if plugin := gopenrouter.WebPlugin(gopenrouter.WebPluginOptions{}); !reflect.DeepEqual(plugin, gopenrouter.Plugin{ID: "web"}) {
+			t.Errorf("Expected plugin without options, got %+v", plugin)
+		}
+
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", nil).
+			WithWebSearch(gopenrouter.WebPluginOptions{MaxResults: 3, SearchPrompt: "Sources:"}).
+			Build()
+		data, err := json.Marshal(request.Plugins)
+		if err != nil {
+			t.Fatalf("Failed to marshal plugins: %v", err)
+		}
+		expected := `[{"id":"web","max_results":3,"search_prompt":"Sources:"}]`
+		if string(data) != expected {
+			t.Errorf("Expected JSON %s, got %s", expected, data)
+		}
+	})
+
+	t.Run("OnlineModel", func(t *testing.T) {
+		if model := gopenrouter.OnlineModel("openai/gpt-4o"); model != "openai/gpt-4o:online" {
+			t.Errorf("Expected model openai/gpt-4o:online, got %s", model)
+		}
+		if model := gopenrouter.OnlineModel("openai/gpt-4o:online"); model != "openai/gpt-4o:online" {
+			t.Errorf("Expected suffix not to be repeated, got %s", model)
+		}
+	})
+
+	t.Run("FileParserPlugin", func(t *testing.T) {
+		request := gopenrouter.NewCompletionRequestBuilder("openai/gpt-4o", "Summarize the document").
+			WithPDFEngine(gopenrouter.PDFEngineMistralOCR).
+			Build()
+		data, err := json.Marshal(request.Plugins)
+		if err != nil {
+			t.Fatalf("Failed to marshal plugins: %v", err)
+		}
+		expected := `[{"id":"file-parser","pdf":{"engine":"mistral-ocr"}}]`
+		if string(data) != expected {
+			t.Errorf("Expected JSON %s, got %s", expected, data)
+		}
+	})
+}
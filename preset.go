@@ -0,0 +1,21 @@
+package gopenrouter
+
+// presetPrefix is the prefix of model references invoking a preset.
+const presetPrefix = "@preset/"
+
+// PresetModel returns the model reference invoking the preset with the given slug, for
+// use as the model of a request. The preset configured in the OpenRouter dashboard then
+// provides the model, provider preferences, system prompt, and parameters of the request.
+//
+// Example usage:
+//
+//	request := gopenrouter.NewChatCompletionRequestBuilder(gopenrouter.PresetModel("email-copywriter"), messages).Build()
+func PresetModel(slug string) string {
+	return presetPrefix + slug
+}
+
+// ModelWithPreset returns the model reference applying the preset with the given slug to
+// a specific model, overriding the model configured in the preset.
+func ModelWithPreset(model, slug string) string {
+	return model + presetPrefix + slug
+}
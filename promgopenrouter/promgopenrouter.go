@@ -0,0 +1,181 @@
+// Package promgopenrouter exports the measurements of the gopenrouter client as
+// Prometheus metrics.
+//
+// The metrics are registered with a Prometheus registerer and passed to the client:
+//
+//	metrics, err := promgopenrouter.New(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	  // handle error
+//	}
+//	client := gopenrouter.New("your-api-key", gopenrouter.WithMetrics(metrics))
+//
+// This module requires gopenrouter v0.5.0 or later, the first release with WithMetrics.
+package promgopenrouter
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace prefixes the names of all metrics.
+const namespace = "openrouter"
+
+// Metrics implements gopenrouter.Metrics and gopenrouter.StreamMetricsObserver with
+// Prometheus collectors.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	tokens   *prometheus.CounterVec
+	cost     *prometheus.CounterVec
+	ttft     *prometheus.HistogramVec
+	streams  *prometheus.HistogramVec
+}
+
+var _ gopenrouter.StreamMetricsObserver = (*Metrics)(nil)
+
+// Option configures the Prometheus metrics.
+type Option func(*config)
+
+// config holds the configuration of the Prometheus metrics.
+type config struct {
+	buckets       []float64
+	streamBuckets []float64
+	constLabels   prometheus.Labels
+}
+
+// defaultStreamBuckets are the default buckets of the stream histograms, in seconds.
+// They extend further than prometheus.DefBuckets, as generations can take minutes.
+var defaultStreamBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// WithBuckets sets the buckets of the request latency histogram, in seconds.
+// By default, prometheus.DefBuckets are used.
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// WithStreamBuckets sets the buckets of the time to first token and stream duration
+// histograms, in seconds.
+func WithStreamBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.streamBuckets = buckets
+	}
+}
+
+// WithConstLabels sets labels added to all metrics, such as the name of the application.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *config) {
+		c.constLabels = labels
+	}
+}
+
+// New creates the Prometheus metrics and registers them with registerer.
+//
+// The following metrics are exported:
+//   - openrouter_requests_total: HTTP requests by endpoint, method, and status code
+//   - openrouter_request_errors_total: failed HTTP requests by endpoint and status code,
+//     with code "0" for requests that received no response
+//   - openrouter_request_duration_seconds: latency of HTTP requests by endpoint and method
+//   - openrouter_tokens_total: tokens by model, provider, and type ("prompt" or "completion")
+//   - openrouter_cost_total: cost in credits by model and provider
+//   - openrouter_stream_time_to_first_token_seconds: time until the first chunk of streams
+//     by endpoint, model, and provider
+//   - openrouter_stream_duration_seconds: duration of streams by endpoint, model, provider,
+//     and status ("ok" or "error")
+func New(registerer prometheus.Registerer, opts ...Option) (*Metrics, error) {
+	cfg := config{buckets: prometheus.DefBuckets, streamBuckets: defaultStreamBuckets}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "requests_total",
+			Help:        "Number of HTTP requests sent to OpenRouter, including retries.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"endpoint", "method", "code"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "request_errors_total",
+			Help:        "Number of failed HTTP requests sent to OpenRouter.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"endpoint", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Time until the response headers of HTTP requests to OpenRouter were received.",
+			Buckets:     cfg.buckets,
+			ConstLabels: cfg.constLabels,
+		}, []string{"endpoint", "method"}),
+		tokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "tokens_total",
+			Help:        "Number of tokens used by completions.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"model", "provider", "type"}),
+		cost: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "cost_total",
+			Help:        "Cost of completions in credits.",
+			ConstLabels: cfg.constLabels,
+		}, []string{"model", "provider"}),
+		ttft: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "stream_time_to_first_token_seconds",
+			Help:        "Time between sending streaming requests to OpenRouter and receiving their first chunk.",
+			Buckets:     cfg.streamBuckets,
+			ConstLabels: cfg.constLabels,
+		}, []string{"endpoint", "model", "provider"}),
+		streams: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "stream_duration_seconds",
+			Help:        "Time between sending streaming requests to OpenRouter and the end of their streams.",
+			Buckets:     cfg.streamBuckets,
+			ConstLabels: cfg.constLabels,
+		}, []string{"endpoint", "model", "provider", "status"}),
+	}
+
+	for _, collector := range []prometheus.Collector{m.requests, m.errors, m.latency, m.tokens, m.cost, m.ttft, m.streams} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveRequest records a completed attempt of an HTTP request.
+func (m *Metrics) ObserveRequest(ctx context.Context, r gopenrouter.RequestMetrics) {
+	code := strconv.Itoa(r.StatusCode)
+	m.requests.WithLabelValues(r.Endpoint, r.Method, code).Inc()
+	m.latency.WithLabelValues(r.Endpoint, r.Method).Observe(r.Latency.Seconds())
+	if r.Err != nil {
+		m.errors.WithLabelValues(r.Endpoint, code).Inc()
+	}
+}
+
+// ObserveUsage records the token usage and cost of a completion.
+func (m *Metrics) ObserveUsage(ctx context.Context, u gopenrouter.UsageMetrics) {
+	m.tokens.WithLabelValues(u.Model, u.Provider, "prompt").Add(float64(u.PromptTokens))
+	m.tokens.WithLabelValues(u.Model, u.Provider, "completion").Add(float64(u.CompletionTokens))
+	if u.Cost > 0 {
+		m.cost.WithLabelValues(u.Model, u.Provider).Add(u.Cost)
+	}
+}
+
+// ObserveStream records the time to first token and the duration of a stream.
+func (m *Metrics) ObserveStream(ctx context.Context, s gopenrouter.StreamEndInfo) {
+	if s.Metrics.Chunks > 0 {
+		m.ttft.WithLabelValues(s.Endpoint, s.Model, s.Provider).Observe(s.Metrics.TimeToFirstToken.Seconds())
+	}
+	status := "ok"
+	if s.Err != nil {
+		status = "error"
+	}
+	m.streams.WithLabelValues(s.Endpoint, s.Model, s.Provider, status).Observe(s.Metrics.TotalDuration.Seconds())
+}
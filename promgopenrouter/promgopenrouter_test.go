@@ -0,0 +1,130 @@
+package promgopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/promgopenrouter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	var fail bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":429,"message":"rate limited"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"cmpl-1","provider":"OpenAI","model":"openai/gpt-4o","choices":[{"text":"Hi","index":0}],"usage":{"prompt_tokens":3,"completion_tokens":5,"total_tokens":8}}`))
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	metrics, err := promgopenrouter.New(registry, promgopenrouter.WithConstLabels(prometheus.Labels{"app": "test"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithMetrics(metrics))
+	request := gopenrouter.NewCompletionRequestBuilder("openai/gpt-4o", "Hello").Build()
+
+	for range 2 {
+		if _, err := client.Completion(context.Background(), *request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	fail = true
+	if _, err := client.Completion(context.Background(), *request); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	expected := `
+# HELP openrouter_request_errors_total Number of failed HTTP requests sent to OpenRouter.
+# TYPE openrouter_request_errors_total counter
+openrouter_request_errors_total{app="test",code="429",endpoint="/completions"} 1
+# HELP openrouter_requests_total Number of HTTP requests sent to OpenRouter, including retries.
+# TYPE openrouter_requests_total counter
+openrouter_requests_total{app="test",code="200",endpoint="/completions",method="POST"} 2
+openrouter_requests_total{app="test",code="429",endpoint="/completions",method="POST"} 1
+# HELP openrouter_tokens_total Number of tokens used by completions.
+# TYPE openrouter_tokens_total counter
+openrouter_tokens_total{app="test",model="openai/gpt-4o",provider="OpenAI",type="completion"} 10
+openrouter_tokens_total{app="test",model="openai/gpt-4o",provider="OpenAI",type="prompt"} 6
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"openrouter_requests_total", "openrouter_request_errors_total", "openrouter_tokens_total")
+	if err != nil {
+		t.Errorf("Unexpected metrics: %v", err)
+	}
+
+	if got := testutil.CollectAndCount(registry, "openrouter_request_duration_seconds"); got != 1 {
+		t.Errorf("Expected 1 latency histogram, got %d", got)
+	}
+
+	metrics.ObserveUsage(context.Background(), gopenrouter.UsageMetrics{Model: "openai/gpt-4o", Provider: "OpenAI", Cost: 0.25})
+	expected = `
+# HELP openrouter_cost_total Cost of completions in credits.
+# TYPE openrouter_cost_total counter
+openrouter_cost_total{app="test",model="openai/gpt-4o",provider="OpenAI"} 0.25
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "openrouter_cost_total"); err != nil {
+		t.Errorf("Unexpected metrics: %v", err)
+	}
+}
+
+func TestStreamMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := promgopenrouter.New(registry, promgopenrouter.WithStreamBuckets([]float64{0.5, 1, 5}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	metrics.ObserveStream(context.Background(), gopenrouter.StreamEndInfo{
+		Endpoint: "/chat/completions",
+		Model:    "openai/gpt-4o",
+		Provider: "OpenAI",
+		Metrics:  gopenrouter.StreamMetrics{Chunks: 3, TimeToFirstToken: 400 * time.Millisecond, TotalDuration: 2 * time.Second},
+	})
+	metrics.ObserveStream(context.Background(), gopenrouter.StreamEndInfo{
+		Endpoint: "/chat/completions",
+		Model:    "openai/gpt-4o",
+		Metrics:  gopenrouter.StreamMetrics{TotalDuration: 800 * time.Millisecond},
+		Err:      errors.New("stream stalled"),
+	})
+
+	expected := `
+# HELP openrouter_stream_time_to_first_token_seconds Time between sending streaming requests to OpenRouter and receiving their first chunk.
+# TYPE openrouter_stream_time_to_first_token_seconds histogram
+openrouter_stream_time_to_first_token_seconds_bucket{endpoint="/chat/completions",model="openai/gpt-4o",provider="OpenAI",le="0.5"} 1
+openrouter_stream_time_to_first_token_seconds_bucket{endpoint="/chat/completions",model="openai/gpt-4o",provider="OpenAI",le="1"} 1
+openrouter_stream_time_to_first_token_seconds_bucket{endpoint="/chat/completions",model="openai/gpt-4o",provider="OpenAI",le="5"} 1
+openrouter_stream_time_to_first_token_seconds_bucket{endpoint="/chat/completions",model="openai/gpt-4o",provider="OpenAI",le="+Inf"} 1
+openrouter_stream_time_to_first_token_seconds_sum{endpoint="/chat/completions",model="openai/gpt-4o",provider="OpenAI"} 0.4
+openrouter_stream_time_to_first_token_seconds_count{endpoint="/chat/completions",model="openai/gpt-4o",provider="OpenAI"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "openrouter_stream_time_to_first_token_seconds"); err != nil {
+		t.Errorf("Unexpected metrics: %v", err)
+	}
+
+	if got := testutil.CollectAndCount(registry, "openrouter_stream_duration_seconds"); got != 2 {
+		t.Errorf("Expected 2 stream duration histograms, got %d", got)
+	}
+}
+
+func TestDuplicateRegistration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := promgopenrouter.New(registry); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := promgopenrouter.New(registry); err == nil {
+		t.Error("Expected error registering the metrics twice, got nil")
+	}
+}
@@ -0,0 +1,81 @@
+package gopenrouter
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// providerOptionsFields has the fields of ProviderOptions without its JSON methods.
+type providerOptionsFields ProviderOptions
+
+// providerOptionKeys returns the JSON keys of the fields of ProviderOptions.
+var providerOptionKeys = sync.OnceValue(func() map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeFor[providerOptionsFields]()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+})
+
+// MarshalJSON encodes the provider options, adding the parameter overrides of each
+// provider as a field keyed by the provider slug. Overrides for a slug colliding with the
+// name of a provider option, such as "sort", cannot be encoded and fail with an error.
+func (o ProviderOptions) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(providerOptionsFields(o))
+	if err != nil || len(o.ProviderParameters) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for provider, parameters := range o.ProviderParameters {
+		if providerOptionKeys()[provider] {
+			return nil, fmt.Errorf("provider parameters for %q collide with the provider option of the same name", provider)
+		}
+		raw, err := json.Marshal(parameters)
+		if err != nil {
+			return nil, err
+		}
+		fields[provider] = raw
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes provider options, collecting the object fields not matching
+// a known option into the parameter overrides of the providers.
+func (o *ProviderOptions) UnmarshalJSON(data []byte) error {
+	var options providerOptionsFields
+	if err := json.Unmarshal(data, &options); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for key, raw := range fields {
+		if providerOptionKeys()[key] {
+			continue
+		}
+		var parameters map[string]any
+		if err := json.Unmarshal(raw, &parameters); err != nil || parameters == nil {
+			continue
+		}
+		if options.ProviderParameters == nil {
+			options.ProviderParameters = make(map[string]map[string]any)
+		}
+		options.ProviderParameters[key] = parameters
+	}
+
+	*o = ProviderOptions(options)
+	return nil
+}
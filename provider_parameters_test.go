@@ -0,0 +1,80 @@
+package gopenrouter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestProviderParameters(t *testing.T) {
+	t.Run("Marshal", func(t *testing.T) {
+		options := gopenrouter.NewProviderOptionsBuilder().
+			WithOrder([]string{"openai", "anthropic"}).
+			WithProviderParameters("openai", map[string]any{"temperature": 0.2}).
+			WithProviderParameters("anthropic", map[string]any{"temperature": 0.5}).
+			WithProviderParameters("openai", map[string]any{"service_tier": "flex"}).
+			Build()
+
+		data, err := json.Marshal(options)
+		if err != nil {
+			t.Fatalf("Failed to marshal options: %v", err)
+		}
+		expected := `{"anthropic":{"temperature":0.5},"openai":{"service_tier":"flex","temperature":0.2},"order":["openai","anthropic"]}`
+		if string(data) != expected {
+			t.Errorf("Expected JSON %s, got %s", expected, data)
+		}
+	})
+
+	t.Run("CollidingProvider", func(t *testing.T) {
+		builder := gopenrouter.NewProviderOptionsBuilder().
+			WithSortStrategy(gopenrouter.SortPrice).
+			WithProviderParameters("sort", map[string]any{"temperature": 0.2})
+
+		if _, err := json.Marshal(builder.Build()); err == nil {
+			t.Error("Expected an error marshaling parameters colliding with an option, got nil")
+		}
+
+		_, err := builder.BuildE()
+		var validationErr *gopenrouter.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected ValidationError, got %v", err)
+		}
+		if len(validationErr.Violations) != 1 || validationErr.Violations[0].Field != "sort" {
+			t.Errorf("Expected a violation of sort, got %+v", validationErr.Violations)
+		}
+	})
+
+	t.Run("WithoutParameters", func(t *testing.T) {
+		options := gopenrouter.NewProviderOptionsBuilder().WithZDR(true).Build()
+
+		data, err := json.Marshal(options)
+		if err != nil {
+			t.Fatalf("Failed to marshal options: %v", err)
+		}
+		if string(data) != `{"zdr":true}` {
+			t.Errorf(`Expected JSON {"zdr":true}, got %s`, data)
+		}
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		var options gopenrouter.ProviderOptions
+		data := `{"order":["openai"],"openai":{"temperature":0.2},"max_price":{"prompt":1}}`
+		if err := json.Unmarshal([]byte(data), &options); err != nil {
+			t.Fatalf("Failed to unmarshal options: %v", err)
+		}
+
+		if !reflect.DeepEqual(options.Order, []string{"openai"}) {
+			t.Errorf("Expected order [openai], got %v", options.Order)
+		}
+		if options.MaxPrice == nil || options.MaxPrice.Prompt == nil || *options.MaxPrice.Prompt != 1 {
+			t.Errorf("Expected max prompt price 1, got %+v", options.MaxPrice)
+		}
+		expected := map[string]map[string]any{"openai": {"temperature": 0.2}}
+		if !reflect.DeepEqual(options.ProviderParameters, expected) {
+			t.Errorf("Expected provider parameters %v, got %v", expected, options.ProviderParameters)
+		}
+	})
+}
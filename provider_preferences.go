@@ -0,0 +1,95 @@
+package gopenrouter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// AccountProviderPreferences describes the provider routing settings of an OpenRouter
+// account, which apply to all of its requests in addition to their provider options.
+//
+// OpenRouter does not expose these settings through its API, so they must be copied from
+// the account settings, for example through the configuration of the application.
+type AccountProviderPreferences struct {
+	// IgnoredProviders lists the providers never used for requests of the account
+	IgnoredProviders []string
+}
+
+// ProviderConflict describes provider options of a request that conflict with the
+// provider preferences of the account.
+type ProviderConflict struct {
+	// Providers lists the providers named in the Order or Only options of the request
+	// that the account ignores
+	Providers []string
+	// NoneAvailable indicates that all providers allowed by the Only option of the request
+	// are ignored by the account, so that the request cannot be routed to any provider
+	NoneAvailable bool
+}
+
+// Error describes the conflict, so that it can be returned or logged as an error.
+func (c *ProviderConflict) Error() string {
+	if c.NoneAvailable {
+		return fmt.Sprintf("all allowed providers are ignored by the account: %s", strings.Join(c.Providers, ", "))
+	}
+	return fmt.Sprintf("providers ignored by the account: %s", strings.Join(c.Providers, ", "))
+}
+
+// Conflicts returns the conflicts between the provider options of a request and the
+// preferences of the account, or nil if there are none. Provider names are compared
+// case-insensitively.
+func (p AccountProviderPreferences) Conflicts(options *ProviderOptions) *ProviderConflict {
+	if options == nil || len(p.IgnoredProviders) == 0 {
+		return nil
+	}
+
+	ignored := func(provider string) bool {
+		return slices.ContainsFunc(p.IgnoredProviders, func(name string) bool {
+			return strings.EqualFold(name, provider)
+		})
+	}
+
+	var conflict ProviderConflict
+	for _, provider := range slices.Concat(options.Order, options.Only) {
+		if ignored(provider) && !slices.Contains(conflict.Providers, provider) {
+			conflict.Providers = append(conflict.Providers, provider)
+		}
+	}
+	if len(conflict.Providers) == 0 {
+		return nil
+	}
+	conflict.NoneAvailable = len(options.Only) > 0 && !slices.ContainsFunc(options.Only, func(provider string) bool {
+		return !ignored(provider)
+	})
+	return &conflict
+}
+
+// WithAccountProviderPreferences makes the client warn about completion and chat
+// completion requests whose provider options conflict with the provider preferences of
+// the account, which otherwise fail with confusing errors about providers being
+// unavailable. Conflicts are logged at warn level with the logger set with WithLogger.
+func WithAccountProviderPreferences(preferences AccountProviderPreferences) Option {
+	return func(c *Client) {
+		c.accountProviders = &preferences
+	}
+}
+
+// checkProviderPreferences logs the conflicts between the provider options of a request
+// and the provider preferences of the account, if set.
+func (c *Client) checkProviderPreferences(ctx context.Context, model string, options *ProviderOptions) {
+	if c.accountProviders == nil || !c.log.enabled(ctx, slog.LevelWarn) {
+		return
+	}
+
+	conflict := c.accountProviders.Conflicts(options)
+	if conflict == nil {
+		return
+	}
+	c.log.logger.LogAttrs(ctx, slog.LevelWarn, "openrouter provider conflict",
+		slog.String("model", model),
+		slog.Any("providers", conflict.Providers),
+		slog.Bool("none_available", conflict.NoneAvailable),
+	)
+}
@@ -0,0 +1,102 @@
+package gopenrouter_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestAccountProviderPreferences(t *testing.T) {
+	preferences := gopenrouter.AccountProviderPreferences{IgnoredProviders: []string{"DeepInfra", "together"}}
+
+	tests := []struct {
+		name     string
+		options  *gopenrouter.ProviderOptions
+		expected *gopenrouter.ProviderConflict
+	}{
+		{
+			name:    "NoOptions",
+			options: nil,
+		},
+		{
+			name:    "NoConflict",
+			options: gopenrouter.NewProviderOptionsBuilder().WithOrder([]string{"OpenAI", "Anthropic"}).Build(),
+		},
+		{
+			name:     "IgnoredInOrder",
+			options:  gopenrouter.NewProviderOptionsBuilder().WithOrder([]string{"deepinfra", "OpenAI", "deepinfra"}).Build(),
+			expected: &gopenrouter.ProviderConflict{Providers: []string{"deepinfra"}},
+		},
+		{
+			name: "SomeAllowedIgnored",
+			options: gopenrouter.NewProviderOptionsBuilder().
+				WithOnly([]string{"Together", "OpenAI"}).
+				Build(),
+			expected: &gopenrouter.ProviderConflict{Providers: []string{"Together"}},
+		},
+		{
+			name: "AllAllowedIgnored",
+			options: gopenrouter.NewProviderOptionsBuilder().
+				WithOnly([]string{"Together", "DeepInfra"}).
+				Build(),
+			expected: &gopenrouter.ProviderConflict{Providers: []string{"Together", "DeepInfra"}, NoneAvailable: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflict := preferences.Conflicts(tt.options)
+			if !reflect.DeepEqual(conflict, tt.expected) {
+				t.Errorf("Expected conflict %+v, got %+v", tt.expected, conflict)
+			}
+		})
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		conflict := &gopenrouter.ProviderConflict{Providers: []string{"Together", "DeepInfra"}, NoneAvailable: true}
+		expected := "all allowed providers are ignored by the account: Together, DeepInfra"
+		if conflict.Error() != expected {
+			t.Errorf("Expected error %q, got %q", expected, conflict.Error())
+		}
+	})
+}
+
+func TestWithAccountProviderPreferences(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"gen-1","choices":[{"message":{"role":"assistant","content":"Hi"}}]}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := gopenrouter.New("test-key",
+		gopenrouter.WithBaseURL(server.URL),
+		gopenrouter.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+		gopenrouter.WithAccountProviderPreferences(gopenrouter.AccountProviderPreferences{IgnoredProviders: []string{"Together"}}),
+	)
+	request := gopenrouter.NewChatCompletionRequestBuilder("meta-llama/llama-3-70b", []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}).
+		WithProvider(gopenrouter.NewProviderOptionsBuilder().WithOnly([]string{"Together"}).Build()).
+		Build()
+
+	if _, err := client.ChatCompletion(context.Background(), *request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	records := parseLogRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 log record, got %d", len(records))
+	}
+	record := records[0]
+	if record["msg"] != "openrouter provider conflict" || record["level"] != "WARN" {
+		t.Errorf("Expected provider conflict warning, got %v", record)
+	}
+	if record["model"] != "meta-llama/llama-3-70b" || record["none_available"] != true {
+		t.Errorf("Unexpected log attributes: %v", record)
+	}
+}
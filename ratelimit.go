@@ -0,0 +1,87 @@
+package gopenrouter
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitInfo describes the rate limit state reported by OpenRouter in the
+// X-RateLimit-* response headers.
+type RateLimitInfo struct {
+	// Limit is the maximum number of requests allowed in the current window
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is the time at which the current window resets, if reported
+	Reset time.Time
+}
+
+// RateLimit returns the rate limit state reported by the most recent response carrying
+// X-RateLimit-* headers, or nil if none has been received yet. Schedulers can use it to
+// adapt their concurrency to the remaining capacity of the account.
+func (c *Client) RateLimit() *RateLimitInfo {
+	info := c.rateLimit.Load()
+	if info == nil {
+		return nil
+	}
+	copied := *info
+	return &copied
+}
+
+// recordRateLimit stores the rate limit state reported in the headers of a response.
+func (c *Client) recordRateLimit(header http.Header) {
+	if info := parseRateLimit(header, time.Now()); info != nil {
+		c.rateLimit.Store(info)
+	}
+}
+
+// parseRateLimit extracts rate limit information from the response headers.
+// It returns nil if none of the X-RateLimit-* headers are present.
+func parseRateLimit(header http.Header, now time.Time) *RateLimitInfo {
+	limit := header.Get("X-RateLimit-Limit")
+	remaining := header.Get("X-RateLimit-Remaining")
+	reset := header.Get("X-RateLimit-Reset")
+	if limit == "" && remaining == "" && reset == "" {
+		return nil
+	}
+
+	info := &RateLimitInfo{}
+	info.Limit, _ = strconv.Atoi(strings.TrimSpace(limit))
+	info.Remaining, _ = strconv.Atoi(strings.TrimSpace(remaining))
+	if value, err := strconv.ParseInt(strings.TrimSpace(reset), 10, 64); err == nil {
+		switch {
+		case value >= 1e12:
+			// Unix timestamp in milliseconds, as sent by OpenRouter
+			info.Reset = time.UnixMilli(value)
+		case value >= 1e9:
+			// Unix timestamp in seconds
+			info.Reset = time.Unix(value, 0)
+		default:
+			// Seconds until the window resets
+			info.Reset = now.Add(time.Duration(value) * time.Second)
+		}
+	}
+	return info
+}
+
+// parseRetryAfter returns the delay requested by the Retry-After header, which may hold
+// either a number of seconds or an HTTP date. It returns zero if the header is absent or invalid.
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
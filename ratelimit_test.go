@@ -0,0 +1,70 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestClientRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Truncate(time.Millisecond)
+	remaining := 10
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/models" {
+			// responses without rate limit headers keep the last observed state
+			_, _ = w.Write([]byte(`{"data":[]}`))
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "20")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.UnixMilli(), 10))
+		if remaining == 0 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":429,"message":"Rate limit exceeded"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"total_credits":10,"total_usage":1}}`))
+	}))
+	defer server.Close()
+
+	client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+	if info := client.RateLimit(); info != nil {
+		t.Errorf("Expected no rate limit state before the first request, got %+v", info)
+	}
+
+	data, err := client.GetCredits(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	info := client.RateLimit()
+	if info == nil {
+		t.Fatal("Expected rate limit state")
+	}
+	if info.Limit != 20 || info.Remaining != 10 || !info.Reset.Equal(reset) {
+		t.Errorf("Expected limit 20 with 10 remaining until %v, got %+v", reset, info)
+	}
+	if responseInfo := data.RateLimit(); responseInfo == nil || *responseInfo != *info {
+		t.Errorf("Expected response rate limit %+v, got %+v", info, responseInfo)
+	}
+
+	if _, err := client.ListModels(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info := client.RateLimit(); info == nil || info.Remaining != 10 {
+		t.Errorf("Expected last observed state to be kept, got %+v", info)
+	}
+
+	remaining = 0
+	if _, err := client.GetCredits(context.Background()); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if info := client.RateLimit(); info == nil || info.Remaining != 0 {
+		t.Errorf("Expected rate limit state of the failed request, got %+v", info)
+	}
+}
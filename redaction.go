@@ -0,0 +1,124 @@
+package gopenrouter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Redaction configures the values hidden from request and response dumps, so that debug
+// output can be enabled in production without leaking credentials or user content. The
+// Authorization header is always redacted.
+//
+// Its methods can also be used by custom logging middleware.
+//
+// Example usage:
+//
+//	client := gopenrouter.New(apiKey,
+//		gopenrouter.WithDebug(os.Stderr),
+//		gopenrouter.WithDebugRedaction(gopenrouter.Redaction{
+//			Headers: []string{"X-Gateway-Key"},
+//			Fields:  []string{"messages.content", "choices.message.content", "choices.delta.content"},
+//		}),
+//	)
+type Redaction struct {
+	// Headers lists further headers whose values are redacted, such as the credentials of
+	// an API gateway
+	Headers []string
+	// Fields lists the dot-separated paths of JSON fields whose values are redacted in
+	// bodies and stream chunks, such as "messages.content". Arrays are traversed
+	// implicitly, and a "*" segment matches any field.
+	Fields []string
+	// Func, if set, is applied to every body and stream chunk after the fields have been
+	// redacted, for redaction the field paths cannot express
+	Func func(body []byte) []byte
+}
+
+// WithDebugRedaction sets the values redacted from the output of WithDebug.
+func WithDebugRedaction(redaction Redaction) Option {
+	return func(c *Client) {
+		c.debug.redaction = redaction
+	}
+}
+
+// RedactHeader returns a copy of header whose sensitive values are replaced with
+// "[REDACTED]".
+func (r Redaction) RedactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for key, values := range header {
+		if !r.sensitiveHeader(key) {
+			continue
+		}
+		for i := range values {
+			values[i] = redacted
+		}
+	}
+	return header
+}
+
+// RedactBody returns body with the values of the redacted fields replaced with
+// "[REDACTED]", followed by the result of Func if set. Fields are only redacted in
+// valid JSON, which is re-encoded with its object keys sorted.
+func (r Redaction) RedactBody(body []byte) []byte {
+	if len(r.Fields) > 0 {
+		var value any
+		if err := json.Unmarshal(body, &value); err == nil {
+			for _, field := range r.Fields {
+				value = redactValue(value, strings.Split(field, "."))
+			}
+			if data, err := json.Marshal(value); err == nil {
+				body = data
+			}
+		}
+	}
+	if r.Func != nil {
+		body = r.Func(body)
+	}
+	return body
+}
+
+// redactLine returns a line of an event stream with the payload of a data field redacted.
+func (r Redaction) redactLine(line []byte) []byte {
+	data, ok := bytes.CutPrefix(line, []byte("data:"))
+	if !ok || (len(r.Fields) == 0 && r.Func == nil) {
+		return line
+	}
+	data = bytes.TrimPrefix(data, []byte(" "))
+	return append([]byte("data: "), r.RedactBody(data)...)
+}
+
+// sensitiveHeader reports whether the values of the header with the given name are redacted.
+func (r Redaction) sensitiveHeader(key string) bool {
+	key = http.CanonicalHeaderKey(key)
+	if key == "Authorization" {
+		return true
+	}
+	for _, header := range r.Headers {
+		if http.CanonicalHeaderKey(header) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue replaces the values at the given path within a generic JSON value.
+func redactValue(value any, path []string) any {
+	if len(path) == 0 {
+		return redacted
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for key, field := range value {
+			if path[0] == "*" || key == path[0] {
+				value[key] = redactValue(field, path[1:])
+			}
+		}
+	case []any:
+		for i, element := range value {
+			value[i] = redactValue(element, path)
+		}
+	}
+	return value
+}
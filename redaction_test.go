@@ -0,0 +1,140 @@
+package gopenrouter_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+func TestRedaction(t *testing.T) {
+	redaction := gopenrouter.Redaction{
+		Headers: []string{"x-gateway-key"},
+		Fields:  []string{"messages.content", "choices.*.content", "user"},
+	}
+
+	t.Run("RedactHeader", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Authorization", "Bearer secret")
+		header.Set("X-Gateway-Key", "gateway-secret")
+		header.Set("Content-Type", "application/json")
+
+		redacted := redaction.RedactHeader(header)
+		if redacted.Get("Authorization") != "[REDACTED]" || redacted.Get("X-Gateway-Key") != "[REDACTED]" {
+			t.Errorf("Expected sensitive headers to be redacted, got %v", redacted)
+		}
+		if redacted.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected other headers to be kept, got %v", redacted)
+		}
+		if header.Get("Authorization") != "Bearer secret" {
+			t.Error("Expected the original header to be unchanged")
+		}
+	})
+
+	t.Run("RedactBody", func(t *testing.T) {
+		body := `{"model":"m","user":"alice","messages":[{"role":"user","content":"secret"},{"role":"assistant","content":"also secret"}]}`
+		expected := `{"messages":[{"content":"[REDACTED]","role":"user"},{"content":"[REDACTED]","role":"assistant"}],"model":"m","user":"[REDACTED]"}`
+		if got := string(redaction.RedactBody([]byte(body))); got != expected {
+			t.Errorf("Expected body %s, got %s", expected, got)
+		}
+
+		if got := string(redaction.RedactBody([]byte("not json"))); got != "not json" {
+			t.Errorf("Expected invalid JSON to be kept, got %s", got)
+		}
+	})
+
+	t.Run("Func", func(t *testing.T) {
+		custom := gopenrouter.Redaction{Func: func(body []byte) []byte {
+			return bytes.ReplaceAll(body, []byte("4111-1111"), []byte("****"))
+		}}
+		if got := string(custom.RedactBody([]byte(`card 4111-1111`))); got != "card ****" {
+			t.Errorf("Expected custom redaction, got %s", got)
+		}
+	})
+
+	t.Run("Debug", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"gen-1","choices":[{"index":0,"message":{"role":"assistant","content":"private answer"}}]}`))
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		client := gopenrouter.New("secret-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithHeader("X-Gateway-Key", "gateway-secret"),
+			gopenrouter.WithDebug(&buf),
+			gopenrouter.WithDebugRedaction(redaction),
+		)
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "private question"}}
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		response, err := client.ChatCompletion(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Choices[0].Message.Content != "private answer" {
+			t.Errorf("Expected the response to be unaffected, got %s", response.Choices[0].Message.Content)
+		}
+
+		output := buf.String()
+		for _, secret := range []string{"secret-key", "gateway-secret", "private question", "private answer"} {
+			if strings.Contains(output, secret) {
+				t.Errorf("Expected %q to be redacted, got:\n%s", secret, output)
+			}
+		}
+		if !strings.Contains(output, `"model":"test-model"`) {
+			t.Errorf("Expected other fields to be kept, got:\n%s", output)
+		}
+	})
+
+	t.Run("DebugStream", func(t *testing.T) {
+		stream := gopenroutertest.NewStream().
+			Chunk(gopenroutertest.ChatChunk("gen-1", "test-model", "private chunk")).
+			Done()
+		server := httptest.NewServer(stream)
+		defer server.Close()
+
+		var buf bytes.Buffer
+		client := gopenrouter.New("secret-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithDebug(&buf),
+			gopenrouter.WithDebugRedaction(redaction),
+		)
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", nil).Build()
+		reader, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer reader.Close()
+
+		var text strings.Builder
+		for {
+			chunk, err := reader.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			text.WriteString(chunk.Text())
+		}
+		if text.String() != "private chunk" {
+			t.Errorf("Expected the stream to be unaffected, got %s", text.String())
+		}
+
+		output := buf.String()
+		if strings.Contains(output, "private chunk") {
+			t.Errorf("Expected stream content to be redacted, got:\n%s", output)
+		}
+		if !strings.Contains(output, "<-- data: [DONE]") {
+			t.Errorf("Expected the [DONE] sentinel to be kept, got:\n%s", output)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package gopenrouter
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Violation describes a request parameter with an invalid value.
+type Violation struct {
+	// Field is the JSON name of the parameter, such as "temperature" or "provider.only"
+	Field string
+	// Message describes why the value is invalid
+	Message string
+}
+
+// ValidationError reports the invalid parameters of a request, as returned by the BuildE
+// methods of the request builders.
+type ValidationError struct {
+	// Violations lists every invalid parameter found, in the order they were checked
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	violations := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		violations[i] = violation.Field + ": " + violation.Message
+	}
+	return "invalid request: " + strings.Join(violations, "; ")
+}
+
+// validator collects the violations found while checking a request.
+type validator struct {
+	violations []Violation
+}
+
+// addf records a violation of field.
+func (v *validator) addf(field, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns a *ValidationError listing the violations, or nil if there are none.
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
+
+// between checks that value, if set, is within [min, max], or (min, max] if minExclusive
+// is set.
+func (v *validator) between(field string, value *float64, min, max float64, minExclusive bool) {
+	if value == nil {
+		return
+	}
+	if minExclusive && (*value <= min || *value > max) {
+		v.addf(field, "must be in (%g, %g], got %g", min, max, *value)
+	} else if !minExclusive && (*value < min || *value > max) {
+		v.addf(field, "must be in [%g, %g], got %g", min, max, *value)
+	}
+}
+
+// atLeast checks that value, if set, is not below min.
+func (v *validator) atLeast(field string, value *int, min int) {
+	if value != nil && *value < min {
+		v.addf(field, "must be at least %d, got %d", min, *value)
+	}
+}
+
+// nonNegative checks that value, if set, is not negative.
+func (v *validator) nonNegative(field string, value *float64) {
+	if value != nil && *value < 0 {
+		v.addf(field, "must not be negative, got %g", *value)
+	}
+}
+
+// samplingParameters holds the parameters shared by completion and chat completion
+// requests.
+type samplingParameters struct {
+	provider          *ProviderOptions
+	reasoning         *ReasoningOptions
+	stream            *bool
+	streamOptions     *StreamOptions
+	maxTokens         *int
+	temperature       *float64
+	topP              *float64
+	topK              *int
+	frequencyPenalty  *float64
+	presencePenalty   *float64
+	repetitionPenalty *float64
+	topLogProbs       *int
+	minP              *float64
+	topA              *float64
+	logprobs          *bool
+}
+
+// check validates the ranges of the parameters and the options that conflict with each
+// other.
+func (v *validator) check(p samplingParameters) {
+	v.atLeast("max_tokens", p.maxTokens, 1)
+	v.between("temperature", p.temperature, 0, 2, false)
+	v.between("top_p", p.topP, 0, 1, true)
+	v.atLeast("top_k", p.topK, 1)
+	v.between("frequency_penalty", p.frequencyPenalty, -2, 2, false)
+	v.between("presence_penalty", p.presencePenalty, -2, 2, false)
+	v.between("repetition_penalty", p.repetitionPenalty, 0, 2, true)
+	v.between("min_p", p.minP, 0, 1, false)
+	v.between("top_a", p.topA, 0, 1, false)
+
+	if p.topLogProbs != nil {
+		if *p.topLogProbs < 0 || *p.topLogProbs > 20 {
+			v.addf("top_logprobs", "must be in [0, 20], got %d", *p.topLogProbs)
+		}
+		if p.logprobs == nil || !*p.logprobs {
+			v.addf("top_logprobs", "requires logprobs to be enabled")
+		}
+	}
+	if p.streamOptions != nil && p.stream != nil && !*p.stream {
+		v.addf("stream_options", "requires streaming, which is disabled")
+	}
+	if p.reasoning != nil {
+		v.atLeast("reasoning.max_tokens", p.reasoning.MaxTokens, 1)
+		if p.reasoning.Effort != "" && p.reasoning.MaxTokens != nil {
+			v.addf("reasoning", "effort and max_tokens cannot both be set")
+		}
+	}
+	if p.provider != nil {
+		v.checkProvider(p.provider, "provider.")
+	}
+}
+
+// checkProvider validates provider options, prefixing the field names with prefix.
+func (v *validator) checkProvider(options *ProviderOptions, prefix string) {
+	if options.DataCollection != "" && options.DataCollection != "allow" && options.DataCollection != "deny" {
+		v.addf(prefix+"data_collection", `must be "allow" or "deny", got %q`, options.DataCollection)
+	}
+	if options.Sort != "" && !ProviderSort(options.Sort).Valid() {
+		v.addf(prefix+"sort", "must be one of price, throughput, latency, got %q", options.Sort)
+	}
+	for _, quantization := range options.Quantizations {
+		switch quantization {
+		case QuantizationInt4, QuantizationInt8, QuantizationFP4, QuantizationFP6, QuantizationFP8,
+			QuantizationFP16, QuantizationBF16, QuantizationFP32, QuantizationUnknown:
+		default:
+			v.addf(prefix+"quantizations", "unknown quantization %q", quantization)
+		}
+	}
+	if options.MaxPrice != nil {
+		v.nonNegative(prefix+"max_price.prompt", options.MaxPrice.Prompt)
+		v.nonNegative(prefix+"max_price.completion", options.MaxPrice.Completion)
+		v.nonNegative(prefix+"max_price.image", options.MaxPrice.Image)
+		v.nonNegative(prefix+"max_price.request", options.MaxPrice.Request)
+	}
+	v.nonNegative(prefix+"preferred_min_throughput", options.PreferredMinThroughput)
+	v.nonNegative(prefix+"preferred_max_latency", options.PreferredMaxLatency)
+
+	for _, provider := range slices.Sorted(maps.Keys(options.ProviderParameters)) {
+		if providerOptionKeys()[provider] {
+			v.addf(prefix+provider, "provider parameters collide with the provider option of the same name")
+		}
+	}
+	for _, provider := range options.Only {
+		if containsProvider(options.Ignore, provider) {
+			v.addf(prefix+"ignore", "provider %s is both allowed by only and ignored", provider)
+		}
+	}
+	for _, provider := range options.Order {
+		if containsProvider(options.Ignore, provider) {
+			v.addf(prefix+"ignore", "provider %s is both ordered and ignored", provider)
+		}
+		if len(options.Only) > 0 && !containsProvider(options.Only, provider) {
+			v.addf(prefix+"order", "provider %s is not allowed by only", provider)
+		}
+	}
+}
+
+// containsProvider reports whether providers contains provider, compared case-insensitively.
+func containsProvider(providers []string, provider string) bool {
+	return slices.ContainsFunc(providers, func(name string) bool {
+		return strings.EqualFold(name, provider)
+	})
+}
@@ -0,0 +1,110 @@
+package gopenrouter_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// violationFields returns the fields of the violations of a *ValidationError.
+func violationFields(t *testing.T, err error) []string {
+	t.Helper()
+	var validationErr *gopenrouter.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	fields := make([]string, len(validationErr.Violations))
+	for i, violation := range validationErr.Violations {
+		fields[i] = violation.Field
+	}
+	return fields
+}
+
+func TestBuildE(t *testing.T) {
+	t.Run("ChatValid", func(t *testing.T) {
+		request, err := gopenrouter.NewChatCompletionRequestBuilder("test-model", nil).
+			WithTemperature(2).
+			WithTopP(1).
+			WithTopK(1).
+			WithLogprobs(true).
+			WithTopLogprobs(5).
+			BuildE()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if request.Model != "test-model" || *request.Temperature != 2 {
+			t.Errorf("Expected the built request, got %+v", request)
+		}
+	})
+
+	t.Run("ChatViolations", func(t *testing.T) {
+		provider := gopenrouter.NewProviderOptionsBuilder().
+			WithOnly([]string{"OpenAI"}).
+			WithIgnore([]string{"openai"}).
+			Build()
+		request, err := gopenrouter.NewChatCompletionRequestBuilder("test-model", nil).
+			WithTemperature(2.5).
+			WithTopP(0).
+			WithTopK(0).
+			WithTopLogprobs(3).
+			WithProvider(provider).
+			BuildE()
+		if request != nil {
+			t.Errorf("Expected no request, got %+v", request)
+		}
+
+		expected := []string{"temperature", "top_p", "top_k", "top_logprobs", "provider.ignore"}
+		if fields := violationFields(t, err); strings.Join(fields, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected violations of %v, got %v", expected, fields)
+		}
+		if !strings.Contains(err.Error(), "temperature: must be in [0, 2], got 2.5") {
+			t.Errorf("Expected the error to describe the violations, got %v", err)
+		}
+	})
+
+	t.Run("CompletionViolations", func(t *testing.T) {
+		reasoningTokens := 100
+		_, err := gopenrouter.NewCompletionRequestBuilder("test-model", "prompt").
+			WithMaxTokens(0).
+			WithRepetitionPenalty(0).
+			WithFrequencyPenalty(-3).
+			WithStream(false).
+			WithStreamIncludeUsage(true).
+			WithReasoning(&gopenrouter.ReasoningOptions{Effort: gopenrouter.EffortHigh, MaxTokens: &reasoningTokens}).
+			BuildE()
+
+		expected := []string{"max_tokens", "frequency_penalty", "repetition_penalty", "stream_options", "reasoning"}
+		if fields := violationFields(t, err); strings.Join(fields, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected violations of %v, got %v", expected, fields)
+		}
+	})
+
+	t.Run("ProviderOptions", func(t *testing.T) {
+		_, err := gopenrouter.NewProviderOptionsBuilder().
+			WithSort("fastest").
+			WithDataCollection("sometimes").
+			WithMaxPromptPrice(-1).
+			WithOrder([]string{"Anthropic"}).
+			WithOnly([]string{"OpenAI"}).
+			BuildE()
+
+		expected := []string{"data_collection", "sort", "max_price.prompt", "order"}
+		if fields := violationFields(t, err); strings.Join(fields, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected violations of %v, got %v", expected, fields)
+		}
+
+		options, err := gopenrouter.NewProviderOptionsBuilder().
+			WithSort("fastest").
+			WithSortStrategy(gopenrouter.SortPrice).
+			WithDataCollection("deny").
+			BuildE()
+		if err != nil {
+			t.Fatalf("Expected a later valid sort to replace the invalid one, got %v", err)
+		}
+		if options.Sort != "price" {
+			t.Errorf("Expected sort %q, got %q", gopenrouter.SortPrice, options.Sort)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package gopenrouter
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+const (
+	// defaultRetryInitialBackoff is the delay before the first retry if none is configured
+	defaultRetryInitialBackoff = 500 * time.Millisecond
+	// defaultRetryMaxBackoff is the maximum delay between retries if none is configured
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy configures how requests failing with retryable errors are retried.
+// The zero value uses the defaults documented on each field.
+type RetryPolicy struct {
+	// InitialBackoff is the base delay before the first retry, doubled for each further
+	// attempt. Defaults to 500ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. If the server requests a longer delay
+	// with the Retry-After header, the request is not retried. Defaults to 30s.
+	MaxBackoff time.Duration
+	// Retryable decides whether a failed attempt is retried. Defaults to IsRetryable.
+	Retryable func(err error) bool
+}
+
+// WithRetry enables automatic retries of failed requests, including the establishment
+// of streams. Requests are attempted at most maxAttempts times in total, waiting with
+// jittered exponential backoff between attempts, or as long as requested by the
+// Retry-After header of the failed response.
+//
+// Example usage:
+//
+//	client := gopenrouter.New(apiKey, gopenrouter.WithRetry(3, gopenrouter.RetryPolicy{}))
+func WithRetry(maxAttempts int, policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryAttempts = maxAttempts
+		c.retryPolicy = policy
+	}
+}
+
+// retryable reports whether a request failing with err should be retried.
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// backoff returns the delay before the given retry attempt, starting at 1, and reports
+// false if the delay requested by the server exceeds the maximum backoff.
+func (p RetryPolicy) backoff(retry int, err error) (time.Duration, bool) {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = defaultRetryInitialBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	delay := initial
+	for i := 1; i < retry && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxBackoff)
+	// Randomize the delay between half and the full value, so clients failing
+	// at the same time don't retry in lockstep
+	delay = delay/2 + rand.N(delay/2+1)
+
+	if retryAfter := retryAfterOf(err); retryAfter > 0 {
+		if retryAfter > maxBackoff {
+			return 0, false
+		}
+		delay = max(delay, retryAfter)
+	}
+	return delay, true
+}
+
+// retryAfterOf returns the delay requested by the server in the response that caused err.
+func retryAfterOf(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.RetryAfter
+	}
+	return 0
+}
+
+// do sends the request through the call middleware, retrying it according to the
+// client's retry policy. Responses with a non-2xx status code are converted to errors,
+// so a returned response always indicates success.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	return c.call(req)
+}
+
+// doWithRetries sends the request, retrying it according to the client's retry policy.
+func (c *Client) doWithRetries(req *http.Request) (*http.Response, error) {
+	// All attempts share the same idempotency key, so a request that reached the
+	// server before the connection failed is not processed twice
+	if err := c.setIdempotencyKey(req); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		c.hooks.onRequest(req, attempt)
+		c.log.request(req, attempt)
+		start := time.Now()
+		resp, err := c.doOnce(req)
+		latency := time.Since(start)
+		c.observeAttempt(req, attempt, latency, resp, err)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= c.retryAttempts || !c.retryPolicy.retryable(err) {
+			return nil, err
+		}
+
+		delay, ok := c.retryPolicy.backoff(attempt, err)
+		if !ok {
+			return nil, err
+		}
+		c.hooks.onRetry(req, attempt, delay, err)
+		c.log.retry(req, attempt, delay, err)
+
+		// The body has been consumed by the failed attempt and must be recreated
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return nil, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// observeAttempt reports a completed attempt to the hooks, the logger, and the metrics
+// receiver, and records the rate limit state reported by the response.
+func (c *Client) observeAttempt(req *http.Request, attempt int, latency time.Duration, resp *http.Response, err error) {
+	c.hooks.onResponse(req, attempt, latency, resp, err)
+	c.log.response(req, attempt, latency, resp, err)
+	c.observeRequest(req, latency, resp, err)
+	if _, header := responseStatus(resp, err); header != nil {
+		c.recordRateLimit(header)
+	}
+}
+
+// doOnce sends the request once, converting responses with a non-2xx status code to errors.
+func (c *Client) doOnce(req *http.Request) (*http.Response, error) {
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		return nil, c.handleErrorResp(resp)
+	}
+	return resp, nil
+}
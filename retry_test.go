@@ -0,0 +1,205 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestRetry(t *testing.T) {
+	policy := gopenrouter.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	t.Run("RetriesServerErrors", func(t *testing.T) {
+		var attempts atomic.Int32
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if attempts.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"error":{"code":503,"message":"Unavailable"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithRetry(3, policy))
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		response, err := client.ChatCompletion(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("ChatCompletion failed: %v", err)
+		}
+		if response.Choices[0].Message.Content != "Hi" {
+			t.Errorf("Expected content 'Hi', got '%s'", response.Choices[0].Message.Content)
+		}
+		if attempts.Load() != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+		}
+		for i, body := range bodies {
+			if body == "" || body != bodies[0] {
+				t.Errorf("Expected attempt %d to send the same request body, got '%s'", i+1, body)
+			}
+		}
+	})
+
+	t.Run("GivesUpAfterMaxAttempts", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":429,"message":"Rate limited"}}`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithRetry(2, policy))
+		_, err := client.GetCredits(context.Background())
+		if !errors.Is(err, gopenrouter.ErrRateLimited) {
+			t.Errorf("Expected ErrRateLimited, got %v", err)
+		}
+		if attempts.Load() != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts.Load())
+		}
+	})
+
+	t.Run("DoesNotRetryClientErrors", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":400,"message":"Bad request"}}`))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithRetry(3, policy))
+		if _, err := client.GetCredits(context.Background()); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if attempts.Load() != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts.Load())
+		}
+	})
+
+	t.Run("HonorsRetryAfter", func(t *testing.T) {
+		var attempts atomic.Int32
+		var first time.Time
+		var retryDelay time.Duration
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				first = time.Now()
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			retryDelay = time.Since(first)
+			_, _ = w.Write([]byte(`{"data":{"total_credits":1,"total_usage":0}}`))
+		}))
+		defer server.Close()
+
+		longPolicy := gopenrouter.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Second}
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithRetry(2, longPolicy))
+		if _, err := client.GetCredits(context.Background()); err != nil {
+			t.Fatalf("GetCredits failed: %v", err)
+		}
+		if retryDelay < time.Second {
+			t.Errorf("Expected retry to wait for Retry-After of 1s, waited %v", retryDelay)
+		}
+	})
+
+	t.Run("RetryAfterExceedsMaxBackoff", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithRetry(3, policy))
+		if _, err := client.GetCredits(context.Background()); !errors.Is(err, gopenrouter.ErrRateLimited) {
+			t.Errorf("Expected ErrRateLimited, got %v", err)
+		}
+		if attempts.Load() != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts.Load())
+		}
+	})
+
+	t.Run("CustomRetryable", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		custom := policy
+		custom.Retryable = func(err error) bool { return false }
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithRetry(3, custom))
+		if _, err := client.GetCredits(context.Background()); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if attempts.Load() != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts.Load())
+		}
+	})
+
+	t.Run("StreamEstablishment", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hello"}}]}` + "\n\n"))
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithRetry(2, policy))
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("ChatCompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		chunk, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		if *chunk.Choices[0].Delta.Content != "Hello" {
+			t.Errorf("Expected content 'Hello', got '%s'", *chunk.Choices[0].Delta.Content)
+		}
+		if attempts.Load() != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts.Load())
+		}
+	})
+
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		if _, err := client.GetCredits(context.Background()); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if attempts.Load() != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts.Load())
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package gopenrouter
+
+import (
+	"cmp"
+	"context"
+	"slices"
+)
+
+// SelectionStrategy determines the order of the models selected by SelectModels.
+type SelectionStrategy int
+
+const (
+	// SelectCheapest orders models by the sum of their prompt and completion token prices
+	SelectCheapest SelectionStrategy = iota
+	// SelectFastest orders models by their throughput, as given in ModelCriteria.Throughput,
+	// falling back to the price for models with equal or unknown throughput
+	SelectFastest
+)
+
+// ModelCriteria describes the models acceptable for a request and how to order them.
+// Zero values impose no constraint.
+type ModelCriteria struct {
+	// RequiredParameters lists the request parameters a model must support (e.g., "tools")
+	RequiredParameters []string
+	// InputModalities lists the input types a model must accept (e.g., "image")
+	InputModalities []string
+	// OutputModalities lists the output types a model must produce (e.g., "text")
+	OutputModalities []string
+	// MinContextLength is the minimum number of tokens a model must be able to process
+	MinContextLength int
+	// MaxPromptPrice is the maximum price per prompt token, in USD
+	MaxPromptPrice float64
+	// MaxCompletionPrice is the maximum price per completion token, in USD
+	MaxCompletionPrice float64
+	// Strategy determines the order of the matching models
+	Strategy SelectionStrategy
+	// Throughput maps model IDs to their measured throughput in tokens per second, used by
+	// SelectFastest; models missing from it are ordered after those with known throughput
+	Throughput map[string]float64
+	// Limit is the maximum number of models returned, or zero for all matching models
+	Limit int
+}
+
+// SelectModels returns the models meeting the criteria, best first. Models with variable
+// or invalid pricing are skipped, as their price cannot be compared.
+func SelectModels(models []ModelData, criteria ModelCriteria) []ModelData {
+	type candidate struct {
+		model ModelData
+		price float64
+	}
+
+	var candidates []candidate
+	for _, model := range models {
+		price, ok := criteria.match(model)
+		if ok {
+			candidates = append(candidates, candidate{model: model, price: price})
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if criteria.Strategy == SelectFastest {
+			ta, okA := criteria.Throughput[a.model.ID]
+			tb, okB := criteria.Throughput[b.model.ID]
+			switch {
+			case okA && !okB:
+				return -1
+			case !okA && okB:
+				return 1
+			case ta != tb:
+				return cmp.Compare(tb, ta)
+			}
+		}
+		return cmp.Compare(a.price, b.price)
+	})
+
+	if criteria.Limit > 0 && len(candidates) > criteria.Limit {
+		candidates = candidates[:criteria.Limit]
+	}
+	selected := make([]ModelData, len(candidates))
+	for i, c := range candidates {
+		selected[i] = c.model
+	}
+	return selected
+}
+
+// match reports whether the model meets the criteria, along with its combined token price.
+func (c ModelCriteria) match(model ModelData) (float64, bool) {
+	if !model.SupportsParameters(c.RequiredParameters...) {
+		return 0, false
+	}
+	for _, modality := range c.InputModalities {
+		if !slices.Contains(model.Architecture.InputModalities, modality) {
+			return 0, false
+		}
+	}
+	for _, modality := range c.OutputModalities {
+		if !slices.Contains(model.Architecture.OutputModalities, modality) {
+			return 0, false
+		}
+	}
+	if c.MinContextLength > 0 && modelContextLength(model) < c.MinContextLength {
+		return 0, false
+	}
+
+	prompt, err := parsePrice("prompt", model.Pricing.Prompt)
+	if err != nil {
+		return 0, false
+	}
+	completion, err := parsePrice("completion", model.Pricing.Completion)
+	if err != nil {
+		return 0, false
+	}
+	if (c.MaxPromptPrice > 0 && prompt > c.MaxPromptPrice) || (c.MaxCompletionPrice > 0 && completion > c.MaxCompletionPrice) {
+		return 0, false
+	}
+	return prompt + completion, true
+}
+
+// Select returns the IDs of the catalog models meeting the criteria, best first, as an
+// ordered fallback list suitable for the WithModels method of request builders. It
+// returns ErrNoMatchingModels if no model meets the criteria.
+//
+// Example usage:
+//
+//	models, err := catalog.Select(ctx, gopenrouter.ModelCriteria{
+//		RequiredParameters: []string{"tools"},
+//		MinContextLength:   32000,
+//		Limit:              3,
+//	})
+//	request := gopenrouter.NewChatCompletionRequestBuilder(models[0], messages).
+//		WithModels(models).
+//		Build()
+func (m *ModelCatalog) Select(ctx context.Context, criteria ModelCriteria) ([]string, error) {
+	models, err := m.Models(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := SelectModels(models, criteria)
+	if len(selected) == 0 {
+		return nil, ErrNoMatchingModels
+	}
+	ids := make([]string, len(selected))
+	for i, model := range selected {
+		ids[i] = model.ID
+	}
+	return ids, nil
+}
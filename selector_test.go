@@ -0,0 +1,142 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func selectorModels() []gopenrouter.ModelData {
+	length := func(n float64) *float64 { return &n }
+	return []gopenrouter.ModelData{
+		{
+			ID:                  "expensive/vision",
+			ContextLength:       length(128000),
+			Architecture:        gopenrouter.ModelArchitecture{InputModalities: []string{"text", "image"}, OutputModalities: []string{"text"}},
+			Pricing:             gopenrouter.ModelPricing{Prompt: "0.00001", Completion: "0.00003"},
+			SupportedParameters: []string{"tools", "response_format"},
+		},
+		{
+			ID:                  "cheap/small",
+			ContextLength:       length(8000),
+			Architecture:        gopenrouter.ModelArchitecture{InputModalities: []string{"text"}, OutputModalities: []string{"text"}},
+			Pricing:             gopenrouter.ModelPricing{Prompt: "0.0000001", Completion: "0.0000002"},
+			SupportedParameters: []string{"tools"},
+		},
+		{
+			ID:                  "mid/large",
+			ContextLength:       length(200000),
+			Architecture:        gopenrouter.ModelArchitecture{InputModalities: []string{"text"}, OutputModalities: []string{"text"}},
+			Pricing:             gopenrouter.ModelPricing{Prompt: "0.000003", Completion: "0.000015"},
+			SupportedParameters: []string{"tools", "response_format"},
+		},
+		{
+			ID:                  "router/auto",
+			ContextLength:       length(2000000),
+			Pricing:             gopenrouter.ModelPricing{Prompt: "-1", Completion: "-1"},
+			SupportedParameters: []string{"tools", "response_format"},
+		},
+	}
+}
+
+func selectedIDs(models []gopenrouter.ModelData) []string {
+	ids := make([]string, len(models))
+	for i, model := range models {
+		ids[i] = model.ID
+	}
+	return ids
+}
+
+func TestSelectModels(t *testing.T) {
+	cases := []struct {
+		name     string
+		criteria gopenrouter.ModelCriteria
+		expect   []string
+	}{
+		{
+			name:     "CheapestFirst",
+			criteria: gopenrouter.ModelCriteria{},
+			expect:   []string{"cheap/small", "mid/large", "expensive/vision"},
+		},
+		{
+			name:     "RequiredParameters",
+			criteria: gopenrouter.ModelCriteria{RequiredParameters: []string{"response_format"}},
+			expect:   []string{"mid/large", "expensive/vision"},
+		},
+		{
+			name:     "InputModalities",
+			criteria: gopenrouter.ModelCriteria{InputModalities: []string{"image"}},
+			expect:   []string{"expensive/vision"},
+		},
+		{
+			name:     "MinContextLength",
+			criteria: gopenrouter.ModelCriteria{MinContextLength: 100000},
+			expect:   []string{"mid/large", "expensive/vision"},
+		},
+		{
+			name:     "MaxPrice",
+			criteria: gopenrouter.ModelCriteria{MaxPromptPrice: 0.000005, MaxCompletionPrice: 0.00002},
+			expect:   []string{"cheap/small", "mid/large"},
+		},
+		{
+			name: "Fastest",
+			criteria: gopenrouter.ModelCriteria{
+				Strategy:   gopenrouter.SelectFastest,
+				Throughput: map[string]float64{"expensive/vision": 120, "mid/large": 60},
+			},
+			expect: []string{"expensive/vision", "mid/large", "cheap/small"},
+		},
+		{
+			name:     "Limit",
+			criteria: gopenrouter.ModelCriteria{Limit: 2},
+			expect:   []string{"cheap/small", "mid/large"},
+		},
+		{
+			name:     "NoMatch",
+			criteria: gopenrouter.ModelCriteria{OutputModalities: []string{"image"}},
+			expect:   []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := selectedIDs(gopenrouter.SelectModels(selectorModels(), tc.criteria))
+			if !reflect.DeepEqual(got, tc.expect) {
+				t.Errorf("Expected %v, got %v", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestModelCatalogSelect(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"data": [
+			{"id": "a/expensive", "pricing": {"prompt": "0.00001", "completion": "0.00002"}, "supported_parameters": ["tools"]},
+			{"id": "b/cheap", "pricing": {"prompt": "0.000001", "completion": "0.000002"}, "supported_parameters": ["tools"]},
+			{"id": "c/no-tools", "pricing": {"prompt": "0", "completion": "0"}}
+		]}`)
+	}))
+	defer ts.Close()
+
+	catalog := gopenrouter.NewModelCatalog(gopenrouter.New("test-key", gopenrouter.WithBaseURL(ts.URL)))
+
+	ids, err := catalog.Select(context.Background(), gopenrouter.ModelCriteria{RequiredParameters: []string{"tools"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"b/cheap", "a/expensive"}) {
+		t.Errorf("Expected [b/cheap a/expensive], got %v", ids)
+	}
+
+	_, err = catalog.Select(context.Background(), gopenrouter.ModelCriteria{RequiredParameters: []string{"logprobs"}})
+	if !errors.Is(err, gopenrouter.ErrNoMatchingModels) {
+		t.Errorf("Expected ErrNoMatchingModels, got %v", err)
+	}
+}
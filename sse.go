@@ -0,0 +1,108 @@
+package gopenrouter
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+)
+
+// scanBufferPool holds the line buffers of finished parsers for reuse by new ones, so
+// that opening a stream does not allocate a fresh buffer.
+var scanBufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 4096)
+		return &buf
+	},
+}
+
+// SSEEvent is a data event read from a server-sent event stream.
+type SSEEvent struct {
+	// ID is the value of the most recent id field of the stream, used to resume it
+	ID string
+	// Data is the payload of the data field
+	Data []byte
+}
+
+// Done reports whether the event is the [DONE] sentinel ending OpenRouter streams.
+func (e SSEEvent) Done() bool {
+	return string(e.Data) == "[DONE]"
+}
+
+// SSEParser reads the data events of a server-sent event stream, such as the body of a
+// streaming completion response. It is used by the stream readers of the client, and can
+// be used on its own to decode recorded streams.
+//
+// As OpenRouter sends each chunk in a single data field, every data field is returned as
+// an event of its own, without waiting for the blank line ending the event. Data fields
+// with an empty payload and fields other than data and id are ignored.
+type SSEParser struct {
+	// LastEventID is the value of the most recent id field. It can be set before reading
+	// to carry the ID of an interrupted stream over to the stream resuming it.
+	LastEventID string
+	// OnComment is called, if set, with the text of comment lines, such as the keep-alive
+	// comments sent by OpenRouter while a request is processed.
+	OnComment func(comment string)
+
+	scanner *bufio.Scanner
+	buf     *[]byte
+}
+
+// NewSSEParser returns a parser reading a server-sent event stream from r.
+func NewSSEParser(r io.Reader) *SSEParser {
+	buf := scanBufferPool.Get().(*[]byte)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(*buf, bufio.MaxScanTokenSize)
+	return &SSEParser{scanner: scanner, buf: buf}
+}
+
+// Next returns the next data event of the stream. It returns io.EOF once r is exhausted,
+// or the error that interrupted reading, such as bufio.ErrTooLong for overlong lines.
+func (p *SSEParser) Next() (SSEEvent, error) {
+	for p.scanner.Scan() {
+		line := bytes.TrimSpace(p.scanner.Bytes())
+
+		// Skip empty lines
+		if len(line) == 0 {
+			continue
+		}
+
+		// Report comments, such as OpenRouter's processing keep-alives
+		if comment, ok := bytes.CutPrefix(line, []byte(":")); ok {
+			if p.OnComment != nil {
+				p.OnComment(string(bytes.TrimSpace(comment)))
+			}
+			continue
+		}
+
+		// Track event IDs so an interrupted stream can be resumed
+		if id, ok := bytes.CutPrefix(line, []byte("id:")); ok {
+			p.LastEventID = string(bytes.TrimSpace(id))
+			continue
+		}
+
+		if data, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+			data = bytes.TrimPrefix(data, []byte(" "))
+			if len(data) == 0 {
+				continue
+			}
+			return SSEEvent{ID: p.LastEventID, Data: bytes.Clone(data)}, nil
+		}
+	}
+
+	// The scanner no longer reads into its buffer once it has stopped
+	p.release()
+	if err := p.scanner.Err(); err != nil {
+		return SSEEvent{}, err
+	}
+	return SSEEvent{}, io.EOF
+}
+
+// release returns the line buffer of the parser to the pool. Buffers the scanner grew
+// for long lines are separate allocations left to the garbage collector.
+func (p *SSEParser) release() {
+	if p.buf != nil {
+		scanBufferPool.Put(p.buf)
+		p.buf = nil
+	}
+}
@@ -0,0 +1,145 @@
+package gopenrouter_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// parseAll returns the data events of a stream and the error ending it.
+func parseAll(parser *gopenrouter.SSEParser) ([]gopenrouter.SSEEvent, error) {
+	var events []gopenrouter.SSEEvent
+	for {
+		event, err := parser.Next()
+		if err != nil {
+			return events, err
+		}
+		events = append(events, event)
+	}
+}
+
+func TestSSEParser(t *testing.T) {
+	t.Run("Events", func(t *testing.T) {
+		input := ": OPENROUTER PROCESSING\n\n" +
+			"id: 1\r\ndata: {\"id\":\"gen-1\"}\r\n\r\n" +
+			"event: message\nretry: 100\ndata:{\"id\":\"gen-2\"}\n" +
+			"data: \n\n" +
+			"id:2\ndata: [DONE]\n"
+
+		var comments []string
+		parser := gopenrouter.NewSSEParser(strings.NewReader(input))
+		parser.OnComment = func(comment string) {
+			comments = append(comments, comment)
+		}
+		events, err := parseAll(parser)
+		if err != io.EOF {
+			t.Fatalf("Expected io.EOF, got %v", err)
+		}
+
+		expected := []gopenrouter.SSEEvent{
+			{ID: "1", Data: []byte(`{"id":"gen-1"}`)},
+			{ID: "1", Data: []byte(`{"id":"gen-2"}`)},
+			{ID: "2", Data: []byte(`[DONE]`)},
+		}
+		if len(events) != len(expected) {
+			t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+		}
+		for i, event := range events {
+			if event.ID != expected[i].ID || !bytes.Equal(event.Data, expected[i].Data) {
+				t.Errorf("Expected event %d to be %s (id %s), got %s (id %s)", i, expected[i].Data, expected[i].ID, event.Data, event.ID)
+			}
+		}
+		if !events[2].Done() || events[0].Done() {
+			t.Error("Expected only the last event to be the [DONE] sentinel")
+		}
+		if len(comments) != 1 || comments[0] != "OPENROUTER PROCESSING" {
+			t.Errorf("Expected the processing comment, got %v", comments)
+		}
+	})
+
+	t.Run("LastEventID", func(t *testing.T) {
+		parser := gopenrouter.NewSSEParser(strings.NewReader("data: {}\n\n"))
+		parser.LastEventID = "7"
+		event, err := parser.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if event.ID != "7" {
+			t.Errorf("Expected the carried over event ID 7, got %s", event.ID)
+		}
+	})
+
+	t.Run("LineTooLong", func(t *testing.T) {
+		input := "data: " + strings.Repeat("x", bufio.MaxScanTokenSize) + "\n\n"
+		_, err := parseAll(gopenrouter.NewSSEParser(strings.NewReader(input)))
+		if !errors.Is(err, bufio.ErrTooLong) {
+			t.Errorf("Expected bufio.ErrTooLong, got %v", err)
+		}
+	})
+
+	t.Run("ReadError", func(t *testing.T) {
+		readErr := errors.New("connection reset")
+		reader := io.MultiReader(strings.NewReader("data: {}\n\n"), &failingReader{err: readErr})
+		events, err := parseAll(gopenrouter.NewSSEParser(reader))
+		if len(events) != 1 || !errors.Is(err, readErr) {
+			t.Errorf("Expected 1 event and the read error, got %d events and %v", len(events), err)
+		}
+	})
+}
+
+// failingReader fails every read with err.
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func FuzzSSEParser(f *testing.F) {
+	f.Add([]byte("data: {\"id\":\"gen-1\"}\n\ndata: [DONE]\n\n"))
+	f.Add([]byte(": OPENROUTER PROCESSING\n\nid: 1\ndata: {}\n\n"))
+	f.Add([]byte("id:\r\ndata:\r\n\r\nevent: x\ndata:  \t{\"a\":\n\"b\"}\n"))
+	f.Add([]byte("data: {\"error\":{\"code\":502,\"message\":\"Provider returned error\"}}\n\n"))
+	f.Add([]byte("\x00data: \xff\xfe\n:\n\n\n"))
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		events, err := parseAll(gopenrouter.NewSSEParser(bytes.NewReader(input)))
+		if err != io.EOF && !errors.Is(err, bufio.ErrTooLong) {
+			t.Fatalf("Expected io.EOF or bufio.ErrTooLong, got %v", err)
+		}
+
+		// Re-encoding the parsed events must yield the same events
+		var encoded bytes.Buffer
+		for _, event := range events {
+			if len(event.Data) == 0 || bytes.ContainsAny(event.Data, "\n") {
+				t.Fatalf("Expected a non-empty single-line payload, got %q", event.Data)
+			}
+			if strings.ContainsAny(event.ID, "\n") || strings.TrimSpace(event.ID) != event.ID {
+				t.Fatalf("Expected a trimmed single-line ID, got %q", event.ID)
+			}
+			encoded.WriteString("id: " + event.ID + "\n")
+			encoded.WriteString("data: ")
+			encoded.Write(event.Data)
+			encoded.WriteString("\n\n")
+		}
+
+		reparsed, err := parseAll(gopenrouter.NewSSEParser(&encoded))
+		if err != io.EOF {
+			t.Fatalf("Expected io.EOF re-parsing the events, got %v", err)
+		}
+		if len(reparsed) != len(events) {
+			t.Fatalf("Expected %d re-parsed events, got %d", len(events), len(reparsed))
+		}
+		for i := range events {
+			if reparsed[i].ID != events[i].ID || !bytes.Equal(reparsed[i].Data, events[i].Data) {
+				t.Fatalf("Expected re-parsed event %q (id %q), got %q (id %q)", events[i].Data, events[i].ID, reparsed[i].Data, reparsed[i].ID)
+			}
+		}
+	})
+}
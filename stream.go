@@ -0,0 +1,792 @@
+package gopenrouter
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// streamReconnectBackoff is the delay before the first reconnection attempt.
+	// Subsequent attempts double the delay.
+	streamReconnectBackoff = 250 * time.Millisecond
+)
+
+// StreamReader is the interface implemented by ChatCompletionStreamReader and
+// CompletionStreamReader. It allows helpers to operate on either kind of stream.
+type StreamReader[T any] interface {
+	// Recv reads the next chunk, returning io.EOF when the stream has finished
+	Recv() (T, error)
+	// Close closes the stream and releases the underlying connection
+	Close() error
+}
+
+// StreamBufferPolicy determines what happens when the read-ahead buffer of a stream is full.
+type StreamBufferPolicy int
+
+const (
+	// StreamBufferBlock pauses reading from the connection until the consumer catches up
+	StreamBufferBlock StreamBufferPolicy = iota
+
+	// StreamBufferDropOldest discards the oldest buffered chunk to make room for new ones,
+	// so the connection is always drained at the rate the server sends data
+	StreamBufferDropOldest
+)
+
+// streamEvent is a single data payload read from a stream, or the error that ended it.
+type streamEvent struct {
+	data []byte
+	// id is the most recent SSE id field at the time the event was read
+	id  string
+	err error
+	// done is set on the io.EOF event produced by the [DONE] sentinel
+	done bool
+}
+
+// streamReader implements the Server-Sent Events decoding shared by
+// ChatCompletionStreamReader and CompletionStreamReader.
+type streamReader[T any] struct {
+	ctx context.Context
+
+	// mu guards the connection state, which may be replaced by a reconnect running
+	// on the read-ahead goroutine while the consumer closes the stream
+	mu       sync.Mutex
+	response *http.Response
+	watchdog *idleWatchdog
+	closed   bool
+
+	// Connection reading state, owned by whichever goroutine reads events
+	parser *SSEParser
+	// eventID holds the value of the most recently read SSE id field
+	eventID string
+	// done is set once the [DONE] sentinel has been read
+	done bool
+	// reconnect re-establishes the stream, resuming after the given event ID
+	reconnect func(ctx context.Context, lastEventID string) (*http.Response, error)
+	// reconnectsLeft is the number of reconnection attempts still available
+	reconnectsLeft int
+	// idleTimeout aborts the stream if no data arrives for the given duration
+	idleTimeout time.Duration
+	// onComment is called for SSE comment lines
+	onComment func(comment string)
+
+	// buffer holds events read ahead of the consumer, if read-ahead is enabled
+	buffer *eventBuffer
+	// metrics records timing information about the stream
+	metrics *streamMetrics
+	// onDecodeError is called for chunks that cannot be decoded
+	onDecodeError func(raw []byte, err error)
+	// onChunk is called for each data chunk returned to the consumer
+	onChunk func(ctx context.Context, info StreamChunkInfo)
+	// onUsage is called once the stream ends or is closed, with the last usage reported
+	// by its chunks, or nil if none
+	onUsage func(ctx context.Context, id, model, provider string, usage *Usage)
+	// usage holds the usage of the stream for onUsage, updated by the decoded chunks
+	usage atomic.Pointer[streamUsage]
+	// usageReported is set once onUsage has been called
+	usageReported atomic.Bool
+	// onEnd is called once when the consumer reaches the end of the stream, or when the
+	// stream is closed before its end
+	onEnd func(ctx context.Context, info StreamEndInfo)
+	// endReported is set once onEnd has been called
+	endReported atomic.Bool
+	// onGeneration is called with the generation ID once the stream finishes cleanly
+	onGeneration func(ctx context.Context, id string)
+
+	// Consumer state
+	// pending delivers the result of a read abandoned by RecvContext, so the next
+	// read picks up where it left off instead of racing the still-running read
+	pending chan streamEvent
+	// lastRaw holds the data payload of the most recently received chunk
+	lastRaw []byte
+	// lastEventID holds the SSE id of the most recently received chunk
+	lastEventID string
+	// err is the terminal error returned for all reads once the stream has ended
+	err error
+	// finished is set once the consumer has received the [DONE] sentinel
+	finished bool
+	// skipped counts the chunks that could not be decoded
+	skipped int
+	// received counts the data chunks returned to the consumer
+	received int
+	// generationID holds the generation ID reported by the decoded chunks
+	generationID string
+	// endpoint is the path of the streaming endpoint
+	endpoint string
+	// requestedModel is the model of the request that opened the stream
+	requestedModel string
+	// model and provider hold the origin of the stream, as reported by the decoded chunks
+	model    string
+	provider string
+}
+
+// newStreamReader creates a stream reader decoding events from the response body.
+func newStreamReader[T any](response *http.Response) *streamReader[T] {
+	ctx := context.Background()
+	if response.Request != nil {
+		ctx = response.Request.Context()
+	}
+	s := &streamReader[T]{
+		ctx:     ctx,
+		metrics: newStreamMetrics(time.Now()),
+	}
+	s.setResponse(response)
+	return s
+}
+
+// setResponse starts reading events from the given response, arming the idle
+// watchdog when an idle timeout is configured. The caller must hold s.mu or
+// have exclusive access to the reader.
+func (s *streamReader[T]) setResponse(response *http.Response) {
+	if s.watchdog != nil {
+		s.watchdog.stop()
+		s.watchdog = nil
+	}
+
+	body := response.Body
+	if s.idleTimeout > 0 && body != nil {
+		s.watchdog = newIdleWatchdog(body, s.idleTimeout)
+		body = s.watchdog
+	}
+
+	s.response = response
+	s.parser = NewSSEParser(body)
+	s.parser.LastEventID = s.eventID
+	s.parser.OnComment = func(comment string) {
+		if s.onComment != nil {
+			s.onComment(comment)
+		}
+	}
+}
+
+// newClientStream sends a streaming request for model to the given endpoint and returns
+// a reader configured with the client's streaming options. The call options are applied
+// to the initial request and to every reconnection attempt.
+func newClientStream[T any](ctx context.Context, c *Client, urlSuffix, model string, body any, opts []CallOption) (*streamReader[T], error) {
+	start := time.Now()
+	encoded, err := encodeBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if c.streamReconnects > 0 {
+		// Reconnections resume the same generation, so they share its idempotency key
+		if ctx, err = c.contextWithStreamKey(ctx); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := c.openStream(ctx, urlSuffix, encodedBody(encoded), "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &streamReader[T]{
+		ctx:            ctx,
+		endpoint:       urlSuffix,
+		model:          model,
+		requestedModel: model,
+		idleTimeout:    c.streamIdleTimeout,
+		metrics:        newStreamMetrics(start),
+		onDecodeError:  c.onDecodeError,
+		onComment:      c.onComment,
+		onChunk:        c.hooks.OnStreamChunk,
+	}
+	if c.observesStreamEnd() {
+		stream.onEnd = c.observeStreamEnd
+	}
+	if c.metrics != nil || c.budget != nil || c.usageTracker != nil {
+		stream.onUsage = c.observeStreamUsage
+	}
+	if c.enricher != nil {
+		stream.onGeneration = c.enrichGeneration
+	}
+	stream.setResponse(resp)
+	if c.streamReconnects > 0 {
+		stream.reconnectsLeft = c.streamReconnects
+		stream.reconnect = func(ctx context.Context, lastEventID string) (*http.Response, error) {
+			return c.openStream(ctx, urlSuffix, encodedBody(encoded), lastEventID, opts)
+		}
+	}
+	if c.streamBufferSize > 0 {
+		stream.startReadAhead(c.streamBufferSize, c.streamBufferPolicy)
+	}
+	return stream, nil
+}
+
+// openStream sends a streaming request and returns the response once the server has
+// accepted it. A non-empty lastEventID is sent in the Last-Event-ID header so the
+// server can resume an interrupted stream.
+func (c *Client) openStream(ctx context.Context, urlSuffix string, body any, lastEventID string, opts []CallOption) (*http.Response, error) {
+	ctx, establish := c.withEstablishTimeout(ctx)
+
+	req, err := c.newRequest(
+		ctx,
+		http.MethodPost,
+		c.fullURL(urlSuffix),
+		withBody(body),
+		withCallOptions(opts),
+	)
+	if err != nil {
+		establish.cancel(nil)
+		return nil, err
+	}
+
+	// Set accept header for streaming
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := c.do(req)
+	if !establish.stop() {
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		establish.cancel(nil)
+		return nil, fmt.Errorf("error establishing stream: %w", context.DeadlineExceeded)
+	}
+	if err != nil {
+		establish.cancel(nil)
+		return nil, err
+	}
+
+	// Release the request context once the stream is closed
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: establish.cancel}
+	return resp, nil
+}
+
+// establishTimeout aborts a streaming request that is not established within the
+// client's request timeout.
+type establishTimeout struct {
+	timer  *time.Timer
+	cancel context.CancelCauseFunc
+}
+
+// withEstablishTimeout returns a context that is cancelled if the stream is not established
+// within the request timeout, unless ctx already has a deadline.
+func (c *Client) withEstablishTimeout(ctx context.Context) (context.Context, *establishTimeout) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	establish := &establishTimeout{cancel: cancel}
+	if _, ok := ctx.Deadline(); c.requestTimeout > 0 && !ok {
+		establish.timer = time.AfterFunc(c.requestTimeout, func() {
+			cancel(context.DeadlineExceeded)
+		})
+	}
+	return ctx, establish
+}
+
+// stop disarms the timeout, reporting false if it has already expired.
+func (e *establishTimeout) stop() bool {
+	return e.timer == nil || e.timer.Stop()
+}
+
+// cancelOnClose cancels the request context when the response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelCauseFunc
+}
+
+// Close closes the response body and releases the request context.
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel(nil)
+	return err
+}
+
+// recv reads the next event from the stream and decodes it into T.
+// Malformed chunks are skipped and reported to the decode error callback. If ctx is done before a chunk arrives, recv returns
+// ctx.Err() and the stream remains usable.
+func (s *streamReader[T]) recv(ctx context.Context) (T, error) {
+	var response T
+	if err := s.recvInto(ctx, &response); err != nil {
+		var zero T
+		return zero, err
+	}
+	return response, nil
+}
+
+// recvInto is like recv, but decodes the next chunk into response, which is reset before
+// each chunk is decoded. Reusing response across calls avoids allocating its choices for
+// every chunk.
+func (s *streamReader[T]) recvInto(ctx context.Context, response *T) error {
+	for {
+		data, err := s.recvRaw(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Parse JSON chunk
+		resetChunk(response)
+		if err := json.Unmarshal(data, response); err != nil {
+			// Skip malformed chunks
+			s.skipped++
+			if s.onDecodeError != nil {
+				s.onDecodeError(data, err)
+			}
+			continue
+		}
+
+		if origin, ok := any(response).(originReporter); ok {
+			model, provider := origin.origin()
+			s.model = cmp.Or(model, s.model)
+			s.provider = cmp.Or(provider, s.provider)
+		}
+		var usage *Usage
+		if reporter, ok := any(response).(usageReporter); ok {
+			usage = reporter.usage()
+			s.metrics.recordUsage(usage)
+		}
+		first := false
+		if generation, ok := any(response).(generationReporter); ok && s.generationID == "" {
+			s.generationID = generation.generationID()
+			first = s.generationID != ""
+		}
+		if (s.onUsage != nil || s.onEnd != nil) && (first || usage != nil) {
+			s.storeUsage(usage)
+		}
+
+		return nil
+	}
+}
+
+// streamUsage is the usage of a stream along with its origin, as reported to onUsage.
+type streamUsage struct {
+	id       string
+	model    string
+	provider string
+	usage    *Usage
+}
+
+// storeUsage records the origin of the stream and, if not nil, its latest usage, so they
+// can be reported once the stream ends even if it is closed from another goroutine.
+func (s *streamReader[T]) storeUsage(usage *Usage) {
+	current := streamUsage{id: s.generationID, model: s.model, provider: s.provider}
+	if usage != nil {
+		copied := *usage
+		current.usage = &copied
+	} else if previous := s.usage.Load(); previous != nil {
+		current.usage = previous.usage
+	}
+	s.usage.Store(&current)
+}
+
+// reportUsage passes the usage of the stream to onUsage, once. Streams may repeat their
+// usage, for example in a chunk and again after a reconnect, so only the last usage is
+// reported to have the cost of the stream counted once.
+func (s *streamReader[T]) reportUsage() {
+	if s.onUsage == nil || !s.usageReported.CompareAndSwap(false, true) {
+		return
+	}
+	if current := s.usage.Load(); current != nil {
+		s.onUsage(s.ctx, current.id, current.model, current.provider, current.usage)
+	}
+}
+
+// reportEnd passes info to onEnd, once.
+func (s *streamReader[T]) reportEnd(info StreamEndInfo) {
+	if s.onEnd == nil || !s.endReported.CompareAndSwap(false, true) {
+		return
+	}
+	s.onEnd(s.ctx, info)
+}
+
+// reportClosed reports the end of a stream closed before the consumer reached its end,
+// with ErrStreamClosed. Its origin is taken from the chunks stored for onUsage, as the
+// consumer may still be reading from another goroutine.
+func (s *streamReader[T]) reportClosed() {
+	if s.onEnd == nil || s.endReported.Load() {
+		return
+	}
+	info := StreamEndInfo{
+		Endpoint: s.endpoint,
+		Model:    s.requestedModel,
+		Metrics:  s.metrics.snapshot(),
+		Err:      ErrStreamClosed,
+	}
+	if current := s.usage.Load(); current != nil {
+		info.Model = cmp.Or(current.model, info.Model)
+		info.Provider = current.provider
+	}
+	s.reportEnd(info)
+}
+
+// chunkResetter is implemented by stream chunks that can be reset for reuse while keeping
+// the memory of their choices.
+type chunkResetter interface {
+	reset()
+}
+
+// resetChunk zeroes chunk before it is decoded into again, as json.Unmarshal keeps the
+// fields absent from the JSON payload.
+func resetChunk[T any](chunk *T) {
+	if resetter, ok := any(chunk).(chunkResetter); ok {
+		resetter.reset()
+		return
+	}
+	var zero T
+	*chunk = zero
+}
+
+func (r *ChatCompletionStreamResponse) reset() {
+	choices := r.Choices[:0]
+	clear(choices[:cap(choices)])
+	*r = ChatCompletionStreamResponse{Choices: choices}
+}
+
+func (r *CompletionStreamResponse) reset() {
+	choices := r.Choices[:0]
+	clear(choices[:cap(choices)])
+	*r = CompletionStreamResponse{Choices: choices}
+}
+
+// recvRaw returns the next data payload from the stream without decoding it.
+func (s *streamReader[T]) recvRaw(ctx context.Context) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	event, err := s.nextEvent(ctx)
+	if err != nil {
+		// The read was abandoned; the stream itself has not failed
+		return nil, err
+	}
+
+	s.lastEventID = event.id
+	if event.err != nil {
+		s.err = event.err
+		s.finished = event.done
+		if s.onEnd != nil {
+			info := StreamEndInfo{
+				Endpoint: s.endpoint,
+				Model:    s.model,
+				Provider: s.provider,
+				Metrics:  s.metrics.snapshot(),
+			}
+			if event.err != io.EOF {
+				info.Err = event.err
+			}
+			s.reportEnd(info)
+		}
+		s.reportUsage()
+		if event.err == io.EOF && s.onGeneration != nil && s.generationID != "" {
+			s.onGeneration(s.ctx, s.generationID)
+		}
+		return nil, event.err
+	}
+
+	s.lastRaw = event.data
+	s.received++
+	if s.onChunk != nil {
+		s.onChunk(s.ctx, StreamChunkInfo{
+			Chunk:   s.received,
+			Elapsed: time.Since(s.metrics.start),
+			Raw:     event.data,
+		})
+	}
+	return event.data, nil
+}
+
+// nextEvent waits for the next event until ctx is done. A read interrupted by ctx keeps
+// running in the background and its result is returned by the following call.
+func (s *streamReader[T]) nextEvent(ctx context.Context) (streamEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return streamEvent{}, err
+	}
+
+	if s.pending == nil {
+		if ctx.Done() == nil {
+			// The read cannot be interrupted, so no goroutine is needed
+			return s.readNext(), nil
+		}
+		pending := make(chan streamEvent, 1)
+		go func() {
+			pending <- s.readNext()
+		}()
+		s.pending = pending
+	}
+
+	select {
+	case event := <-s.pending:
+		s.pending = nil
+		return event, nil
+	case <-ctx.Done():
+		return streamEvent{}, ctx.Err()
+	}
+}
+
+// readNext returns the next event from the read-ahead buffer or the connection.
+func (s *streamReader[T]) readNext() streamEvent {
+	if s.buffer != nil {
+		return s.buffer.pop()
+	}
+	return s.readEvent()
+}
+
+// readEvent reads lines from the connection until the next data payload or the end of the stream.
+func (s *streamReader[T]) readEvent() streamEvent {
+	if s.done {
+		return streamEvent{id: s.eventID, err: io.EOF, done: true}
+	}
+
+	for {
+		event, readErr := s.parser.Next()
+		s.eventID = s.parser.LastEventID
+		if readErr != nil {
+			err := s.readError(readErr)
+			if err == nil {
+				// Resume reading from the re-established connection
+				continue
+			}
+			s.metrics.finish(time.Now())
+			return streamEvent{id: s.eventID, err: err}
+		}
+
+		// Check for stream end
+		if event.Done() {
+			s.done = true
+			s.metrics.finish(time.Now())
+			return streamEvent{id: s.eventID, err: io.EOF, done: true}
+		}
+
+		s.metrics.recordChunk(time.Now())
+		return streamEvent{data: event.Data, id: s.eventID}
+	}
+}
+
+// readError determines the error to report once the connection stops yielding events,
+// given the error returned by the parser. It returns nil if the stream was successfully
+// re-established.
+func (s *streamReader[T]) readError(readErr error) error {
+	// Report cancellation of the request context rather than the transport
+	// error it caused, so callers can tell user cancellation from failures
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	if readErr == io.EOF {
+		readErr = nil
+	}
+	s.mu.Lock()
+	stalled := s.watchdog != nil && s.watchdog.stalled.Load()
+	s.mu.Unlock()
+
+	if s.tryReconnect() {
+		return nil
+	}
+	if stalled {
+		return ErrStreamStalled
+	}
+	if readErr != nil {
+		return fmt.Errorf("error reading stream: %w", readErr)
+	}
+	return ErrStreamTruncated
+}
+
+// tryReconnect re-establishes an interrupted stream using the last received event ID.
+// It reports whether a new connection was opened.
+func (s *streamReader[T]) tryReconnect() bool {
+	if s.reconnect == nil || s.eventID == "" {
+		return false
+	}
+
+	backoff := streamReconnectBackoff
+	for s.reconnectsLeft > 0 {
+		s.reconnectsLeft--
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+		backoff *= 2
+
+		s.mu.Lock()
+		closed := s.closed
+		s.mu.Unlock()
+		if closed {
+			return false
+		}
+
+		resp, err := s.reconnect(s.ctx, s.eventID)
+		if err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = resp.Body.Close()
+			return false
+		}
+		_ = s.response.Body.Close()
+		s.setResponse(resp)
+		s.mu.Unlock()
+		return true
+	}
+	return false
+}
+
+// close closes the underlying response body and stops the read-ahead goroutine.
+func (s *streamReader[T]) close() error {
+	defer s.reportUsage()
+	defer s.reportClosed()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed && s.buffer != nil {
+		close(s.buffer.stop)
+	}
+	s.closed = true
+	s.metrics.finish(time.Now())
+	if s.watchdog != nil {
+		s.watchdog.stop()
+	}
+	if s.response != nil && s.response.Body != nil {
+		return s.response.Body.Close()
+	}
+	return nil
+}
+
+// startReadAhead starts a goroutine that reads events from the connection into a
+// bounded buffer, so the connection keeps being drained while the consumer is busy.
+func (s *streamReader[T]) startReadAhead(size int, policy StreamBufferPolicy) {
+	s.buffer = &eventBuffer{
+		events:     make(chan streamEvent, size),
+		stop:       make(chan struct{}),
+		dropOldest: policy == StreamBufferDropOldest,
+	}
+
+	go func() {
+		for {
+			event := s.readEvent()
+			if !s.buffer.push(event) || event.err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// header returns the headers of the current streaming response.
+func (s *streamReader[T]) header() http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.response == nil {
+		return nil
+	}
+	return s.response.Header
+}
+
+// droppedChunks returns the number of chunks discarded by a full read-ahead buffer.
+func (s *streamReader[T]) droppedChunks() int {
+	if s.buffer == nil {
+		return 0
+	}
+	return int(s.buffer.dropped.Load())
+}
+
+// eventBuffer is a bounded queue of events read ahead of the consumer.
+type eventBuffer struct {
+	events     chan streamEvent
+	stop       chan struct{}
+	dropOldest bool
+	dropped    atomic.Int64
+}
+
+// push adds an event to the buffer, either waiting for space or discarding the
+// oldest event depending on the policy. It reports false if the stream was closed.
+func (b *eventBuffer) push(event streamEvent) bool {
+	for {
+		select {
+		case b.events <- event:
+			return true
+		case <-b.stop:
+			return false
+		default:
+		}
+
+		if !b.dropOldest {
+			select {
+			case b.events <- event:
+				return true
+			case <-b.stop:
+				return false
+			}
+		}
+
+		select {
+		case <-b.events:
+			b.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// pop returns the next buffered event, waiting for one to arrive if necessary.
+func (b *eventBuffer) pop() streamEvent {
+	select {
+	case <-b.stop:
+		return streamEvent{err: fmt.Errorf("error reading stream: %w", http.ErrBodyReadAfterClose)}
+	default:
+	}
+
+	select {
+	case event := <-b.events:
+		return event
+	case <-b.stop:
+		return streamEvent{err: fmt.Errorf("error reading stream: %w", http.ErrBodyReadAfterClose)}
+	}
+}
+
+// idleWatchdog wraps a response body and closes it when a read blocks for longer
+// than the configured timeout, unblocking readers stuck on a dead connection.
+// The timer only runs while a read is in progress, so slow consumers are not
+// mistaken for stalled connections.
+type idleWatchdog struct {
+	body    io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	stalled atomic.Bool
+}
+
+// newIdleWatchdog wraps body with a disarmed idle timer.
+func newIdleWatchdog(body io.ReadCloser, timeout time.Duration) *idleWatchdog {
+	w := &idleWatchdog{
+		body:    body,
+		timeout: timeout,
+	}
+	w.timer = time.AfterFunc(timeout, func() {
+		w.stalled.Store(true)
+		_ = w.body.Close()
+	})
+	w.timer.Stop()
+	return w
+}
+
+// Read reads from the wrapped body, aborting the read if no data arrives within the timeout.
+func (w *idleWatchdog) Read(p []byte) (int, error) {
+	w.timer.Reset(w.timeout)
+	n, err := w.body.Read(p)
+	w.timer.Stop()
+	return n, err
+}
+
+// Close stops the idle timer and closes the wrapped body.
+func (w *idleWatchdog) Close() error {
+	w.stop()
+	return w.body.Close()
+}
+
+// stop disarms the idle timer.
+func (w *idleWatchdog) stop() {
+	w.timer.Stop()
+}
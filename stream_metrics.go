@@ -0,0 +1,147 @@
+package gopenrouter
+
+import (
+	"sync"
+	"time"
+)
+
+// StreamMetrics contains performance measurements for a single stream.
+// Durations are measured from the moment the request was sent, so they include
+// network latency and time spent by OpenRouter routing the request.
+type StreamMetrics struct {
+	// TimeToFirstToken is the time between sending the request and receiving the first chunk
+	TimeToFirstToken time.Duration
+	// TotalDuration is the time between sending the request and the end of the stream
+	TotalDuration time.Duration
+	// Chunks is the number of data chunks received
+	Chunks int
+	// MeanInterChunkLatency is the average time between consecutive chunks
+	MeanInterChunkLatency time.Duration
+	// MaxInterChunkLatency is the longest time between consecutive chunks
+	MaxInterChunkLatency time.Duration
+	// CompletionTokens is the number of completion tokens reported in the usage chunk, if any
+	CompletionTokens int
+	// TokensPerSecond is the completion token throughput between the first and the last chunk.
+	// It is zero if the stream did not report usage statistics.
+	TokensPerSecond float64
+}
+
+// streamMetrics records timing information while a stream is read.
+// It is safe for concurrent use, as chunks may be read by the read-ahead goroutine
+// while the consumer inspects the metrics.
+type streamMetrics struct {
+	mu         sync.Mutex
+	start      time.Time
+	firstChunk time.Time
+	lastChunk  time.Time
+	end        time.Time
+	chunks     int
+	gapTotal   time.Duration
+	gapMax     time.Duration
+	tokens     int
+}
+
+// newStreamMetrics starts measuring a stream whose request was sent at start.
+func newStreamMetrics(start time.Time) *streamMetrics {
+	return &streamMetrics{start: start}
+}
+
+// recordChunk records the arrival of a data chunk.
+func (m *streamMetrics) recordChunk(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.chunks == 0 {
+		m.firstChunk = now
+	} else {
+		gap := now.Sub(m.lastChunk)
+		m.gapTotal += gap
+		if gap > m.gapMax {
+			m.gapMax = gap
+		}
+	}
+	m.lastChunk = now
+	m.chunks++
+}
+
+// recordUsage records the completion tokens reported by the stream.
+func (m *streamMetrics) recordUsage(usage *Usage) {
+	if usage == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens = usage.CompletionTokens
+}
+
+// finish marks the end of the stream. Only the first call has an effect.
+func (m *streamMetrics) finish(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.end.IsZero() {
+		m.end = now
+	}
+}
+
+// snapshot returns the metrics recorded so far. For a stream that has not finished
+// yet, the total duration is measured until now.
+func (m *streamMetrics) snapshot() StreamMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	end := m.end
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	metrics := StreamMetrics{
+		TotalDuration:        end.Sub(m.start),
+		Chunks:               m.chunks,
+		MaxInterChunkLatency: m.gapMax,
+		CompletionTokens:     m.tokens,
+	}
+	if m.chunks > 0 {
+		metrics.TimeToFirstToken = m.firstChunk.Sub(m.start)
+	}
+	if m.chunks > 1 {
+		metrics.MeanInterChunkLatency = m.gapTotal / time.Duration(m.chunks-1)
+	}
+	if generation := m.lastChunk.Sub(m.firstChunk); m.tokens > 0 && generation > 0 {
+		metrics.TokensPerSecond = float64(m.tokens) / generation.Seconds()
+	}
+	return metrics
+}
+
+// usageReporter is implemented by stream chunk types carrying usage statistics.
+type usageReporter interface {
+	usage() *Usage
+}
+
+// usage returns the usage statistics carried by the chunk, if any.
+func (r *ChatCompletionStreamResponse) usage() *Usage {
+	return r.Usage
+}
+
+// usage returns the usage statistics carried by the chunk, if any.
+func (r *CompletionStreamResponse) usage() *Usage {
+	return r.Usage
+}
+
+// originReporter is implemented by stream chunks identifying the model and provider
+// that generated them.
+type originReporter interface {
+	origin() (model, provider string)
+}
+
+// origin returns the model that generated the chunk.
+func (r *ChatCompletionStreamResponse) origin() (model, provider string) {
+	return r.Model, ""
+}
+
+// origin returns the model and provider that generated the chunk.
+func (r *CompletionStreamResponse) origin() (model, provider string) {
+	return r.Model, r.Provider
+}
@@ -0,0 +1,108 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestStreamMetrics(t *testing.T) {
+	t.Run("CompletedStream", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			flusher := w.(http.Flusher)
+
+			time.Sleep(20 * time.Millisecond)
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hello"}}]}` + "\n\n"))
+			flusher.Flush()
+			time.Sleep(20 * time.Millisecond)
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":" world"}}]}` + "\n\n"))
+			flusher.Flush()
+			time.Sleep(20 * time.Millisecond)
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":10,"total_tokens":15}}` + "\n\n"))
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("ChatCompletionStream failed: %v", err)
+		}
+		defer func() {
+			_ = stream.Close()
+		}()
+
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Recv failed: %v", err)
+			}
+		}
+
+		metrics := stream.Metrics()
+		if metrics.Chunks != 3 {
+			t.Errorf("Expected 3 chunks, got %d", metrics.Chunks)
+		}
+		if metrics.TimeToFirstToken < 20*time.Millisecond {
+			t.Errorf("Expected time to first token of at least 20ms, got %v", metrics.TimeToFirstToken)
+		}
+		if metrics.TotalDuration < metrics.TimeToFirstToken {
+			t.Errorf("Expected total duration %v to be at least the time to first token %v", metrics.TotalDuration, metrics.TimeToFirstToken)
+		}
+		if metrics.MeanInterChunkLatency <= 0 || metrics.MaxInterChunkLatency < metrics.MeanInterChunkLatency {
+			t.Errorf("Expected positive inter-chunk latencies, got mean %v and max %v", metrics.MeanInterChunkLatency, metrics.MaxInterChunkLatency)
+		}
+		if metrics.CompletionTokens != 10 {
+			t.Errorf("Expected 10 completion tokens, got %d", metrics.CompletionTokens)
+		}
+		if metrics.TokensPerSecond <= 0 {
+			t.Errorf("Expected positive tokens per second, got %f", metrics.TokensPerSecond)
+		}
+
+		// Metrics are final once the stream has finished
+		time.Sleep(10 * time.Millisecond)
+		if again := stream.Metrics(); again.TotalDuration != metrics.TotalDuration {
+			t.Errorf("Expected total duration to stay %v, got %v", metrics.TotalDuration, again.TotalDuration)
+		}
+	})
+
+	t.Run("NoChunks", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "Hello").Build()
+
+		stream, err := client.CompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("CompletionStream failed: %v", err)
+		}
+		defer func() {
+			_ = stream.Close()
+		}()
+
+		if _, err := stream.Recv(); err != io.EOF {
+			t.Fatalf("Expected io.EOF, got %v", err)
+		}
+
+		metrics := stream.Metrics()
+		if metrics.Chunks != 0 || metrics.TimeToFirstToken != 0 || metrics.TokensPerSecond != 0 {
+			t.Errorf("Expected empty metrics, got %+v", metrics)
+		}
+	})
+}
@@ -0,0 +1,796 @@
+package gopenrouter_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bkovacki/gopenrouter"
+	"github.com/bkovacki/gopenrouter/gopenroutertest"
+)
+
+func TestStreamReconnect(t *testing.T) {
+	t.Run("ResumesWithLastEventID", func(t *testing.T) {
+		var attempts atomic.Int32
+		var resumedFrom atomic.Value
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+
+			if attempts.Add(1) == 1 {
+				// Drop the connection before the stream has finished
+				_, _ = w.Write([]byte("id: 1\n" + `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hello"}}]}` + "\n\n"))
+				return
+			}
+
+			resumedFrom.Store(r.Header.Get("Last-Event-ID"))
+			_, _ = w.Write([]byte("id: 2\n" + `data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":" world"}}]}` + "\n\n"))
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithStreamReconnect(2))
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("ChatCompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		var content string
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			content += *chunk.Choices[0].Delta.Content
+		}
+
+		if content != "Hello world" {
+			t.Errorf("Expected content 'Hello world', got '%s'", content)
+		}
+		if attempts.Load() != 2 {
+			t.Errorf("Expected 2 connection attempts, got %d", attempts.Load())
+		}
+		if id, _ := resumedFrom.Load().(string); id != "1" {
+			t.Errorf("Expected Last-Event-ID '1', got '%s'", id)
+		}
+		if stream.LastEventID() != "2" {
+			t.Errorf("Expected last event ID '2', got '%s'", stream.LastEventID())
+		}
+	})
+
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		var attempts atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("id: 1\n" + `data: {"id":"gen-1","choices":[{"index":0,"text":"Hello"}]}` + "\n\n"))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "test prompt").Build()
+
+		stream, err := client.CompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("CompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("Failed to read chunk: %v", err)
+		}
+		if _, err := stream.Recv(); !errors.Is(err, gopenrouter.ErrStreamTruncated) {
+			t.Errorf("Expected ErrStreamTruncated, got %v", err)
+		}
+		if attempts.Load() != 1 {
+			t.Errorf("Expected 1 connection attempt, got %d", attempts.Load())
+		}
+	})
+}
+
+func TestStreamIdleTimeout(t *testing.T) {
+	t.Run("StalledStream", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`data: {"id":"gen-1","choices":[{"index":0,"text":"Hello"}]}` + "\n\n"))
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+			// Hold the connection open without sending anything
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		client := gopenrouter.New("test-api-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithStreamIdleTimeout(50*time.Millisecond))
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "test prompt").Build()
+
+		stream, err := client.CompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("CompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("Failed to read first chunk: %v", err)
+		}
+
+		_, err = stream.Recv()
+		if !errors.Is(err, gopenrouter.ErrStreamStalled) {
+			t.Errorf("Expected ErrStreamStalled, got %v", err)
+		}
+	})
+
+	t.Run("SlowConsumerNotStalled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`data: {"id":"gen-1","choices":[{"index":0,"text":"Hello"}]}` + "\n\n"))
+			_, _ = w.Write([]byte(`data: {"id":"gen-1","choices":[{"index":0,"text":" world"}]}` + "\n\n"))
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithStreamIdleTimeout(20*time.Millisecond))
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "test prompt").Build()
+
+		stream, err := client.CompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("CompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		for i := 0; i < 2; i++ {
+			if _, err := stream.Recv(); err != nil {
+				t.Fatalf("Failed to read chunk %d: %v", i, err)
+			}
+			time.Sleep(40 * time.Millisecond)
+		}
+		if _, err := stream.Recv(); err != io.EOF {
+			t.Errorf("Expected EOF, got %v", err)
+		}
+	})
+}
+
+func TestStreamContextError(t *testing.T) {
+	cases := []struct {
+		name      string
+		newCtx    func() (context.Context, context.CancelFunc)
+		cancelNow bool
+		expectErr error
+	}{
+		{
+			name: "Canceled",
+			newCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			cancelNow: true,
+			expectErr: context.Canceled,
+		},
+		{
+			name: "DeadlineExceeded",
+			newCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			expectErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hello"}}]}` + "\n\n"))
+				if f, ok := w.(http.Flusher); ok {
+					f.Flush()
+				}
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}))
+			defer server.Close()
+			defer close(release)
+
+			client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+			messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+			request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+			ctx, cancel := tc.newCtx()
+			defer cancel()
+
+			stream, err := client.ChatCompletionStream(ctx, *request)
+			if err != nil {
+				t.Fatalf("ChatCompletionStream failed: %v", err)
+			}
+			defer func() { _ = stream.Close() }()
+
+			if _, err := stream.Recv(); err != nil {
+				t.Fatalf("Failed to read first chunk: %v", err)
+			}
+
+			if tc.cancelNow {
+				cancel()
+			}
+
+			_, err = stream.Recv()
+			if err != tc.expectErr {
+				t.Errorf("Expected %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestStreamRecvContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hello"}}]}` + "\n\n"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":" world"}}]}` + "\n\n"))
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+	request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+	stream, err := client.ChatCompletionStream(context.Background(), *request)
+	if err != nil {
+		t.Fatalf("ChatCompletionStream failed: %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	chunk, err := stream.RecvContext(ctx)
+	cancel()
+	if err != nil {
+		t.Fatalf("Failed to read first chunk: %v", err)
+	}
+	if *chunk.Choices[0].Delta.Content != "Hello" {
+		t.Errorf("Expected content 'Hello', got '%s'", *chunk.Choices[0].Delta.Content)
+	}
+
+	// The second chunk is held back, so a short per-read deadline expires
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	_, err = stream.RecvContext(ctx)
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	// The stream remains usable after the abandoned read
+	close(release)
+	chunk, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected stream to remain usable, got %v", err)
+	}
+	if *chunk.Choices[0].Delta.Content != " world" {
+		t.Errorf("Expected content ' world', got '%s'", *chunk.Choices[0].Delta.Content)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestStreamFinal(t *testing.T) {
+	cases := []struct {
+		name       string
+		body       string
+		expectDone bool
+		expectErr  error
+	}{
+		{
+			name:       "CleanFinish",
+			body:       "data: [DONE]\n\n",
+			expectDone: true,
+			expectErr:  io.EOF,
+		},
+		{
+			name:       "TruncatedConnection",
+			expectDone: false,
+			expectErr:  gopenrouter.ErrStreamTruncated,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"openai/gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}}]}` + "\n\n"))
+				_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"openai/gpt-4o","choices":[{"index":0,"delta":{"content":" world"},"finish_reason":"stop"}]}` + "\n\n"))
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+			messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+			request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+			stream, err := client.ChatCompletionStream(context.Background(), *request)
+			if err != nil {
+				t.Fatalf("ChatCompletionStream failed: %v", err)
+			}
+			defer func() { _ = stream.Close() }()
+
+			for {
+				_, err := stream.Recv()
+				if err != nil {
+					if err != tc.expectErr {
+						t.Errorf("Expected %v, got %v", tc.expectErr, err)
+					}
+					if tc.expectErr == gopenrouter.ErrStreamTruncated && !errors.Is(err, io.ErrUnexpectedEOF) {
+						t.Errorf("Expected error to wrap io.ErrUnexpectedEOF, got %v", err)
+					}
+					break
+				}
+				if stream.Done() {
+					t.Error("Expected Done to be false before the stream has finished")
+				}
+			}
+
+			if stream.Done() != tc.expectDone {
+				t.Errorf("Expected Done to be %v, got %v", tc.expectDone, stream.Done())
+			}
+
+			final := stream.Final()
+			if final.ID != "chatcmpl-1" || final.Model != "openai/gpt-4o" || final.Object != "chat.completion.chunk" || final.Created != 1700000000 {
+				t.Errorf("Expected the metadata of the chunks, got ID %q, model %q, object %q, and created %d",
+					final.ID, final.Model, final.Object, final.Created)
+			}
+			if len(final.Choices) != 1 {
+				t.Fatalf("Expected 1 choice, got %d", len(final.Choices))
+			}
+			if final.Choices[0].Message.Content != "Hello world" {
+				t.Errorf("Expected content 'Hello world', got '%s'", final.Choices[0].Message.Content)
+			}
+			if final.Choices[0].FinishReason != "stop" {
+				t.Errorf("Expected finish reason 'stop', got '%s'", final.Choices[0].FinishReason)
+			}
+		})
+	}
+}
+
+func TestStreamDecodeErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("data: {invalid json}\n\n"))
+		_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hello"}}]}` + "\n\n"))
+		_, _ = w.Write([]byte(`data: {"id":42}` + "\n\n"))
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	var raws []string
+	var decodeErrs []error
+	client := gopenrouter.New(
+		"test-api-key",
+		gopenrouter.WithBaseURL(server.URL),
+		gopenrouter.WithOnDecodeError(func(raw []byte, err error) {
+			raws = append(raws, string(raw))
+			decodeErrs = append(decodeErrs, err)
+		}),
+	)
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+	request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+	stream, err := client.ChatCompletionStream(context.Background(), *request)
+	if err != nil {
+		t.Fatalf("ChatCompletionStream failed: %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	chunk, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to read valid chunk: %v", err)
+	}
+	if *chunk.Choices[0].Delta.Content != "Hello" {
+		t.Errorf("Expected content 'Hello', got '%s'", *chunk.Choices[0].Delta.Content)
+	}
+	if stream.SkippedChunks() != 1 {
+		t.Errorf("Expected 1 skipped chunk, got %d", stream.SkippedChunks())
+	}
+
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+	if stream.SkippedChunks() != 2 {
+		t.Errorf("Expected 2 skipped chunks, got %d", stream.SkippedChunks())
+	}
+
+	if len(raws) != 2 || raws[0] != "{invalid json}" || raws[1] != `{"id":42}` {
+		t.Errorf("Expected callback to receive both malformed chunks, got %v", raws)
+	}
+	for _, err := range decodeErrs {
+		if err == nil {
+			t.Error("Expected callback to receive a decoding error")
+		}
+	}
+}
+
+func TestStreamComments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(": OPENROUTER PROCESSING\n\n"))
+		_, _ = w.Write([]byte(": OPENROUTER PROCESSING\n\n"))
+		_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hello"}}]}` + "\n\n"))
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	var comments []string
+	client := gopenrouter.New(
+		"test-api-key",
+		gopenrouter.WithBaseURL(server.URL),
+		gopenrouter.WithOnComment(func(comment string) {
+			comments = append(comments, comment)
+		}),
+	)
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+	request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+	stream, err := client.ChatCompletionStream(context.Background(), *request)
+	if err != nil {
+		t.Fatalf("ChatCompletionStream failed: %v", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	chunk, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to read chunk: %v", err)
+	}
+	if *chunk.Choices[0].Delta.Content != "Hello" {
+		t.Errorf("Expected content 'Hello', got '%s'", *chunk.Choices[0].Delta.Content)
+	}
+
+	if len(comments) != 2 {
+		t.Fatalf("Expected 2 comments before the first chunk, got %v", comments)
+	}
+	for _, comment := range comments {
+		if comment != "OPENROUTER PROCESSING" {
+			t.Errorf("Expected comment 'OPENROUTER PROCESSING', got '%s'", comment)
+		}
+	}
+}
+
+func TestStreamRawChunks(t *testing.T) {
+	chunks := []string{
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hello"}}]}`,
+		`{malformed}`,
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"!"}}]}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(": OPENROUTER PROCESSING\n\n"))
+		for _, chunk := range chunks {
+			_, _ = w.Write([]byte("data: " + chunk + "\n\n"))
+		}
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+	request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+	t.Run("RecvRaw", func(t *testing.T) {
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("ChatCompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		for i, expected := range chunks {
+			raw, err := stream.RecvRaw()
+			if err != nil {
+				t.Fatalf("Failed to read raw chunk %d: %v", i, err)
+			}
+			if string(raw) != expected {
+				t.Errorf("Expected raw chunk %s, got %s", expected, raw)
+			}
+		}
+		if _, err := stream.RecvRaw(); err != io.EOF {
+			t.Errorf("Expected EOF, got %v", err)
+		}
+	})
+
+	t.Run("RawChunkAfterRecv", func(t *testing.T) {
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("ChatCompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		if stream.RawChunk() != nil {
+			t.Errorf("Expected no raw chunk before Recv, got %s", stream.RawChunk())
+		}
+
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("Failed to read chunk: %v", err)
+		}
+		if string(stream.RawChunk()) != chunks[0] {
+			t.Errorf("Expected raw chunk %s, got %s", chunks[0], stream.RawChunk())
+		}
+
+		// The malformed chunk is skipped by Recv
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("Failed to read chunk: %v", err)
+		}
+		if string(stream.RawChunk()) != chunks[2] {
+			t.Errorf("Expected raw chunk %s, got %s", chunks[2], stream.RawChunk())
+		}
+	})
+}
+
+func TestStreamBuffer(t *testing.T) {
+	newServer := func(count int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			for i := 1; i <= count; i++ {
+				_, _ = w.Write([]byte(`data: {"id":"gen-1","choices":[{"index":0,"text":"` + strconv.Itoa(i) + `"}]}` + "\n\n"))
+			}
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+	}
+
+	t.Run("Block", func(t *testing.T) {
+		server := newServer(5)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithStreamBuffer(2, gopenrouter.StreamBufferBlock))
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "test prompt").Build()
+
+		stream, err := client.CompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("CompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		for i := 1; i <= 5; i++ {
+			chunk, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("Failed to read chunk %d: %v", i, err)
+			}
+			if chunk.Choices[0].Text != strconv.Itoa(i) {
+				t.Errorf("Expected text '%d', got '%s'", i, chunk.Choices[0].Text)
+			}
+		}
+		if _, err := stream.Recv(); err != io.EOF {
+			t.Errorf("Expected EOF, got %v", err)
+		}
+		if stream.DroppedChunks() != 0 {
+			t.Errorf("Expected no dropped chunks, got %d", stream.DroppedChunks())
+		}
+	})
+
+	t.Run("DropOldest", func(t *testing.T) {
+		server := newServer(10)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithStreamBuffer(2, gopenrouter.StreamBufferDropOldest))
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "test prompt").Build()
+
+		stream, err := client.CompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("CompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		// Wait for the read-ahead goroutine to drain the whole connection
+		deadline := time.Now().Add(2 * time.Second)
+		for stream.DroppedChunks() < 9 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		var texts []string
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			texts = append(texts, chunk.Choices[0].Text)
+		}
+
+		if len(texts) != 1 || texts[0] != "10" {
+			t.Errorf("Expected only the newest chunk to be kept, got %v", texts)
+		}
+		if stream.DroppedChunks() != 9 {
+			t.Errorf("Expected 9 dropped chunks, got %d", stream.DroppedChunks())
+		}
+	})
+
+	t.Run("RecvAfterClose", func(t *testing.T) {
+		server := newServer(3)
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithStreamBuffer(1, gopenrouter.StreamBufferBlock))
+		request := gopenrouter.NewCompletionRequestBuilder("test-model", "test prompt").Build()
+
+		stream, err := client.CompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("CompletionStream failed: %v", err)
+		}
+
+		if err := stream.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if _, err := stream.Recv(); err == nil {
+			t.Error("Expected error after closing stream")
+		}
+	})
+}
+
+func TestStreamRecvChunk(t *testing.T) {
+	t.Run("Chat", func(t *testing.T) {
+		stream := gopenroutertest.NewStream().
+			Data(`{"id":"gen-1","model":"test-model","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}},{"index":1,"delta":{"content":"Hi"}}]}`).
+			Chunk(gopenroutertest.ChatFinishChunk("gen-1", "test-model", "stop")).
+			Usage(gopenrouter.Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}).
+			Done()
+		reader := gopenrouter.NewChatCompletionStreamReader(streamResponse(stream))
+		defer func() { _ = reader.Close() }()
+		ctx := context.Background()
+
+		first, err := reader.RecvChunk(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if first.Text() != "HelloHi" || len(first.Choices) != 2 {
+			t.Errorf("Expected 2 choices with text 'HelloHi', got %d with '%s'", len(first.Choices), first.Text())
+		}
+
+		second, err := reader.RecvChunk(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if second != first {
+			t.Error("Expected the chunk to be reused")
+		}
+		if len(second.Choices) != 1 {
+			t.Fatalf("Expected 1 choice, got %d", len(second.Choices))
+		}
+		if choice := second.Choices[0]; choice.Delta.Content != nil || choice.Delta.Role != nil || choice.FinishReason == nil || *choice.FinishReason != "stop" {
+			t.Errorf("Expected only the finish reason to be set, got %+v", choice)
+		}
+
+		third, err := reader.RecvChunk(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(third.Choices) != 0 || third.Usage == nil || third.Usage.TotalTokens != 5 {
+			t.Errorf("Expected a usage chunk without choices, got %+v", third)
+		}
+
+		if chunk, err := reader.RecvChunk(ctx); err != io.EOF || chunk != nil {
+			t.Errorf("Expected nil chunk and io.EOF, got %v and %v", chunk, err)
+		}
+
+		if final := reader.Final(); len(final.Choices) != 0 || final.Usage.TotalTokens != 0 {
+			t.Errorf("Expected chunks received with RecvChunk not to be accumulated, got %+v", final)
+		}
+	})
+
+	t.Run("Completion", func(t *testing.T) {
+		stream := gopenroutertest.NewStream().
+			Data(`{"id":"gen-1","provider":"test-provider","system_fingerprint":"fp","choices":[{"index":0,"text":"Hello"}]}`).
+			Chunk(gopenroutertest.CompletionChunk("gen-1", "test-model", " world")).
+			Done()
+		reader := gopenrouter.NewCompletionStreamReader(streamResponse(stream))
+		defer func() { _ = reader.Close() }()
+		ctx := context.Background()
+
+		if _, err := reader.RecvChunk(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		chunk, err := reader.RecvChunk(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if chunk.Provider != "" || chunk.SystemFingerprint != nil {
+			t.Errorf("Expected the fields of the previous chunk to be reset, got %+v", chunk)
+		}
+		if chunk.Choices[0].Text != " world" {
+			t.Errorf("Expected text ' world', got '%s'", chunk.Choices[0].Text)
+		}
+		if _, err := reader.RecvChunk(ctx); err != io.EOF {
+			t.Errorf("Expected io.EOF, got %v", err)
+		}
+		if final := reader.Final(); len(final.Choices) != 0 {
+			t.Errorf("Expected chunks received with RecvChunk not to be accumulated, got %+v", final.Choices)
+		}
+	})
+}
+
+// streamResponse returns a response whose body is the given stream.
+func streamResponse(stream *gopenroutertest.Stream) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/event-stream"}},
+		Body:       io.NopCloser(bytes.NewReader(stream.Bytes())),
+	}
+}
+
+func BenchmarkStreamRecv(b *testing.B) {
+	stream := gopenroutertest.NewStream()
+	for range 100 {
+		stream.Chunk(gopenroutertest.ChatChunk("gen-1", "test-model", "token"))
+	}
+	stream.Done()
+
+	b.Run("Recv", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			reader := gopenrouter.NewChatCompletionStreamReader(streamResponse(stream))
+			for {
+				if _, err := reader.Recv(); err != nil {
+					break
+				}
+			}
+		}
+	})
+
+	b.Run("RecvChunk", func(b *testing.B) {
+		b.ReportAllocs()
+		ctx := context.Background()
+		for b.Loop() {
+			reader := gopenrouter.NewChatCompletionStreamReader(streamResponse(stream))
+			for {
+				if _, err := reader.RecvChunk(ctx); err != nil {
+					break
+				}
+			}
+		}
+	})
+}
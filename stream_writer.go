@@ -0,0 +1,91 @@
+package gopenrouter
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// TextChunk is implemented by stream chunks carrying generated text,
+// such as ChatCompletionStreamResponse and CompletionStreamResponse.
+type TextChunk interface {
+	// Text returns the text delta carried by the chunk
+	Text() string
+}
+
+// StreamToWriter reads the stream until it ends and writes the text delta of every chunk to w.
+// If w implements http.Flusher or has a Flush() error method, like *bufio.Writer, it is flushed
+// after each chunk so the text reaches its destination as soon as it is generated.
+//
+// Parameters:
+//   - ctx: The context controlling how long to wait for chunks
+//   - stream: The stream to read, which is not closed by StreamToWriter
+//   - w: The destination of the generated text
+//
+// Returns:
+//   - int64: The number of bytes written to w
+//   - error: Any error reading the stream or writing to w; nil once the stream ends with io.EOF
+//
+// Example usage:
+//
+//	stream, err := client.ChatCompletionStream(ctx, *request)
+//	if err != nil {
+//	  // handle error
+//	}
+//	defer stream.Close()
+//
+//	if _, err := gopenrouter.StreamToWriter(ctx, stream, os.Stdout); err != nil {
+//	  // handle error
+//	}
+func StreamToWriter[T TextChunk](ctx context.Context, stream StreamReader[T], w io.Writer) (int64, error) {
+	recv := func() (T, error) {
+		if err := ctx.Err(); err != nil {
+			var zero T
+			return zero, err
+		}
+		return stream.Recv()
+	}
+	if reader, ok := stream.(interface {
+		RecvContext(ctx context.Context) (T, error)
+	}); ok {
+		recv = func() (T, error) {
+			return reader.RecvContext(ctx)
+		}
+	}
+
+	var written int64
+	for {
+		chunk, err := recv()
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+
+		text := chunk.Text()
+		if text == "" {
+			continue
+		}
+
+		n, err := io.WriteString(w, text)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+		if err := flush(w); err != nil {
+			return written, err
+		}
+	}
+}
+
+// flush flushes w if it supports flushing.
+func flush(w io.Writer) error {
+	switch f := w.(type) {
+	case http.Flusher:
+		f.Flush()
+	case interface{ Flush() error }:
+		return f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// flushRecorder is a writer counting how often it was flushed.
+type flushRecorder struct {
+	strings.Builder
+	flushes int
+}
+
+func (f *flushRecorder) Flush() error {
+	f.flushes++
+	return nil
+}
+
+func TestStreamToWriter(t *testing.T) {
+	t.Run("ChatCompletionStream", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"role":"assistant"}}]}` + "\n\n"))
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hello"}}]}` + "\n\n"))
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":" world"}}]}` + "\n\n"))
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("ChatCompletionStream failed: %v", err)
+		}
+		defer func() { _ = stream.Close() }()
+
+		var out flushRecorder
+		written, err := gopenrouter.StreamToWriter(context.Background(), stream, &out)
+		if err != nil {
+			t.Fatalf("StreamToWriter failed: %v", err)
+		}
+		if out.String() != "Hello world" {
+			t.Errorf("Expected 'Hello world', got '%s'", out.String())
+		}
+		if written != int64(len("Hello world")) {
+			t.Errorf("Expected %d bytes written, got %d", len("Hello world"), written)
+		}
+		if out.flushes != 2 {
+			t.Errorf("Expected 2 flushes, got %d", out.flushes)
+		}
+	})
+
+	t.Run("CompletionStream", func(t *testing.T) {
+		source := &sliceStream[gopenrouter.CompletionStreamResponse]{values: []gopenrouter.CompletionStreamResponse{
+			{Choices: []gopenrouter.StreamingChoice{{Text: "Once"}}},
+			{Choices: []gopenrouter.StreamingChoice{{Text: " upon a time"}}},
+		}}
+
+		var out strings.Builder
+		if _, err := gopenrouter.StreamToWriter[gopenrouter.CompletionStreamResponse](context.Background(), source, &out); err != nil {
+			t.Fatalf("StreamToWriter failed: %v", err)
+		}
+		if out.String() != "Once upon a time" {
+			t.Errorf("Expected 'Once upon a time', got '%s'", out.String())
+		}
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		source := &sliceStream[gopenrouter.CompletionStreamResponse]{values: []gopenrouter.CompletionStreamResponse{
+			{Choices: []gopenrouter.StreamingChoice{{Text: "Once"}}},
+		}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var out strings.Builder
+		_, err := gopenrouter.StreamToWriter[gopenrouter.CompletionStreamResponse](ctx, source, &out)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("Expected nothing to be written, got '%s'", out.String())
+		}
+	})
+}
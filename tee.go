@@ -0,0 +1,153 @@
+package gopenrouter
+
+import (
+	"errors"
+	"sync"
+)
+
+// errTeeReaderClosed is returned by Recv on a tee branch that has been closed.
+var errTeeReaderClosed = errors.New("read on closed tee stream")
+
+// TeeStream duplicates a stream into n independent readers, each of which receives
+// every chunk of the source stream in order. This allows one consumer to render deltas
+// while another persists them or computes metrics, without requesting the generation twice.
+//
+// The source is read on demand by whichever branch needs the next chunk, and chunks are
+// buffered until every open branch has received them, so every branch must either be
+// consumed or closed. The source stream is closed once all branches are closed.
+// Errors returned by the source, including io.EOF, are delivered to every branch.
+//
+// Each branch may be used from its own goroutine.
+func TeeStream[T any](source StreamReader[T], n int) []StreamReader[T] {
+	t := &tee[T]{
+		source:  source,
+		cursors: make([]int, n),
+		open:    n,
+	}
+
+	readers := make([]StreamReader[T], n)
+	for i := range readers {
+		readers[i] = &teeReader[T]{tee: t, id: i}
+	}
+	return readers
+}
+
+// tee holds the state shared by the branches of a duplicated stream.
+type tee[T any] struct {
+	// fetchMu serializes reads from the source
+	fetchMu sync.Mutex
+	source  StreamReader[T]
+
+	mu sync.Mutex
+	// buffer holds chunks that have not yet been received by every open branch
+	buffer []T
+	// offset is the absolute position of buffer[0] in the stream
+	offset int
+	// err is the terminal error returned by the source
+	err error
+	// cursors holds the absolute position of the next chunk for each branch,
+	// or -1 for closed branches
+	cursors []int
+	open    int
+}
+
+// teeReader is a single branch of a duplicated stream.
+type teeReader[T any] struct {
+	tee *tee[T]
+	id  int
+}
+
+// Recv returns the next chunk of the source stream for this branch.
+func (r *teeReader[T]) Recv() (T, error) {
+	t := r.tee
+
+	if value, ok, err := t.next(r.id); ok || err != nil {
+		return value, err
+	}
+
+	t.fetchMu.Lock()
+	defer t.fetchMu.Unlock()
+
+	// Another branch may have fetched the chunk while we were waiting
+	if value, ok, err := t.next(r.id); ok || err != nil {
+		return value, err
+	}
+
+	value, err := t.source.Recv()
+
+	t.mu.Lock()
+	if err != nil {
+		t.err = err
+	} else {
+		t.buffer = append(t.buffer, value)
+	}
+	t.mu.Unlock()
+
+	value, _, err = t.next(r.id)
+	return value, err
+}
+
+// Close closes this branch. The source stream is closed when the last branch is closed.
+func (r *teeReader[T]) Close() error {
+	t := r.tee
+
+	t.mu.Lock()
+	if t.cursors[r.id] < 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	t.cursors[r.id] = -1
+	t.open--
+	t.trim()
+	last := t.open == 0
+	t.mu.Unlock()
+
+	if last {
+		return t.source.Close()
+	}
+	return nil
+}
+
+// next returns the next buffered chunk for the given branch. It reports false when
+// the branch has consumed every buffered chunk and the source has not failed yet.
+func (t *tee[T]) next(id int) (value T, ok bool, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pos := t.cursors[id]
+	if pos < 0 {
+		return value, false, errTeeReaderClosed
+	}
+	if pos-t.offset < len(t.buffer) {
+		value = t.buffer[pos-t.offset]
+		t.cursors[id]++
+		t.trim()
+		return value, true, nil
+	}
+	return value, false, t.err
+}
+
+// trim drops buffered chunks that every open branch has already received.
+func (t *tee[T]) trim() {
+	minPos := -1
+	for _, pos := range t.cursors {
+		if pos >= 0 && (minPos < 0 || pos < minPos) {
+			minPos = pos
+		}
+	}
+	if minPos < 0 {
+		t.offset += len(t.buffer)
+		t.buffer = nil
+		return
+	}
+
+	drop := minPos - t.offset
+	if drop > 0 {
+		var zero T
+		for i := 0; i < drop; i++ {
+			t.buffer[i] = zero
+		}
+		t.buffer = t.buffer[drop:]
+		t.offset = minPos
+	}
+}
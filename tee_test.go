@@ -0,0 +1,155 @@
+package gopenrouter_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// sliceStream is a StreamReader returning a fixed list of values followed by io.EOF.
+type sliceStream[T any] struct {
+	mu     sync.Mutex
+	values []T
+	reads  int
+	closed bool
+}
+
+func (s *sliceStream[T]) Recv() (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var zero T
+	if s.reads >= len(s.values) {
+		return zero, io.EOF
+	}
+	value := s.values[s.reads]
+	s.reads++
+	return value, nil
+}
+
+func (s *sliceStream[T]) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	return nil
+}
+
+func drain[T any](t *testing.T, stream gopenrouter.StreamReader[T]) []T {
+	t.Helper()
+
+	var values []T
+	for {
+		value, err := stream.Recv()
+		if err == io.EOF {
+			return values
+		}
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			return values
+		}
+		values = append(values, value)
+	}
+}
+
+func TestTeeStream(t *testing.T) {
+	t.Run("AllBranchesReceiveEveryChunk", func(t *testing.T) {
+		source := &sliceStream[int]{values: []int{1, 2, 3, 4, 5}}
+		branches := gopenrouter.TeeStream[int](source, 3)
+
+		var wg sync.WaitGroup
+		results := make([][]int, len(branches))
+		for i, branch := range branches {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = drain(t, branch)
+			}()
+		}
+		wg.Wait()
+
+		for i, result := range results {
+			if len(result) != 5 {
+				t.Errorf("Expected branch %d to receive 5 chunks, got %v", i, result)
+				continue
+			}
+			for j, value := range result {
+				if value != j+1 {
+					t.Errorf("Expected branch %d chunk %d to be %d, got %d", i, j, j+1, value)
+				}
+			}
+		}
+		if source.reads != 5 {
+			t.Errorf("Expected source to be read 5 times, got %d", source.reads)
+		}
+	})
+
+	t.Run("CloseAllBranchesClosesSource", func(t *testing.T) {
+		source := &sliceStream[int]{values: []int{1, 2}}
+		branches := gopenrouter.TeeStream[int](source, 2)
+
+		if err := branches[0].Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if source.closed {
+			t.Error("Expected source to stay open while a branch is open")
+		}
+		if _, err := branches[0].Recv(); err == nil {
+			t.Error("Expected error reading a closed branch")
+		}
+
+		if values := drain(t, branches[1]); len(values) != 2 {
+			t.Errorf("Expected remaining branch to receive 2 chunks, got %v", values)
+		}
+
+		if err := branches[1].Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if !source.closed {
+			t.Error("Expected source to be closed after all branches are closed")
+		}
+	})
+
+	t.Run("ChatCompletionStream", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":"Hello"}}]}` + "\n\n"))
+			_, _ = w.Write([]byte(`data: {"id":"chatcmpl-1","choices":[{"index":0,"delta":{"content":" world"}}]}` + "\n\n"))
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client := gopenrouter.New("test-api-key", gopenrouter.WithBaseURL(server.URL))
+		messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+		request := gopenrouter.NewChatCompletionRequestBuilder("test-model", messages).Build()
+
+		stream, err := client.ChatCompletionStream(context.Background(), *request)
+		if err != nil {
+			t.Fatalf("ChatCompletionStream failed: %v", err)
+		}
+
+		branches := gopenrouter.TeeStream[gopenrouter.ChatCompletionStreamResponse](stream, 2)
+		defer func() {
+			for _, branch := range branches {
+				_ = branch.Close()
+			}
+		}()
+
+		// Consume the first branch completely before the second one
+		first := drain(t, branches[0])
+		second := drain(t, branches[1])
+
+		if len(first) != 2 || len(second) != 2 {
+			t.Fatalf("Expected both branches to receive 2 chunks, got %d and %d", len(first), len(second))
+		}
+		if *second[1].Choices[0].Delta.Content != " world" {
+			t.Errorf("Expected content ' world', got '%s'", *second[1].Choices[0].Delta.Content)
+		}
+	})
+}
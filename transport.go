@@ -0,0 +1,46 @@
+package gopenrouter
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// WithOptimizedTransport installs an HTTP client tuned for long-running streams.
+//
+// Unlike http.DefaultClient, whose shared transport keeps only two idle connections per
+// host, the installed client:
+//   - has no overall timeout and no response header timeout, which would abort long
+//     generations; use contexts or WithRequestTimeout to bound requests instead
+//   - keeps a larger pool of idle connections to OpenRouter for concurrent requests
+//   - disables transparent compression, which delays the delivery of event stream
+//     chunks; non-streaming responses are still compressed, as the client sets
+//     Accept-Encoding on those requests and decodes the responses itself
+//   - attempts HTTP/2, multiplexing concurrent streams over a single connection
+//
+// Connections honor the proxy configured in the environment. This option replaces any
+// HTTP client set with WithHTTPClient.
+func WithOptimizedTransport() Option {
+	return func(c *Client) {
+		c.httpClient = &http.Client{Transport: newOptimizedTransport()}
+	}
+}
+
+// newOptimizedTransport returns an HTTP transport tuned for streaming requests.
+func newOptimizedTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   32,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		DisableCompression:    true,
+	}
+}
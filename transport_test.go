@@ -0,0 +1,84 @@
+package gopenrouter
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithOptimizedTransport(t *testing.T) {
+	client := New("test-api-key", WithHTTPClient(&http.Client{}), WithOptimizedTransport())
+
+	httpClient, ok := client.httpClient.(*http.Client)
+	if !ok {
+		t.Fatalf("expected *http.Client, got %T", client.httpClient)
+	}
+	if httpClient.Timeout != 0 {
+		t.Errorf("expected no client timeout, got %v", httpClient.Timeout)
+	}
+
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", httpClient.Transport)
+	}
+	if transport.ResponseHeaderTimeout != 0 {
+		t.Errorf("expected no response header timeout, got %v", transport.ResponseHeaderTimeout)
+	}
+	if !transport.DisableCompression {
+		t.Error("expected transparent compression to be disabled")
+	}
+	if !transport.ForceAttemptHTTP2 {
+		t.Error("expected HTTP/2 to be attempted")
+	}
+	if transport.MaxIdleConnsPerHost <= http.DefaultMaxIdleConnsPerHost {
+		t.Errorf("expected more than %d idle connections per host, got %d", http.DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	}
+
+	t.Run("StreamsAreNotCompressed", func(t *testing.T) {
+		var acceptEncoding string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			acceptEncoding = r.Header.Get("Accept-Encoding")
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithOptimizedTransport())
+		stream, err := client.ChatCompletionStream(context.Background(), ChatCompletionRequest{Model: "test-model"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer stream.Close()
+
+		if acceptEncoding != "" {
+			t.Errorf("expected no Accept-Encoding header, got %q", acceptEncoding)
+		}
+	})
+
+	t.Run("ResponsesAreCompressed", func(t *testing.T) {
+		var header string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header.Get("Accept-Encoding")
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			_, _ = gz.Write([]byte(`{"data":{"total_credits":10,"total_usage":1}}`))
+			_ = gz.Close()
+		}))
+		defer server.Close()
+
+		client := New("test-api-key", WithBaseURL(server.URL), WithOptimizedTransport())
+		credits, err := client.GetCredits(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if header != acceptEncoding {
+			t.Errorf("expected Accept-Encoding %q, got %q", acceptEncoding, header)
+		}
+		if credits.TotalCredits != 10 {
+			t.Errorf("expected total credits 10, got %f", credits.TotalCredits)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package gopenrouter
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// usageLabelContextKey is the context key under which the usage label of requests is stored.
+type usageLabelContextKey struct{}
+
+// ContextWithUsageLabel returns a context that makes the usage of requests sent with it
+// be grouped under the given label by a UsageTracker, for example to attribute spending
+// to the users or tenants of a service.
+//
+// Example usage:
+//
+//	ctx = gopenrouter.ContextWithUsageLabel(ctx, tenant.ID)
+//	response, err := client.ChatCompletion(ctx, *request)
+func ContextWithUsageLabel(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, usageLabelContextKey{}, label)
+}
+
+// UsageKey identifies a group of requests whose usage is summed by a UsageTracker.
+type UsageKey struct {
+	// Model is the model that generated the completions
+	Model string `json:"model"`
+	// Provider is the provider that served the requests, if reported
+	Provider string `json:"provider,omitempty"`
+	// Label is the label set with ContextWithUsageLabel, if any
+	Label string `json:"label,omitempty"`
+}
+
+// UsageTotals contains the summed usage of a group of requests.
+type UsageTotals struct {
+	// Requests is the number of requests that reported usage
+	Requests int `json:"requests"`
+	// PromptTokens is the number of prompt tokens processed
+	PromptTokens int `json:"prompt_tokens"`
+	// CompletionTokens is the number of completion tokens generated
+	CompletionTokens int `json:"completion_tokens"`
+	// ReasoningTokens is the number of completion tokens used for reasoning
+	ReasoningTokens int `json:"reasoning_tokens"`
+	// CachedTokens is the number of prompt tokens read from the cache
+	CachedTokens int `json:"cached_tokens"`
+	// Cost is the amount of credits charged, reported when usage accounting is enabled
+	Cost float64 `json:"cost"`
+}
+
+// add adds the usage of a request to the totals.
+func (t *UsageTotals) add(usage Usage) {
+	t.Requests++
+	t.PromptTokens += usage.PromptTokens
+	t.CompletionTokens += usage.CompletionTokens
+	if usage.CompletionTokensDetails != nil {
+		t.ReasoningTokens += usage.CompletionTokensDetails.ReasoningTokens
+	}
+	if usage.PromptTokensDetails != nil {
+		t.CachedTokens += usage.PromptTokensDetails.CachedTokens
+	}
+	t.Cost += usage.Cost
+}
+
+// merge adds other totals to the totals.
+func (t *UsageTotals) merge(other UsageTotals) {
+	t.Requests += other.Requests
+	t.PromptTokens += other.PromptTokens
+	t.CompletionTokens += other.CompletionTokens
+	t.ReasoningTokens += other.ReasoningTokens
+	t.CachedTokens += other.CachedTokens
+	t.Cost += other.Cost
+}
+
+// UsageRecord contains the summed usage of the requests of a group.
+type UsageRecord struct {
+	UsageKey
+	UsageTotals
+}
+
+// UsageTracker sums the token usage and cost of requests, grouped by model, provider,
+// and the label set with ContextWithUsageLabel. Install it on a client with
+// WithUsageTracker, or record usage manually with Record.
+//
+// A UsageTracker is safe for concurrent use.
+type UsageTracker struct {
+	mu     sync.Mutex
+	totals map[UsageKey]*UsageTotals
+}
+
+// NewUsageTracker creates an empty usage tracker.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{totals: make(map[UsageKey]*UsageTotals)}
+}
+
+// WithUsageTracker records the usage of the completions and streams of the client in
+// the given tracker. Streams report usage only if usage is included in their last chunk.
+func WithUsageTracker(tracker *UsageTracker) Option {
+	return func(c *Client) {
+		c.usageTracker = tracker
+	}
+}
+
+// Record adds the usage of a request to the totals of its group.
+func (t *UsageTracker) Record(key UsageKey, usage Usage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	totals, ok := t.totals[key]
+	if !ok {
+		totals = &UsageTotals{}
+		t.totals[key] = totals
+	}
+	totals.add(usage)
+}
+
+// Snapshot returns the totals of each group, sorted by model, provider, and label.
+func (t *UsageTracker) Snapshot() []UsageRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.records()
+}
+
+// Reset clears the totals, returning those accumulated until then. This allows totals
+// to be exported periodically without counting usage twice.
+func (t *UsageTracker) Reset() []UsageRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	records := t.records()
+	clear(t.totals)
+	return records
+}
+
+// Total returns the totals of all groups combined.
+func (t *UsageTracker) Total() UsageTotals {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var total UsageTotals
+	for _, totals := range t.totals {
+		total.merge(*totals)
+	}
+	return total
+}
+
+// records returns the totals of each group, sorted. It must be called with t.mu held.
+func (t *UsageTracker) records() []UsageRecord {
+	records := make([]UsageRecord, 0, len(t.totals))
+	for key, totals := range t.totals {
+		records = append(records, UsageRecord{UsageKey: key, UsageTotals: *totals})
+	}
+	slices.SortFunc(records, func(a, b UsageRecord) int {
+		return cmp.Or(
+			cmp.Compare(a.Model, b.Model),
+			cmp.Compare(a.Provider, b.Provider),
+			cmp.Compare(a.Label, b.Label),
+		)
+	})
+	return records
+}
+
+// WriteCSV writes the totals of each group to w in CSV format, with a header row.
+func (t *UsageTracker) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{
+		"model", "provider", "label", "requests", "prompt_tokens", "completion_tokens",
+		"reasoning_tokens", "cached_tokens", "cost",
+	})
+	for _, r := range t.Snapshot() {
+		_ = writer.Write([]string{
+			r.Model,
+			r.Provider,
+			r.Label,
+			strconv.Itoa(r.Requests),
+			strconv.Itoa(r.PromptTokens),
+			strconv.Itoa(r.CompletionTokens),
+			strconv.Itoa(r.ReasoningTokens),
+			strconv.Itoa(r.CachedTokens),
+			strconv.FormatFloat(r.Cost, 'f', -1, 64),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// trackUsage records the usage of a request in the tracker set with WithUsageTracker.
+func (c *Client) trackUsage(ctx context.Context, model, provider string, usage Usage) {
+	label, _ := ctx.Value(usageLabelContextKey{}).(string)
+	c.usageTracker.Record(UsageKey{Model: model, Provider: provider, Label: label}, usage)
+}
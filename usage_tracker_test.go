@@ -0,0 +1,127 @@
+package gopenrouter_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+func TestUsageTracker(t *testing.T) {
+	usage := gopenrouter.Usage{
+		PromptTokens:            10,
+		CompletionTokens:        5,
+		TotalTokens:             15,
+		PromptTokensDetails:     &gopenrouter.PromptTokensDetails{CachedTokens: 4},
+		CompletionTokensDetails: &gopenrouter.CompletionTokensDetails{ReasoningTokens: 2},
+		Cost:                    0.25,
+	}
+
+	t.Run("Concurrent", func(t *testing.T) {
+		tracker := gopenrouter.NewUsageTracker()
+		keys := []gopenrouter.UsageKey{
+			{Model: "openai/gpt-4o", Provider: "OpenAI", Label: "tenant-b"},
+			{Model: "openai/gpt-4o", Provider: "OpenAI", Label: "tenant-a"},
+			{Model: "anthropic/claude-3.5-sonnet", Provider: "Anthropic"},
+		}
+
+		var wg sync.WaitGroup
+		for range 50 {
+			for _, key := range keys {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					tracker.Record(key, usage)
+				}()
+			}
+		}
+		wg.Wait()
+
+		records := tracker.Snapshot()
+		if len(records) != 3 {
+			t.Fatalf("Expected 3 records, got %d", len(records))
+		}
+		expectedKeys := []gopenrouter.UsageKey{keys[2], keys[1], keys[0]}
+		for i, record := range records {
+			if record.UsageKey != expectedKeys[i] {
+				t.Errorf("Expected record %d to have key %+v, got %+v", i, expectedKeys[i], record.UsageKey)
+			}
+		}
+		expected := gopenrouter.UsageTotals{
+			Requests:         50,
+			PromptTokens:     500,
+			CompletionTokens: 250,
+			ReasoningTokens:  100,
+			CachedTokens:     200,
+			Cost:             12.5,
+		}
+		if records[0].UsageTotals != expected {
+			t.Errorf("Expected totals %+v, got %+v", expected, records[0].UsageTotals)
+		}
+		if total := tracker.Total(); total.Requests != 150 || total.Cost != 37.5 {
+			t.Errorf("Expected 150 requests costing 37.5, got %+v", total)
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		tracker := gopenrouter.NewUsageTracker()
+		tracker.Record(gopenrouter.UsageKey{Model: "openai/gpt-4o"}, usage)
+
+		if records := tracker.Reset(); len(records) != 1 || records[0].Requests != 1 {
+			t.Errorf("Expected 1 record with 1 request, got %+v", records)
+		}
+		if records := tracker.Snapshot(); len(records) != 0 {
+			t.Errorf("Expected no records after reset, got %+v", records)
+		}
+	})
+
+	t.Run("WriteCSV", func(t *testing.T) {
+		tracker := gopenrouter.NewUsageTracker()
+		tracker.Record(gopenrouter.UsageKey{Model: "openai/gpt-4o", Provider: "OpenAI", Label: "tenant-a"}, usage)
+
+		var buf bytes.Buffer
+		if err := tracker.WriteCSV(&buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := "model,provider,label,requests,prompt_tokens,completion_tokens,reasoning_tokens,cached_tokens,cost\n" +
+			"openai/gpt-4o,OpenAI,tenant-a,1,10,5,2,4,0.25\n"
+		if buf.String() != expected {
+			t.Errorf("Expected CSV %q, got %q", expected, buf.String())
+		}
+	})
+
+	t.Run("WithUsageTracker", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"id":"gen-1","provider":"OpenAI","model":"openai/gpt-4o",`+
+				`"choices":[{"message":{"role":"assistant","content":"Hi"}}],`+
+				`"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15,"cost":0.25}}`)
+		}))
+		defer server.Close()
+
+		tracker := gopenrouter.NewUsageTracker()
+		client := gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL), gopenrouter.WithUsageTracker(tracker))
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}).Build()
+
+		ctx := gopenrouter.ContextWithUsageLabel(context.Background(), "tenant-a")
+		for range 2 {
+			if _, err := client.ChatCompletion(ctx, *request); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		expected := []gopenrouter.UsageRecord{{
+			UsageKey:    gopenrouter.UsageKey{Model: "openai/gpt-4o", Provider: "OpenAI", Label: "tenant-a"},
+			UsageTotals: gopenrouter.UsageTotals{Requests: 2, PromptTokens: 20, CompletionTokens: 10, Cost: 0.5},
+		}}
+		if records := tracker.Snapshot(); !reflect.DeepEqual(records, expected) {
+			t.Errorf("Expected records %+v, got %+v", expected, records)
+		}
+	})
+}
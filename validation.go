@@ -0,0 +1,142 @@
+package gopenrouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// modelParameters are the request fields whose support OpenRouter reports in the
+// supported parameters of models. Other fields, such as the messages or the provider
+// preferences, are supported by all models.
+var modelParameters = []string{
+	"frequency_penalty",
+	"include_reasoning",
+	"logit_bias",
+	"logprobs",
+	"max_tokens",
+	"min_p",
+	"parallel_tool_calls",
+	"presence_penalty",
+	"reasoning",
+	"repetition_penalty",
+	"response_format",
+	"seed",
+	"stop",
+	"structured_outputs",
+	"temperature",
+	"tool_choice",
+	"tools",
+	"top_a",
+	"top_k",
+	"top_logprobs",
+	"top_p",
+	"web_search_options",
+}
+
+// ValidationMode determines how requests using parameters unsupported by their model are handled.
+type ValidationMode int
+
+const (
+	// ValidationWarn logs requests using unsupported parameters with the logger set with
+	// WithLogger, at warn level, and sends them anyway
+	ValidationWarn ValidationMode = iota
+	// ValidationStrict rejects requests using unsupported parameters with an
+	// *UnsupportedParametersError before they are sent
+	ValidationStrict
+)
+
+// UnsupportedParametersError reports request parameters that the model does not support.
+type UnsupportedParametersError struct {
+	// Model is the model the request is sent to
+	Model string
+	// Parameters lists the unsupported parameters set in the request
+	Parameters []string
+}
+
+func (e *UnsupportedParametersError) Error() string {
+	return fmt.Sprintf("model %s does not support the parameters: %s", e.Model, strings.Join(e.Parameters, ", "))
+}
+
+// parameterValidation holds the configuration of the pre-flight parameter validation.
+type parameterValidation struct {
+	catalog *ModelCatalog
+	mode    ValidationMode
+}
+
+// WithParameterValidation enables the validation of completion and chat completion
+// requests against the supported parameters of their model in the catalog, before they
+// are sent. This avoids spending credits on requests whose parameters would be ignored
+// or rejected, such as tools sent to a model without tool support.
+//
+// Requests to models missing from the catalog or without reported supported parameters
+// are not validated, and requests are sent if the catalog cannot be retrieved. Body
+// fields set with WithCallBodyField are not validated.
+func WithParameterValidation(catalog *ModelCatalog, mode ValidationMode) Option {
+	return func(c *Client) {
+		c.validation = &parameterValidation{catalog: catalog, mode: mode}
+	}
+}
+
+// ValidateParameters checks the parameters set in a completion or chat completion
+// request against the supported parameters of the given model. It returns an
+// *UnsupportedParametersError listing the parameters the model does not support, or
+// ErrModelNotFound if the catalog has no such model. Models without reported supported
+// parameters are assumed to support all of them.
+func (m *ModelCatalog) ValidateParameters(ctx context.Context, model string, request any) error {
+	data, err := m.ByID(ctx, model)
+	if err != nil {
+		return err
+	}
+	if len(data.SupportedParameters) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+
+	var unsupported []string
+	for _, param := range modelParameters {
+		if _, ok := fields[param]; ok && !slices.Contains(data.SupportedParameters, param) {
+			unsupported = append(unsupported, param)
+		}
+	}
+	if len(unsupported) > 0 {
+		return &UnsupportedParametersError{Model: model, Parameters: unsupported}
+	}
+	return nil
+}
+
+// validateParameters applies the parameter validation configured with
+// WithParameterValidation to a request, returning an error if it must not be sent.
+func (c *Client) validateParameters(ctx context.Context, model string, request any) error {
+	if c.validation == nil {
+		return nil
+	}
+
+	err := c.validation.catalog.ValidateParameters(ctx, model, request)
+	var unsupported *UnsupportedParametersError
+	if !errors.As(err, &unsupported) {
+		return nil
+	}
+	if c.validation.mode == ValidationStrict {
+		return err
+	}
+	if c.log.enabled(ctx, slog.LevelWarn) {
+		c.log.logger.LogAttrs(ctx, slog.LevelWarn, "openrouter unsupported parameters",
+			slog.String("model", unsupported.Model),
+			slog.Any("parameters", unsupported.Parameters),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package gopenrouter_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bkovacki/gopenrouter"
+)
+
+// newValidationServer serves a list of models and counts the completion requests.
+func newValidationServer(t *testing.T, completions *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/models":
+			_, _ = fmt.Fprint(w, `{"data": [
+				{"id": "openai/gpt-4o", "supported_parameters": ["temperature", "top_p", "tools", "logprobs"]},
+				{"id": "unknown/params"}
+			]}`)
+		case "/chat/completions":
+			completions.Add(1)
+			_, _ = fmt.Fprint(w, `{"id": "gen-1", "choices": [{"message": {"role": "assistant", "content": "Hi"}}]}`)
+		case "/completions":
+			completions.Add(1)
+			_, _ = fmt.Fprint(w, `{"id": "gen-1", "choices": [{"text": "Hi"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 404, "message": "Not found"}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestValidateParameters(t *testing.T) {
+	ctx := context.Background()
+	var completions atomic.Int32
+	server := newValidationServer(t, &completions)
+	catalog := gopenrouter.NewModelCatalog(gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)))
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	t.Run("Supported", func(t *testing.T) {
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).
+			WithTemperature(0.5).
+			WithLogprobs(true).
+			Build()
+		if err := catalog.ValidateParameters(ctx, request.Model, request); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).
+			WithTemperature(0.5).
+			WithTopK(40).
+			WithSeed(1).
+			Build()
+		err := catalog.ValidateParameters(ctx, request.Model, request)
+		var unsupported *gopenrouter.UnsupportedParametersError
+		if !errors.As(err, &unsupported) {
+			t.Fatalf("Expected UnsupportedParametersError, got %v", err)
+		}
+		if unsupported.Model != "openai/gpt-4o" {
+			t.Errorf("Expected model openai/gpt-4o, got %s", unsupported.Model)
+		}
+		if expected := []string{"seed", "top_k"}; !reflect.DeepEqual(unsupported.Parameters, expected) {
+			t.Errorf("Expected parameters %v, got %v", expected, unsupported.Parameters)
+		}
+		if !strings.Contains(err.Error(), "seed, top_k") {
+			t.Errorf("Expected error to list the parameters, got %q", err.Error())
+		}
+	})
+
+	t.Run("CompletionRequest", func(t *testing.T) {
+		request := gopenrouter.NewCompletionRequestBuilder("openai/gpt-4o", "Hello").
+			WithMinP(0.1).
+			Build()
+		var unsupported *gopenrouter.UnsupportedParametersError
+		if err := catalog.ValidateParameters(ctx, request.Model, request); !errors.As(err, &unsupported) {
+			t.Fatalf("Expected UnsupportedParametersError, got %v", err)
+		}
+	})
+
+	t.Run("UnknownSupportedParameters", func(t *testing.T) {
+		request := gopenrouter.NewChatCompletionRequestBuilder("unknown/params", messages).WithTopK(40).Build()
+		if err := catalog.ValidateParameters(ctx, request.Model, request); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("ModelNotFound", func(t *testing.T) {
+		request := gopenrouter.NewChatCompletionRequestBuilder("missing/model", messages).Build()
+		if err := catalog.ValidateParameters(ctx, request.Model, request); !errors.Is(err, gopenrouter.ErrModelNotFound) {
+			t.Errorf("Expected ErrModelNotFound, got %v", err)
+		}
+	})
+}
+
+func TestWithParameterValidation(t *testing.T) {
+	ctx := context.Background()
+	messages := []gopenrouter.ChatMessage{{Role: "user", Content: "Hello"}}
+
+	t.Run("Strict", func(t *testing.T) {
+		var completions atomic.Int32
+		server := newValidationServer(t, &completions)
+		catalog := gopenrouter.NewModelCatalog(gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)))
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithParameterValidation(catalog, gopenrouter.ValidationStrict),
+		)
+
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).WithTopK(40).Build()
+		var unsupported *gopenrouter.UnsupportedParametersError
+		if _, err := client.ChatCompletion(ctx, *request); !errors.As(err, &unsupported) {
+			t.Errorf("Expected UnsupportedParametersError from ChatCompletion, got %v", err)
+		}
+		if _, err := client.ChatCompletionStream(ctx, *request); !errors.As(err, &unsupported) {
+			t.Errorf("Expected UnsupportedParametersError from ChatCompletionStream, got %v", err)
+		}
+		completion := gopenrouter.NewCompletionRequestBuilder("openai/gpt-4o", "Hello").WithTopK(40).Build()
+		if _, err := client.Completion(ctx, *completion); !errors.As(err, &unsupported) {
+			t.Errorf("Expected UnsupportedParametersError from Completion, got %v", err)
+		}
+		if _, err := client.CompletionStream(ctx, *completion); !errors.As(err, &unsupported) {
+			t.Errorf("Expected UnsupportedParametersError from CompletionStream, got %v", err)
+		}
+		if n := completions.Load(); n != 0 {
+			t.Errorf("Expected no completion requests, got %d", n)
+		}
+
+		supported := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).WithTemperature(0.5).Build()
+		if _, err := client.ChatCompletion(ctx, *supported); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		unlisted := gopenrouter.NewChatCompletionRequestBuilder("openrouter/auto", messages).WithTopK(40).Build()
+		if _, err := client.ChatCompletion(ctx, *unlisted); err != nil {
+			t.Errorf("Expected no error for a model missing from the catalog, got %v", err)
+		}
+		if n := completions.Load(); n != 2 {
+			t.Errorf("Expected 2 completion requests, got %d", n)
+		}
+	})
+
+	t.Run("Warn", func(t *testing.T) {
+		var completions atomic.Int32
+		server := newValidationServer(t, &completions)
+		catalog := gopenrouter.NewModelCatalog(gopenrouter.New("test-key", gopenrouter.WithBaseURL(server.URL)))
+		var buf bytes.Buffer
+		client := gopenrouter.New("test-key",
+			gopenrouter.WithBaseURL(server.URL),
+			gopenrouter.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+			gopenrouter.WithParameterValidation(catalog, gopenrouter.ValidationWarn),
+		)
+
+		request := gopenrouter.NewChatCompletionRequestBuilder("openai/gpt-4o", messages).WithTopK(40).Build()
+		if _, err := client.ChatCompletion(ctx, *request); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if n := completions.Load(); n != 1 {
+			t.Errorf("Expected 1 completion request, got %d", n)
+		}
+
+		records := parseLogRecords(t, &buf)
+		if len(records) != 1 {
+			t.Fatalf("Expected 1 log record, got %d", len(records))
+		}
+		if records[0]["msg"] != "openrouter unsupported parameters" || records[0]["level"] != "WARN" {
+			t.Errorf("Expected unsupported parameters warning, got %v", records[0])
+		}
+		if params, _ := records[0]["parameters"].([]any); len(params) != 1 || params[0] != "top_k" {
+			t.Errorf("Expected parameters [top_k], got %v", records[0]["parameters"])
+		}
+	})
+}